@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
+)
+
+// invokeOptions are the flags of the invoke subcommand.
+type invokeOptions struct {
+	format      string
+	params      string
+	param       []string
+	claims      string
+	accessToken string
+	yes         bool
+}
+
+func newInvokeCommand(root *Command) *cobra.Command {
+	var opts invokeOptions
+	c := &cobra.Command{
+		Use:   "invoke TOOL",
+		Short: "Invoke a tool locally without starting a server",
+		Long: "Invoke loads the tools file, initializes only the source used by the tool, " +
+			"invokes the tool once with the given parameters and prints the result.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runInvoke(c, root, args[0], opts)
+		},
+	}
+	flags := c.Flags()
+	flags.StringVar(&opts.format, "format", formatJSON, "Output format. Allowed: 'json', 'table' or 'csv'.")
+	flags.StringVar(&opts.params, "params", "", "JSON object of parameter values, or '-' to read it from stdin.")
+	flags.StringArrayVar(&opts.param, "param", nil, "Parameter value as NAME=VALUE. VALUE is parsed as JSON if possible, and as a string otherwise. Overrides --params.")
+	flags.StringVar(&opts.claims, "claims", "", "JSON object mapping auth service names to the claims used for authenticated parameters.")
+	flags.StringVar(&opts.accessToken, "access-token", "", "Access token passed to tools that require client authorization.")
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "Confirm the invocation of tools that require confirmation.")
+	return c
+}
+
+func runInvoke(c *cobra.Command, root *Command, toolName string, opts invokeOptions) error {
+	format := strings.ToLower(opts.format)
+	if format != formatJSON && format != formatTable && format != formatCSV {
+		return fmt.Errorf("output format invalid: %q", opts.format)
+	}
+	data, err := invokeParams(c.InOrStdin(), opts.params, opts.param)
+	if err != nil {
+		return err
+	}
+	claims := make(map[string]map[string]any)
+	if opts.claims != "" {
+		if err := json.Unmarshal([]byte(opts.claims), &claims); err != nil {
+			return fmt.Errorf("--claims is not a valid JSON object: %w", err)
+		}
+	}
+
+	ctx, err := subcommandContext(c, root)
+	if err != nil {
+		return err
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		return fmt.Errorf("unable to create telemetry instrumentation: %w", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+	ctx = util.WithUserAgent(ctx, root.cfg.Version)
+
+	toolsFile, err := loadToolsFile(ctx, root)
+	if err != nil {
+		return err
+	}
+	// Only the sources used by the tool, and by the tools it invokes, are
+	// initialized.
+	srcs := make(map[string]sources.Source)
+	defer func() {
+		for name, src := range srcs {
+			if err := sources.Close(ctx, src); err != nil {
+				root.logger.WarnContext(ctx, fmt.Sprintf("unable to close source %q: %s", name, err))
+			}
+		}
+	}()
+	tool, err := server.InitializeTool(ctx, toolName, toolsFile.Tools, toolsFile.Sources, srcs, make(map[string]tools.Tool))
+	if err != nil {
+		return err
+	}
+
+	accessToken := tools.AccessToken(opts.accessToken)
+	if tool.RequiresClientAuthorization() && accessToken == "" {
+		return fmt.Errorf("tool %q requires client authorization, use --access-token", toolName)
+	}
+	params, err := tool.ParseParams(data, claims)
+	if err != nil {
+		return fmt.Errorf("provided parameters were invalid: %w", err)
+	}
+	ctx = util.WithClaims(ctx, claims)
+	ctx = confirmation.WithConfirmer(ctx, invokeConfirmer(opts.yes))
+	res, err := tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return fmt.Errorf("error while invoking tool %q: %w", toolName, err)
+	}
+	return writeResult(c.OutOrStdout(), c.ErrOrStderr(), format, res)
+}
+
+// invokeParams returns the parameter values given by the --params and --param
+// flags of the invoke subcommand.
+func invokeParams(stdin io.Reader, params string, param []string) (map[string]any, error) {
+	data := make(map[string]any)
+	if params == "-" {
+		if err := util.DecodeJSON(stdin, &data); err != nil {
+			return nil, fmt.Errorf("unable to read parameters from stdin: %w", err)
+		}
+	} else if params != "" {
+		if err := util.DecodeJSON(strings.NewReader(params), &data); err != nil {
+			return nil, fmt.Errorf("--params is not a valid JSON object: %w", err)
+		}
+	}
+	for _, p := range param {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("--param %q must be formatted as NAME=VALUE", p)
+		}
+		var v any = value
+		if json.Valid([]byte(value)) {
+			if err := util.DecodeJSON(strings.NewReader(value), &v); err != nil {
+				return nil, fmt.Errorf("--param %q has an invalid value: %w", p, err)
+			}
+		}
+		data[name] = v
+	}
+	return data, nil
+}
+
+// writeResult writes the result of a tool in format. The token of the next
+// page of paged results is written to errW.
+func writeResult(w, errW io.Writer, format string, res any) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	if p, ok := res.(tools.PagedResult); ok {
+		res = p.Rows
+		if p.NextPageToken != "" {
+			defer fmt.Fprintf(errW, "next page token: %s\n", p.NextPageToken)
+		}
+	}
+	columns, rows, err := resultTable(res)
+	if err != nil {
+		return fmt.Errorf("unable to format result as %s: %w", format, err)
+	}
+	if format == formatCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		return cw.WriteAll(rows)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// resultTable converts a result made of rows of columns to the sorted column
+// names and the formatted values of each row.
+func resultTable(res any) ([]string, [][]string, error) {
+	var records []map[string]any
+	switch r := res.(type) {
+	case nil:
+	case []map[string]any:
+		records = r
+	case []any:
+		for _, row := range r {
+			m, ok := row.(map[string]any)
+			if !ok {
+				return nil, nil, fmt.Errorf("row of type %T is not a set of columns", row)
+			}
+			records = append(records, m)
+		}
+	case map[string]any:
+		records = []map[string]any{r}
+	default:
+		return nil, nil, fmt.Errorf("result of type %T is not a list of rows", res)
+	}
+
+	set := make(map[string]bool)
+	for _, m := range records {
+		for k := range m {
+			set[k] = true
+		}
+	}
+	columns := slices.Sorted(maps.Keys(set))
+	rows := make([][]string, 0, len(records))
+	for _, m := range records {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = formatValue(m[col])
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+// formatValue formats a column value for table and CSV output.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// invokeConfirmer returns the Confirmer of the invoke subcommand, which
+// confirms invocations if --yes is set.
+func invokeConfirmer(yes bool) confirmation.Confirmer {
+	if yes {
+		return confirmation.AutoConfirm
+	}
+	return confirmation.ConfirmerFunc(func(_ context.Context, req confirmation.Request) error {
+		return fmt.Errorf("%s\ntool %q requires confirmation, use --yes to confirm", req.Message, req.Tool)
+	})
+}
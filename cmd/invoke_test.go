@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// writeInvokeToolsFile writes a tools file with an http tool calling the
+// server at url, and returns its path.
+func writeInvokeToolsFile(t *testing.T, url string) string {
+	t.Helper()
+	in := fmt.Sprintf(`
+sources:
+  my-http-instance:
+    kind: http
+    baseUrl: %s
+  unused-http-instance:
+    kind: http
+    baseUrl: http://127.0.0.1:1
+authServices:
+  my-google-auth:
+    kind: google
+    clientId: my-client-id
+tools:
+  search-pets:
+    kind: http
+    source: my-http-instance
+    method: GET
+    path: /pets
+    description: Search pets by name.
+    queryParams:
+      - name: name
+        type: string
+        description: The name of the pet.
+      - name: limit
+        type: integer
+        description: The maximum number of pets.
+        default: 10
+  owner-pets:
+    kind: http
+    source: my-http-instance
+    method: GET
+    path: /pets
+    description: List the pets of the caller.
+    authRequired: [my-google-auth]
+    queryParams:
+      - name: owner
+        type: string
+        description: The owner.
+        authServices:
+          - name: my-google-auth
+            field: email
+toolsets:
+  pets: [search-pets]
+`, url)
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, []byte(in), 0o600); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+	return path
+}
+
+func TestInvoke(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		name, limit := q.Get("name")+q.Get("owner"), q.Get("limit")
+		if limit == "" {
+			limit = "null"
+		}
+		fmt.Fprintf(w, `[{"name": %q, "limit": %s, "tags": ["a", "b"]}]`, name, limit)
+	}))
+	defer srv.Close()
+	path := writeInvokeToolsFile(t, srv.URL)
+
+	tcs := []struct {
+		desc    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			desc: "json",
+			args: []string{"search-pets", "--params", `{"name": "Rex"}`},
+			want: "[\n  {\n    \"limit\": 10,\n    \"name\": \"Rex\",\n    \"tags\": [\n      \"a\",\n      \"b\"\n    ]\n  }\n]\n",
+		},
+		{
+			desc: "csv",
+			args: []string{"search-pets", "--param", "name=Rex", "--param", "limit=2", "--format", "csv"},
+			want: "limit,name,tags\n2,Rex,\"[\"\"a\"\",\"\"b\"\"]\"\n",
+		},
+		{
+			desc: "table",
+			args: []string{"search-pets", "--params", `{"name": "Rex", "limit": 5}`, "--param", "limit=3", "--format", "table"},
+			want: "limit  name  tags\n3      Rex   [\"a\",\"b\"]\n",
+		},
+		{
+			desc: "claims",
+			args: []string{"owner-pets", "--claims", `{"my-google-auth": {"email": "rex@example.com"}}`, "--format", "csv"},
+			want: "limit,name,tags\n,rex@example.com,\"[\"\"a\"\",\"\"b\"\"]\"\n",
+		},
+		{
+			desc:    "invalid parameters",
+			args:    []string{"search-pets", "--param", "limit=many"},
+			wantErr: "provided parameters were invalid",
+		},
+		{
+			desc:    "missing claims",
+			args:    []string{"owner-pets"},
+			wantErr: "provided parameters were invalid",
+		},
+		{
+			desc:    "unknown tool",
+			args:    []string{"missing-tool"},
+			wantErr: `tool "missing-tool" does not exist`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			args := append([]string{"invoke", "--tools-file", path}, tc.args...)
+			_, out, err := invokeCommand(args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, out); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInvokeParams(t *testing.T) {
+	got, err := invokeParams(strings.NewReader(`{"name": "Rex", "limit": 5}`), "-", []string{"limit=3", "tag=42abc", `quoted="42"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{"name": "Rex", "limit": json.Number("3"), "tag": "42abc", "quoted": "42"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected params (-want +got):\n%s", diff)
+	}
+
+	if _, err := invokeParams(nil, "", []string{"limit"}); err == nil {
+		t.Errorf("expected error for a parameter without a value")
+	}
+}
+
+func TestList(t *testing.T) {
+	path := writeInvokeToolsFile(t, "http://127.0.0.1:1")
+
+	_, out, err := invokeCommand([]string{"list", "--tools-file", path, "--format", "json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got listing
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unable to parse output %q: %s", out, err)
+	}
+	want := listing{
+		Toolsets: map[string][]string{"pets": {"search-pets"}},
+		Tools: map[string]tools.Manifest{
+			"search-pets": {
+				Description: "Search pets by name.",
+				Parameters: []tools.ParameterManifest{
+					{Name: "name", Type: "string", Required: true, Description: "The name of the pet.", AuthServices: []string{}},
+					{Name: "limit", Type: "integer", Required: false, Description: "The maximum number of pets.", AuthServices: []string{}},
+				},
+				AuthRequired: []string{},
+			},
+			"owner-pets": {
+				Description: "List the pets of the caller.",
+				Parameters: []tools.ParameterManifest{
+					{Name: "owner", Type: "string", Required: true, Description: "The owner.", AuthServices: []string{"my-google-auth"}},
+				},
+				AuthRequired: []string{"my-google-auth"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected listing (-want +got):\n%s", diff)
+	}
+
+	_, out, err = invokeCommand([]string{"list", "--tools-file", path, "--toolset", "pets"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantText := `Toolsets:
+  pets: search-pets
+
+Tools:
+  search-pets
+    Search pets by name.
+    - name (string, required): The name of the pet.
+    - limit (integer): The maximum number of pets.
+`
+	if diff := cmp.Diff(wantText, out); diff != "" {
+		t.Errorf("unexpected text listing (-want +got):\n%s", diff)
+	}
+}
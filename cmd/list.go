@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/spf13/cobra"
+)
+
+// listing is the output of the list subcommand.
+type listing struct {
+	Toolsets map[string][]string       `json:"toolsets"`
+	Tools    map[string]tools.Manifest `json:"tools"`
+}
+
+func newListCommand(root *Command) *cobra.Command {
+	var format, toolset string
+	c := &cobra.Command{
+		Use:   "list",
+		Short: "List the toolsets, tools and parameters of a tools file",
+		Long: "List prints the toolsets of the tools file and the description, parameters " +
+			"and required auth services of each tool, without connecting to any source.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			return runList(c, root, format, toolset)
+		},
+	}
+	c.Flags().StringVar(&format, "format", formatText, "Output format. Allowed: 'text' or 'json'.")
+	c.Flags().StringVar(&toolset, "toolset", "", "Only list the tools of this toolset.")
+	return c
+}
+
+func runList(c *cobra.Command, root *Command, format, toolset string) error {
+	format = strings.ToLower(format)
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("output format invalid: %q", format)
+	}
+	ctx, err := subcommandContext(c, root)
+	if err != nil {
+		return err
+	}
+	toolsFile, err := loadToolsFile(ctx, root)
+	if err != nil {
+		return err
+	}
+	l, err := listToolsFile(toolsFile, toolset)
+	if err != nil {
+		return err
+	}
+	return writeListing(c.OutOrStdout(), format, l)
+}
+
+// listToolsFile returns the toolsets of a tools file and the manifests of its
+// tools, or only of the tools of toolset if it is not empty.
+func listToolsFile(tf ToolsFile, toolset string) (listing, error) {
+	l := listing{Toolsets: make(map[string][]string), Tools: make(map[string]tools.Manifest)}
+	names := slices.Collect(maps.Keys(tf.Tools))
+	if toolset != "" {
+		ts, ok := tf.Toolsets[toolset]
+		if !ok {
+			return listing{}, fmt.Errorf("toolset %q does not exist", toolset)
+		}
+		l.Toolsets[toolset] = ts.ToolNames
+		names = ts.ToolNames
+	} else {
+		for name, ts := range tf.Toolsets {
+			l.Toolsets[name] = ts.ToolNames
+		}
+	}
+	for _, name := range names {
+		tc, ok := tf.Tools[name]
+		if !ok {
+			return listing{}, fmt.Errorf("tool %q of toolset %q does not exist", name, toolset)
+		}
+		l.Tools[name] = toolConfigManifest(tc)
+	}
+	return l, nil
+}
+
+// toolConfigManifest builds the manifest of a tool from its configuration,
+// without initializing it.
+func toolConfigManifest(tc tools.ToolConfig) tools.Manifest {
+	desc, _ := toolConfigField(tc, "Description").(string)
+	authRequired, _ := toolConfigField(tc, "AuthRequired").([]string)
+	if authRequired == nil {
+		authRequired = make([]string, 0)
+	}
+	params := toolConfigParameters(tc).Manifest()
+	if params == nil {
+		params = make([]tools.ParameterManifest, 0)
+	}
+	return tools.Manifest{Description: desc, Parameters: params, AuthRequired: authRequired}
+}
+
+func writeListing(w io.Writer, format string, l listing) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(l)
+	}
+	if len(l.Toolsets) > 0 {
+		fmt.Fprintln(w, "Toolsets:")
+		for _, name := range slices.Sorted(maps.Keys(l.Toolsets)) {
+			fmt.Fprintf(w, "  %s: %s\n", name, strings.Join(l.Toolsets[name], ", "))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "Tools:")
+	for _, name := range slices.Sorted(maps.Keys(l.Tools)) {
+		m := l.Tools[name]
+		fmt.Fprintf(w, "  %s\n", name)
+		if m.Description != "" {
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(strings.TrimSpace(m.Description), "\n", "\n    "))
+		}
+		if len(m.AuthRequired) > 0 {
+			fmt.Fprintf(w, "    Auth required: %s\n", strings.Join(m.AuthRequired, ", "))
+		}
+		for _, p := range m.Parameters {
+			attrs := []string{p.Type}
+			if p.Required {
+				attrs = append(attrs, "required")
+			}
+			if len(p.AuthServices) > 0 {
+				attrs = append(attrs, "from "+strings.Join(p.AuthServices, ", "))
+			}
+			fmt.Fprintf(w, "    - %s (%s): %s\n", p.Name, strings.Join(attrs, ", "), p.Description)
+		}
+	}
+	return nil
+}
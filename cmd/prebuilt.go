@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// prebuiltToolsFile is a parsed prebuilt tool configuration.
+type prebuiltToolsFile struct {
+	Name string
+	ToolsFile
+}
+
+// loadPrebuiltToolsFiles loads the prebuilt tool configurations with the
+// given names.
+func loadPrebuiltToolsFiles(ctx context.Context, names []string) ([]prebuiltToolsFile, error) {
+	var prebuilts []prebuiltToolsFile
+	for _, name := range names {
+		buf, err := prebuiltconfigs.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		toolsFile, err := parseToolsFile(ctx, prebuiltOrigin(name), buf)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse prebuilt tool configuration %q: %w", name, err)
+		}
+		prebuilts = append(prebuilts, prebuiltToolsFile{Name: name, ToolsFile: toolsFile})
+	}
+	return prebuilts, nil
+}
+
+// prebuiltOrigin is the origin of the resources of a prebuilt tool
+// configuration.
+func prebuiltOrigin(name string) string {
+	return "prebuilt:" + name
+}
+
+// applyPrebuiltConfigs combines the prebuilt tool configurations with the
+// given names, and merges the user tools file on top of them. Resources of
+// the user tools file override the prebuilt resources of the same name. The
+// combination and the overrides are logged.
+func applyPrebuiltConfigs(ctx context.Context, names []string, user ToolsFile) (ToolsFile, error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return ToolsFile{}, err
+	}
+	prebuilts, err := loadPrebuiltToolsFiles(ctx, names)
+	if err != nil {
+		return ToolsFile{}, err
+	}
+	logger.InfoContext(ctx, fmt.Sprint("Using prebuilt tool configuration for ", strings.Join(names, ", ")))
+
+	combined, err := combinePrebuiltToolsFiles(prebuilts, user)
+	if err != nil {
+		return ToolsFile{}, err
+	}
+	merged, overridden := overrideToolsFile(combined, user)
+	for _, msg := range overridden {
+		logger.InfoContext(ctx, msg)
+	}
+	return merged, nil
+}
+
+// combinePrebuiltToolsFiles merges prebuilt tool configurations with
+// mergeToolsFiles. Resources defined identically by several prebuilt
+// configurations are merged, and so are resources defined by the user tools
+// file, which override them. Other resources defined by several prebuilt
+// configurations are reported as conflicts.
+func combinePrebuiltToolsFiles(prebuilts []prebuiltToolsFile, user ToolsFile) (ToolsFile, error) {
+	userAuthServices := user.AuthServices
+	if user.AuthSources != nil {
+		userAuthServices = user.AuthSources
+	}
+	files := make([]ToolsFile, len(prebuilts))
+	names := make([]string, len(prebuilts))
+	for i, p := range prebuilts {
+		files[i] = withOrigin(ToolsFile{
+			Sources: prebuiltResources(prebuilts, i, user.Sources,
+				func(tf ToolsFile) map[string]sources.SourceConfig { return tf.Sources }),
+			AuthServices: prebuiltResources(prebuilts, i, userAuthServices,
+				func(tf ToolsFile) map[string]auth.AuthServiceConfig {
+					if tf.AuthSources != nil {
+						return tf.AuthSources
+					}
+					return tf.AuthServices
+				}),
+			Tools: prebuiltResources(prebuilts, i, user.Tools,
+				func(tf ToolsFile) map[string]tools.ToolConfig { return tf.Tools }),
+			Toolsets: prebuiltResources(prebuilts, i, user.Toolsets,
+				func(tf ToolsFile) map[string]tools.ToolsetConfig { return tf.Toolsets }),
+		}, prebuiltOrigin(p.Name))
+		names[i] = fmt.Sprintf("'%s' (file #%d)", p.Name, i+1)
+	}
+	merged, err := mergeToolsFiles(files...)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to combine prebuilt tool configurations %s: %w\nDefine the conflicting resources in your tools file to override them", strings.Join(names, ", "), err)
+	}
+	return merged, nil
+}
+
+// prebuiltResources returns the resources of one kind of the prebuilt tool
+// configuration i, leaving out the resources already defined by a previous
+// prebuilt configuration, either identically or, if the resource is
+// overridden by user, in any way.
+func prebuiltResources[V any](prebuilts []prebuiltToolsFile, i int, user map[string]V, resources func(ToolsFile) map[string]V) map[string]V {
+	kept := make(map[string]V)
+	for name, v := range resources(prebuilts[i].ToolsFile) {
+		_, overridden := user[name]
+		defined := slices.ContainsFunc(prebuilts[:i], func(p prebuiltToolsFile) bool {
+			prev, ok := resources(p.ToolsFile)[name]
+			return ok && (overridden || reflect.DeepEqual(v, prev))
+		})
+		if !defined {
+			kept[name] = v
+		}
+	}
+	return kept
+}
+
+// overrideToolsFile merges override on top of base. Resources of override
+// replace the resources of the same name in base. It returns the merged
+// tools file and a message for each overridden resource.
+func overrideToolsFile(base, override ToolsFile) (ToolsFile, []string) {
+	merged := ToolsFile{
+		Sources:      maps.Clone(base.Sources),
+		AuthServices: maps.Clone(base.AuthServices),
+		Tools:        maps.Clone(base.Tools),
+		Toolsets:     maps.Clone(base.Toolsets),
+	}
+	if base.AuthSources != nil {
+		maps.Copy(merged.AuthServices, base.AuthSources)
+	}
+	authServices := override.AuthServices
+	if override.AuthSources != nil {
+		authServices = override.AuthSources
+	}
+
+	merged.Origins = mergeOrigins(base.Origins, override.Origins)
+
+	var msgs []string
+	msgs = append(msgs, overrideResources("source", merged.Sources, override.Sources)...)
+	msgs = append(msgs, overrideResources("authService", merged.AuthServices, authServices)...)
+	msgs = append(msgs, overrideResources("tool", merged.Tools, override.Tools)...)
+	msgs = append(msgs, overrideResources("toolset", merged.Toolsets, override.Toolsets)...)
+	return merged, msgs
+}
+
+func overrideResources[V any](resource string, merged, override map[string]V) []string {
+	var msgs []string
+	for _, name := range slices.Sorted(maps.Keys(override)) {
+		if _, ok := merged[name]; ok {
+			msgs = append(msgs, fmt.Sprintf("%s '%s' of the prebuilt tool configuration is overridden by the tools file", resource, name))
+		}
+		merged[name] = override[name]
+	}
+	return msgs
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func setPrebuiltEnv(t *testing.T) {
+	t.Helper()
+	for _, prefix := range []string{"HANA", "POSTGRES"} {
+		t.Setenv(prefix+"_HOST", "127.0.0.1")
+		t.Setenv(prefix+"_PORT", "1")
+		t.Setenv(prefix+"_DATABASE", "my_db")
+		t.Setenv(prefix+"_USER", "my_user")
+		t.Setenv(prefix+"_PASSWORD", "my_pass")
+	}
+	t.Setenv("SPANNER_PROJECT", "my-project")
+	t.Setenv("SPANNER_INSTANCE", "my-instance")
+	t.Setenv("SPANNER_DATABASE", "my_db")
+}
+
+func TestPrebuiltWithToolsFile(t *testing.T) {
+	setPrebuiltEnv(t)
+	in := `
+tools:
+  execute_sql:
+    kind: postgres-execute-sql
+    source: postgresql-source
+    description: Execute SQL on my application database.
+  list_tables:
+    kind: postgres-sql
+    source: postgresql-source
+    description: List the tables of my application.
+    statement: SELECT table_name FROM information_schema.tables
+  count_hotels:
+    kind: postgres-sql
+    source: postgresql-source
+    description: Count the hotels.
+    statement: SELECT count(*) FROM hotels
+toolsets:
+  my-toolset: [count_hotels, list_tables]
+`
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, []byte(in), 0o600); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+
+	args := []string{"list", "--prebuilt", "hana", "--prebuilt", "postgres,hana", "--tools-file", path, "--format", "json"}
+	c, out, err := invokeCommand(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"hana", "postgres"}, c.prebuiltConfigs); diff != "" {
+		t.Errorf("unexpected prebuilt configs (-want +got):\n%s", diff)
+	}
+	// the log messages precede the listing
+	var got listing
+	if err := json.Unmarshal([]byte(out[strings.Index(out, "{"):]), &got); err != nil {
+		t.Fatalf("unable to parse output %q: %s", out, err)
+	}
+	wantToolsets := map[string][]string{
+		"hana-database-tools": {"execute_sql", "list_tables"},
+		"my-toolset":          {"count_hotels", "list_tables"},
+	}
+	for name, want := range wantToolsets {
+		if diff := cmp.Diff(want, got.Toolsets[name]); diff != "" {
+			t.Errorf("unexpected toolset %q (-want +got):\n%s", name, diff)
+		}
+	}
+	for _, name := range []string{"execute_sql", "count_hotels", "list_active_queries"} {
+		if _, ok := got.Tools[name]; !ok {
+			t.Errorf("expected tool %q in %v", name, slices.Sorted(maps.Keys(got.Tools)))
+		}
+	}
+	for _, name := range []string{"hana_execute_sql", "postgres_execute_sql"} {
+		if _, ok := got.Tools[name]; ok {
+			t.Errorf("unexpected tool %q", name)
+		}
+	}
+	if want := "List the tables of my application."; got.Tools["list_tables"].Description != want {
+		t.Errorf("unexpected description of list_tables: got %q, want %q", got.Tools["list_tables"].Description, want)
+	}
+
+	// without the tools file, the tools defined by both prebuilt
+	// configurations conflict
+	_, _, err = invokeCommand([]string{"list", "--prebuilt", "hana,postgres"})
+	for _, want := range []string{
+		"unable to combine prebuilt tool configurations 'hana' (file #1), 'postgres' (file #2)",
+		"tool 'execute_sql' (file #2)",
+		"tool 'list_tables' (file #2)",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestCombinePrebuiltToolsFiles(t *testing.T) {
+	setPrebuiltEnv(t)
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unable to create context: %s", err)
+	}
+	prebuilts, err := loadPrebuiltToolsFiles(ctx, []string{"hana", "postgres"})
+	if err != nil {
+		t.Fatalf("unable to load prebuilt configs: %s", err)
+	}
+	user := ToolsFile{Tools: server.ToolConfigs{
+		"execute_sql": prebuilts[1].Tools["execute_sql"],
+		"list_tables": prebuilts[1].Tools["list_tables"],
+	}}
+	got, err := combinePrebuiltToolsFiles(prebuilts, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wantOrigins := map[string]string{
+		"hana-source":       "prebuilt:hana",
+		"postgresql-source": "prebuilt:postgres",
+	}
+	if diff := cmp.Diff(wantOrigins, got.Origins.Sources); diff != "" {
+		t.Errorf("unexpected source origins (-want +got):\n%s", diff)
+	}
+	// the tools overridden by the user tools file are kept once, from the
+	// first prebuilt configuration defining them
+	for name := range got.Tools {
+		want := "prebuilt:postgres"
+		if _, ok := user.Tools[name]; ok {
+			want = "prebuilt:hana"
+		}
+		if got.Origins.Tools[name] != want {
+			t.Errorf("unexpected origin of tool %q: got %q, want %q", name, got.Origins.Tools[name], want)
+		}
+	}
+	_, overridden := overrideToolsFile(got, user)
+	wantOverridden := []string{
+		"tool 'execute_sql' of the prebuilt tool configuration is overridden by the tools file",
+		"tool 'list_tables' of the prebuilt tool configuration is overridden by the tools file",
+	}
+	if diff := cmp.Diff(wantOverridden, overridden); diff != "" {
+		t.Errorf("unexpected overridden resources (-want +got):\n%s", diff)
+	}
+}
+
+func TestCombinePrebuiltToolsFilesConflicts(t *testing.T) {
+	prebuilt := func(name, clientID string) prebuiltToolsFile {
+		return prebuiltToolsFile{
+			Name: name,
+			ToolsFile: ToolsFile{
+				AuthServices: server.AuthServiceConfigs{
+					"my-google-auth": google.Config{Name: "my-google-auth", Kind: google.AuthServiceKind, ClientID: clientID},
+				},
+			},
+		}
+	}
+
+	// identical definitions are merged
+	got, err := combinePrebuiltToolsFiles([]prebuiltToolsFile{prebuilt("first", "a"), prebuilt("second", "a")}, ToolsFile{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := (server.AuthServiceConfigs{"my-google-auth": prebuilt("first", "a").AuthServices["my-google-auth"]}); !cmp.Equal(want, got.AuthServices) {
+		t.Errorf("unexpected auth services: %v", got.AuthServices)
+	}
+
+	prebuilts := []prebuiltToolsFile{prebuilt("first", "a"), prebuilt("second", "b")}
+	_, err = combinePrebuiltToolsFiles(prebuilts, ToolsFile{})
+	if err == nil || !strings.Contains(err.Error(), "authService 'my-google-auth' (file #2)") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// defining the auth service in the tools file resolves the conflict
+	user := ToolsFile{AuthServices: server.AuthServiceConfigs{
+		"my-google-auth": google.Config{Name: "my-google-auth", Kind: google.AuthServiceKind, ClientID: "c"},
+	}}
+	combined, err := combinePrebuiltToolsFiles(prebuilts, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	merged, overridden := overrideToolsFile(combined, user)
+	if want := []string{"authService 'my-google-auth' of the prebuilt tool configuration is overridden by the tools file"}; !slices.Equal(want, overridden) {
+		t.Errorf("unexpected overridden resources: %q", overridden)
+	}
+	want := server.AuthServiceConfigs{"my-google-auth": user.AuthServices["my-google-auth"]}
+	if diff := cmp.Diff(want, merged.AuthServices); diff != "" {
+		t.Errorf("unexpected auth services (-want +got):\n%s", diff)
+	}
+}
+
+func TestOverrideToolsFile(t *testing.T) {
+	base := ToolsFile{
+		AuthServices: server.AuthServiceConfigs{"my-google-auth": google.Config{Name: "my-google-auth", ClientID: "a"}},
+		Toolsets: server.ToolsetConfigs{
+			"my-toolset":    tools.ToolsetConfig{Name: "my-toolset", ToolNames: []string{"a"}},
+			"other-toolset": tools.ToolsetConfig{Name: "other-toolset", ToolNames: []string{"b"}},
+		},
+	}
+	base.Origins = server.ConfigOrigins{Toolsets: map[string]string{"my-toolset": "prebuilt:a", "other-toolset": "prebuilt:a"}}
+	override := withOrigin(ToolsFile{
+		AuthSources: server.AuthServiceConfigs{"my-google-auth": google.Config{Name: "my-google-auth", ClientID: "b"}},
+		Toolsets: server.ToolsetConfigs{
+			"my-toolset":  tools.ToolsetConfig{Name: "my-toolset", ToolNames: []string{"c"}},
+			"new-toolset": tools.ToolsetConfig{Name: "new-toolset", ToolNames: []string{"d"}},
+		},
+	}, "tools.yaml")
+	got, overridden := overrideToolsFile(base, override)
+
+	wantOverridden := []string{
+		"authService 'my-google-auth' of the prebuilt tool configuration is overridden by the tools file",
+		"toolset 'my-toolset' of the prebuilt tool configuration is overridden by the tools file",
+	}
+	if diff := cmp.Diff(wantOverridden, overridden); diff != "" {
+		t.Errorf("unexpected overridden resources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]auth.AuthServiceConfig(override.AuthSources), map[string]auth.AuthServiceConfig(got.AuthServices)); diff != "" {
+		t.Errorf("unexpected auth services (-want +got):\n%s", diff)
+	}
+	wantToolsets := server.ToolsetConfigs{
+		"my-toolset":    override.Toolsets["my-toolset"],
+		"other-toolset": base.Toolsets["other-toolset"],
+		"new-toolset":   override.Toolsets["new-toolset"],
+	}
+	if diff := cmp.Diff(wantToolsets, got.Toolsets); diff != "" {
+		t.Errorf("unexpected toolsets (-want +got):\n%s", diff)
+	}
+	wantOrigins := map[string]string{"my-toolset": "tools.yaml", "other-toolset": "prebuilt:a", "new-toolset": "tools.yaml"}
+	if diff := cmp.Diff(wantOrigins, got.Origins.Toolsets); diff != "" {
+		t.Errorf("unexpected toolset origins (-want +got):\n%s", diff)
+	}
+	// the base tools file is not modified
+	if got := base.Toolsets["my-toolset"].ToolNames; !slices.Equal(got, []string{"a"}) {
+		t.Errorf("base tools file was modified: %v", got)
+	}
+}
@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"fmt"
@@ -32,13 +33,13 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	yaml "github.com/goccy/go-yaml"
-	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/googleapis/genai-toolbox/internal/configprovider"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
 	"github.com/googleapis/genai-toolbox/internal/server"
-	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
-	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 
 	// Import tool packages for side effect of registration
@@ -98,6 +99,8 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorevalidaterules"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/hana/hanaexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/hana/hanasql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/hana/hanasqltransaction"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/hana/hanavectorsearch"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/http"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookeradddashboardelement"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerconversationalanalytics"
@@ -147,6 +150,8 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslistinstalledextensions"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgreslisttables"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressqltransaction"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresvectorsearch"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/redis"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spanner/spannerexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spanner/spannerlisttables"
@@ -157,6 +162,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinoexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/trino/trinosql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/pipeline"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/wait"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/valkey"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/yugabytedbsql"
@@ -195,6 +201,10 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/trino"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/valkey"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/yugabytedb"
+
+	// Import embedding model packages for side effect of registration
+	_ "github.com/googleapis/genai-toolbox/internal/embeddingmodels/fake"
+	_ "github.com/googleapis/genai-toolbox/internal/embeddingmodels/gemini"
 )
 
 var (
@@ -209,6 +219,10 @@ var (
 	commitSha string
 )
 
+// adminTokenEnv is the environment variable setting the admin token, if the
+// --admin-token flag is not set.
+const adminTokenEnv = "TOOLBOX_ADMIN_TOKEN"
+
 func init() {
 	versionString = semanticVersion()
 }
@@ -226,7 +240,12 @@ func semanticVersion() string {
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := NewCommand().Execute(); err != nil {
+	cmd := NewCommand()
+	if c, err := cmd.ExecuteC(); err != nil {
+		// Errors are logged by the server, but not by the subcommands.
+		if c != cmd.Command {
+			fmt.Fprintln(cmd.errStream, "Error:", err)
+		}
 		exit := 1
 		os.Exit(exit)
 	}
@@ -236,15 +255,18 @@ func Execute() {
 type Command struct {
 	*cobra.Command
 
-	cfg            server.ServerConfig
-	logger         log.Logger
-	tools_file     string
-	tools_files    []string
-	tools_folder   string
-	prebuiltConfig string
-	inStream       io.Reader
-	outStream      io.Writer
-	errStream      io.Writer
+	cfg             server.ServerConfig
+	logger          log.Logger
+	tools_file      string
+	tools_files     []string
+	tools_folder    string
+	prebuiltConfigs []string
+	pollInterval    time.Duration
+	// configWatcher polls the tools files loaded from remote locations.
+	configWatcher *configprovider.Watcher
+	inStream      io.Reader
+	outStream     io.Writer
+	errStream     io.Writer
 }
 
 // NewCommand returns a Command object representing an invocation of the CLI.
@@ -281,34 +303,52 @@ func NewCommand(opts ...Option) *Command {
 	baseCmd.SetErr(cmd.errStream)
 
 	flags := cmd.Flags()
+	// The tools file flags are shared with the subcommands.
+	persistentFlags := cmd.PersistentFlags()
+	persistentFlags.StringVar(&cmd.tools_file, "tools_file", "", "File path specifying the tool configuration.")
+	// deprecate tools_file
+	_ = persistentFlags.MarkDeprecated("tools_file", "please use --tools-file instead")
+	persistentFlags.StringVar(&cmd.tools_file, "tools-file", "", "File path or URL (http, https or gs) specifying the tool configuration. Cannot be used with --tools-files or --tools-folder.")
+	persistentFlags.StringSliceVar(&cmd.tools_files, "tools-files", []string{}, "Multiple file paths or URLs specifying tool configurations. Files will be merged. Cannot be used with --tools-file or --tools-folder.")
+	persistentFlags.StringVar(&cmd.tools_folder, "tools-folder", "", "Directory path containing YAML tool configuration files. All .yaml and .yml files in the directory will be loaded and merged. Cannot be used with --tools-file or --tools-files.")
+
+	// Fetch prebuilt tools sources to customize the help description
+	prebuiltHelp := fmt.Sprintf(
+		"Use a prebuilt tool configuration by source type. Can be repeated to combine several prebuilt configurations, and combined with --tools-file, --tools-files or --tools-folder to add or override resources. Allowed: '%s'.",
+		strings.Join(prebuiltconfigs.GetPrebuiltSources(), "', '"),
+	)
+	persistentFlags.StringSliceVar(&cmd.prebuiltConfigs, "prebuilt", []string{}, prebuiltHelp)
+
 	flags.StringVarP(&cmd.cfg.Address, "address", "a", "127.0.0.1", "Address of the interface the server will listen on.")
 	flags.IntVarP(&cmd.cfg.Port, "port", "p", 5000, "Port the server will listen on.")
 
-	flags.StringVar(&cmd.tools_file, "tools_file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt.")
-	// deprecate tools_file
-	_ = flags.MarkDeprecated("tools_file", "please use --tools-file instead")
-	flags.StringVar(&cmd.tools_file, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt, --tools-files, or --tools-folder.")
-	flags.StringSliceVar(&cmd.tools_files, "tools-files", []string{}, "Multiple file paths specifying tool configurations. Files will be merged. Cannot be used with --prebuilt, --tools-file, or --tools-folder.")
-	flags.StringVar(&cmd.tools_folder, "tools-folder", "", "Directory path containing YAML tool configuration files. All .yaml and .yml files in the directory will be loaded and merged. Cannot be used with --prebuilt, --tools-file, or --tools-files.")
 	flags.Var(&cmd.cfg.LogLevel, "log-level", "Specify the minimum level logged. Allowed: 'DEBUG', 'INFO', 'WARN', 'ERROR'.")
 	flags.Var(&cmd.cfg.LoggingFormat, "logging-format", "Specify logging format to use. Allowed: 'standard' or 'JSON'.")
 	flags.BoolVar(&cmd.cfg.TelemetryGCP, "telemetry-gcp", false, "Enable exporting directly to Google Cloud Monitoring.")
 	flags.StringVar(&cmd.cfg.TelemetryOTLP, "telemetry-otlp", "", "Enable exporting using OpenTelemetry Protocol (OTLP) to the specified endpoint (e.g. 'http://127.0.0.1:4318')")
+	flags.BoolVar(&cmd.cfg.TelemetryPrometheus, "telemetry-prometheus", false, "Enable serving metrics in the Prometheus format at the /metrics endpoint.")
 	flags.StringVar(&cmd.cfg.TelemetryServiceName, "telemetry-service-name", "toolbox", "Sets the value of the service.name resource attribute for telemetry data.")
+	flags.StringSliceVar(&cmd.cfg.ReadinessSources, "readiness-sources", []string{}, "Names of the sources checked by the /readyz endpoint. All sources are checked by default.")
+	flags.DurationVar(&cmd.cfg.ReadinessTimeout, "readiness-timeout", 5*time.Second, "Time each source checked by the /readyz endpoint has to answer a health check.")
+	flags.StringVar(&cmd.cfg.Audit.File, "audit-file", "", "File path of a JSON lines audit log that records every tool invocation.")
+	flags.IntVar(&cmd.cfg.Audit.FileMaxSizeMB, "audit-file-max-size", 100, "Size in megabytes at which the audit log file is rotated.")
+	flags.IntVar(&cmd.cfg.Audit.FileMaxBackups, "audit-file-max-backups", 5, "Number of rotated audit log files to keep.")
+	flags.BoolVar(&cmd.cfg.Audit.Stdout, "audit-stdout", false, "Write audit log entries to stdout (stderr when using --stdio).")
+	flags.StringVar(&cmd.cfg.Audit.OTLP, "audit-otlp", "", "Export audit log entries as OpenTelemetry logs to the specified OTLP endpoint (e.g. 'http://127.0.0.1:4318').")
+	flags.StringSliceVar(&cmd.cfg.Audit.Redact, "audit-redact", []string{"*password*", "*secret*", "*token*"}, "Glob patterns of parameter names whose values are redacted in the audit log.")
+	flags.StringSliceVar(&cmd.cfg.Audit.Claims, "audit-claims", []string{"sub", "email"}, "Claims of verified auth services that are recorded in the audit log.")
 
-	// Fetch prebuilt tools sources to customize the help description
-	prebuiltHelp := fmt.Sprintf(
-		"Use a prebuilt tool configuration by source type. Cannot be used with --tools-file. Allowed: '%s'.",
-		strings.Join(prebuiltconfigs.GetPrebuiltSources(), "', '"),
-	)
-	flags.StringVar(&cmd.prebuiltConfig, "prebuilt", "", prebuiltHelp)
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
 	flags.BoolVar(&cmd.cfg.DisableReload, "disable-reload", false, "Disables dynamic reloading of tools file.")
+	flags.DurationVar(&cmd.pollInterval, "poll-interval", 30*time.Second, "Interval at which remote tools files are polled for changes. Set to 0 to disable polling.")
 	flags.BoolVar(&cmd.cfg.UI, "ui", false, "Launches the Toolbox UI web server.")
+	flags.StringVar(&cmd.cfg.AdminToken, "admin-token", "", fmt.Sprintf("Bearer token enabling the admin API at /admin. Can also be set with the %s environment variable.", adminTokenEnv))
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
 
+	cmd.AddCommand(newValidateCommand(cmd), newLintCommand(cmd), newInvokeCommand(cmd), newListCommand(cmd), newSchemaCommand(cmd))
+
 	return cmd
 }
 
@@ -318,16 +358,72 @@ type ToolsFile struct {
 	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
 	Tools        server.ToolConfigs        `yaml:"tools"`
 	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	// Templates and parameter sets are resolved while parsing the tools.
+	Definitions server.ToolDefinitions `yaml:",inline"`
+	// Origins records the tools file or prebuilt configuration defining each
+	// resource.
+	Origins server.ConfigOrigins `yaml:"-"`
+}
+
+// withOrigin records origin as the origin of the sources, tools and toolsets
+// of toolsFile.
+func withOrigin(toolsFile ToolsFile, origin string) ToolsFile {
+	toolsFile.Origins = server.ConfigOrigins{
+		Sources:  originsOf(toolsFile.Sources, origin),
+		Tools:    originsOf(toolsFile.Tools, origin),
+		Toolsets: originsOf(toolsFile.Toolsets, origin),
+	}
+	return toolsFile
+}
+
+func originsOf[V any](resources map[string]V, origin string) map[string]string {
+	origins := make(map[string]string, len(resources))
+	for name := range resources {
+		origins[name] = origin
+	}
+	return origins
+}
+
+// mergeOrigins returns the origins of base, replaced by the origins of
+// override.
+func mergeOrigins(base, override server.ConfigOrigins) server.ConfigOrigins {
+	return server.ConfigOrigins{
+		Sources:  mergeOriginMaps(base.Sources, override.Sources),
+		Tools:    mergeOriginMaps(base.Tools, override.Tools),
+		Toolsets: mergeOriginMaps(base.Toolsets, override.Toolsets),
+	}
+}
+
+func mergeOriginMaps(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	maps.Copy(merged, base)
+	maps.Copy(merged, override)
+	return merged
 }
 
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
-// also support ${ENV_NAME:default_value}.
+// also support ${ENV_NAME:default_value}. Secret references, whose scheme
+// would otherwise be read as a variable with a default value, are left to
+// parseToolsFile.
 func parseEnv(input string) (string, error) {
+	return expandEnv(input, false)
+}
+
+// expandEnv replaces environment variables as parseEnv does. If placeholders
+// is set, variables that are not set and have no default value are replaced
+// with secrets.Placeholder instead of failing.
+func expandEnv(input string, placeholders bool) (string, error) {
 	re := regexp.MustCompile(`\$\{(\w+)(:(\w*))?\}`)
 
 	var err error
 	output := re.ReplaceAllStringFunc(input, func(match string) string {
 		parts := re.FindStringSubmatch(match)
+		if parts[2] != "" && secrets.IsScheme(parts[1]) {
+			return match
+		}
 
 		// extract the variable name
 		variableName := parts[1]
@@ -337,24 +433,64 @@ func parseEnv(input string) (string, error) {
 		if parts[2] != "" {
 			return parts[3]
 		}
+		if placeholders {
+			return secrets.Placeholder
+		}
 		err = fmt.Errorf("environment variable not found: %q", variableName)
 		return ""
 	})
 	return output, err
 }
 
-// parseToolsFile parses the provided yaml into appropriate configs.
-func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
+type placeholdersKey struct{}
+
+// withPlaceholders returns a context in which parseToolsFile replaces secret
+// references and unset environment variables with placeholders, so that tools
+// files are validated without reading secrets.
+func withPlaceholders(ctx context.Context) context.Context {
+	return secrets.WithPlaceholders(context.WithValue(ctx, placeholdersKey{}, true))
+}
+
+func placeholdersFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(placeholdersKey{}).(bool)
+	return v
+}
+
+// parseToolsFile parses the provided yaml into appropriate configs. file names
+// the tools file in the errors of its templates, and may be empty.
+func parseToolsFile(ctx context.Context, file string, raw []byte) (ToolsFile, error) {
 	var toolsFile ToolsFile
 	// Replace environment variables if found
-	output, err := parseEnv(string(raw))
+	output, err := expandEnv(string(raw), placeholdersFromContext(ctx))
 	if err != nil {
 		return toolsFile, fmt.Errorf("error parsing environment variables: %s", err)
 	}
-	raw = []byte(output)
+	f, err := parser.ParseBytes([]byte(output), 0)
+	if err != nil {
+		return toolsFile, err
+	}
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return toolsFile, nil
+	}
+	body := f.Docs[0].Body
+	// Replace secret references such as ${file:/path} in the parsed values,
+	// so that secrets are neither expanded as environment variables nor
+	// parsed as YAML
+	if err := secrets.ResolveNode(ctx, body); err != nil {
+		return toolsFile, fmt.Errorf("error resolving secrets: %s", err)
+	}
+
+	// Templates and parameter sets may be defined after the tools using them,
+	// so they are parsed first
+	var defs server.ToolDefinitions
+	if err := yaml.NewDecoder(bytes.NewReader(nil)).DecodeFromNodeContext(ctx, body, &defs); err != nil {
+		return toolsFile, err
+	}
+	defs.File = file
+	ctx = server.WithToolDefinitions(ctx, defs)
 
 	// Parse contents
-	err = yaml.UnmarshalContext(ctx, raw, &toolsFile, yaml.Strict())
+	err = yaml.NewDecoder(bytes.NewReader(nil), yaml.Strict()).DecodeFromNodeContext(ctx, body, &toolsFile)
 	if err != nil {
 		return toolsFile, err
 	}
@@ -386,6 +522,9 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 
 		// Check for conflicts and merge authSources (deprecated, but still support)
 		for name, authSource := range file.AuthSources {
+			if merged.AuthSources == nil {
+				merged.AuthSources = make(server.AuthServiceConfigs)
+			}
 			if _, exists := merged.AuthSources[name]; exists {
 				conflicts = append(conflicts, fmt.Sprintf("authSource '%s' (file #%d)", name, fileIndex+1))
 			} else {
@@ -426,6 +565,9 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, and toolset has a unique name across all files", strings.Join(conflicts, "\n  - "))
 	}
 
+	for _, file := range files {
+		merged.Origins = mergeOrigins(merged.Origins, file.Origins)
+	}
 	return merged, nil
 }
 
@@ -439,12 +581,12 @@ func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile,
 			return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", filePath, err)
 		}
 
-		toolsFile, err := parseToolsFile(ctx, buf)
+		toolsFile, err := parseToolsFile(ctx, filePath, buf)
 		if err != nil {
 			return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", filePath, err)
 		}
 
-		toolsFiles = append(toolsFiles, toolsFile)
+		toolsFiles = append(toolsFiles, withOrigin(toolsFile, filePath))
 	}
 
 	mergedFile, err := mergeToolsFiles(toolsFiles...)
@@ -455,6 +597,77 @@ func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile,
 	return mergedFile, nil
 }
 
+// loadRemoteToolsFiles loads tools files through config providers, which
+// support remote locations such as HTTP(S) URLs and Cloud Storage objects. The
+// watcher of the tools files is kept to poll them for changes.
+func loadRemoteToolsFiles(ctx context.Context, cmd *Command, locations []string) (ToolsFile, error) {
+	var providers []configprovider.Provider
+	for _, location := range locations {
+		p, err := configprovider.New(location)
+		if err != nil {
+			return ToolsFile{}, err
+		}
+		providers = append(providers, p)
+	}
+	watcher := configprovider.NewWatcher(providers, cmd.pollInterval)
+	docs, err := watcher.Load(ctx)
+	if err != nil {
+		return ToolsFile{}, err
+	}
+	toolsFile, err := parseDocuments(ctx, docs)
+	if err != nil {
+		return ToolsFile{}, err
+	}
+	// the server fails to start unless the tools files are applied
+	watcher.Applied(docs)
+	cmd.configWatcher = watcher
+	return toolsFile, nil
+}
+
+// parseDocuments parses tools files fetched by config providers and merges
+// them.
+func parseDocuments(ctx context.Context, docs []configprovider.Document) (ToolsFile, error) {
+	var toolsFiles []ToolsFile
+	for _, doc := range docs {
+		toolsFile, err := parseToolsFile(ctx, doc.Location, doc.Data)
+		if err != nil {
+			return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", doc.Location, err)
+		}
+		toolsFiles = append(toolsFiles, withOrigin(toolsFile, doc.Location))
+	}
+	if len(toolsFiles) == 1 {
+		return toolsFiles[0], nil
+	}
+	mergedFile, err := mergeToolsFiles(toolsFiles...)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to merge tools files: %w", err)
+	}
+	return mergedFile, nil
+}
+
+// reloadDocuments applies tools files polled by a config provider watcher to
+// the server.
+func reloadDocuments(ctx context.Context, docs []configprovider.Document, prebuiltConfigs []string, s *server.Server) error {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+	toolsFile, err := parseDocuments(ctx, docs)
+	if err == nil && len(prebuiltConfigs) > 0 {
+		toolsFile, err = applyPrebuiltConfigs(ctx, prebuiltConfigs, toolsFile)
+	}
+	if err != nil {
+		s.ResourceMgr.RecordReload(err)
+		logger.WarnContext(ctx, fmt.Sprintf("error loading tools files: %s", err))
+		return err
+	}
+	if err := handleDynamicReload(ctx, toolsFile, s); err != nil {
+		logger.WarnContext(ctx, fmt.Sprintf("unable to parse reloaded tools files: %s", err))
+		return err
+	}
+	return nil
+}
+
 // loadAndMergeToolsFolder loads all YAML files from a directory and merges them
 func loadAndMergeToolsFolder(ctx context.Context, folderPath string) (ToolsFile, error) {
 	// Check if directory exists
@@ -491,29 +704,93 @@ func loadAndMergeToolsFolder(ctx context.Context, folderPath string) (ToolsFile,
 	return loadAndMergeToolsFiles(ctx, allFiles)
 }
 
-func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Server) error {
-	logger, err := util.LoggerFromContext(ctx)
-	if err != nil {
-		panic(err)
+// loadToolsFile loads the tools file selected by the --prebuilt,
+// --tools-file, --tools-files or --tools-folder flags. The prebuilt tool
+// configurations are combined, and the user tools files are merged on top of
+// them.
+func loadToolsFile(ctx context.Context, cmd *Command) (ToolsFile, error) {
+	var prebuiltConfigs []string
+	for _, name := range cmd.prebuiltConfigs {
+		if !slices.Contains(prebuiltConfigs, name) {
+			prebuiltConfigs = append(prebuiltConfigs, name)
+		}
+	}
+	cmd.prebuiltConfigs = prebuiltConfigs
+	if len(cmd.prebuiltConfigs) == 0 {
+		return loadUserToolsFile(ctx, cmd)
+	}
+	var user ToolsFile
+	if cmd.tools_file != "" || len(cmd.tools_files) > 0 || cmd.tools_folder != "" {
+		var err error
+		user, err = loadUserToolsFile(ctx, cmd)
+		if err != nil {
+			return ToolsFile{}, err
+		}
+	}
+	return applyPrebuiltConfigs(ctx, cmd.prebuiltConfigs, user)
+}
+
+// loadUserToolsFile loads the tools file selected by the --tools-file,
+// --tools-files or --tools-folder flags, which defaults to tools.yaml.
+func loadUserToolsFile(ctx context.Context, cmd *Command) (ToolsFile, error) {
+	if len(cmd.tools_files) > 0 {
+		// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
+		if cmd.tools_file != "" || cmd.tools_folder != "" {
+			return ToolsFile{}, fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+		}
+
+		// Use multiple tools files
+		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
+		if slices.ContainsFunc(cmd.tools_files, configprovider.IsRemote) {
+			return loadRemoteToolsFiles(ctx, cmd, cmd.tools_files)
+		}
+		return loadAndMergeToolsFiles(ctx, cmd.tools_files)
+	}
+	if cmd.tools_folder != "" {
+		// Make sure --tools-folder and other flags are mutually exclusive
+		if cmd.tools_file != "" || len(cmd.tools_files) > 0 {
+			return ToolsFile{}, fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+		}
+
+		// Use tools folder
+		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
+		return loadAndMergeToolsFolder(ctx, cmd.tools_folder)
+	}
+
+	// Set default value of tools-file flag to tools.yaml
+	if cmd.tools_file == "" {
+		cmd.tools_file = "tools.yaml"
+	}
+
+	if configprovider.IsRemote(cmd.tools_file) {
+		return loadRemoteToolsFiles(ctx, cmd, []string{cmd.tools_file})
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile)
+	// Read single tool file contents
+	buf, err := os.ReadFile(cmd.tools_file)
 	if err != nil {
-		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
-		logger.WarnContext(ctx, errMsg.Error())
-		return err
+		return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
 	}
 
-	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	toolsFile, err := parseToolsFile(ctx, cmd.tools_file, buf)
+	if err != nil {
+		return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
+	}
+	return withOrigin(toolsFile, cmd.tools_file), nil
+}
 
-	return nil
+// subcommandContext returns the context of a subcommand of root, with a
+// logger writing warnings and errors to the error stream of c.
+func subcommandContext(c *cobra.Command, root *Command) (context.Context, error) {
+	logger, err := log.NewStdLogger(c.ErrOrStderr(), c.ErrOrStderr(), "WARN")
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize logger: %w", err)
+	}
+	root.logger = logger
+	return util.WithLogger(c.Context(), logger), nil
 }
 
-// validateReloadEdits checks that the reloaded tools file configs can initialized without failing
-func validateReloadEdits(
-	ctx context.Context, toolsFile ToolsFile,
-) (map[string]sources.Source, map[string]auth.AuthService, map[string]tools.Tool, map[string]tools.Toolset, error,
-) {
+func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Server) error {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -535,20 +812,54 @@ func validateReloadEdits(
 		AuthServiceConfigs: toolsFile.AuthServices,
 		ToolConfigs:        toolsFile.Tools,
 		ToolsetConfigs:     toolsFile.Toolsets,
+		Origins:            toolsFile.Origins,
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
-	if err != nil {
+	// unchanged sources are reused, and replaced ones are closed once the
+	// requests using them complete
+	if err := s.ResourceMgr.Reload(ctx, reloadedConfig); err != nil {
 		errMsg := fmt.Errorf("unable to initialize reloaded configs: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
-		return nil, nil, nil, nil, err
+		return err
 	}
 
-	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
+	return nil
+}
+
+// reloadToolsFiles loads the tools files again and applies them to the
+// server, when a reload is requested through the admin API.
+func reloadToolsFiles(ctx context.Context, cmd *Command, s *server.Server) error {
+	var toolsFile ToolsFile
+	var err error
+	var docs []configprovider.Document
+	if cmd.configWatcher != nil {
+		docs, err = cmd.configWatcher.Load(ctx)
+		if err == nil {
+			toolsFile, err = parseDocuments(ctx, docs)
+		}
+		if err == nil && len(cmd.prebuiltConfigs) > 0 {
+			toolsFile, err = applyPrebuiltConfigs(ctx, cmd.prebuiltConfigs, toolsFile)
+		}
+	} else {
+		toolsFile, err = loadToolsFile(ctx, cmd)
+	}
+	if err != nil {
+		s.ResourceMgr.RecordReload(err)
+		return err
+	}
+	if err := handleDynamicReload(ctx, toolsFile, s); err != nil {
+		return err
+	}
+	if cmd.configWatcher != nil {
+		cmd.configWatcher.Applied(docs)
+	}
+	return nil
 }
 
 // watchChanges checks for changes in the provided yaml tools file(s) or folder.
-func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, s *server.Server) {
+// The reloaded tools files are merged on top of the prebuilt tool
+// configurations, if any.
+func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, prebuiltConfigs []string, s *server.Server) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -590,6 +901,9 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 		logger.DebugContext(ctx, fmt.Sprintf("Added directory %s to watcher.", dir))
 	}
 
+	// the checksum of the tools files detects events which do not change them
+	lastChecksum, _ := localToolsFilesChecksum(folderToWatch, watchedFiles)
+
 	// debounce timer is used to prevent multiple writes triggering multiple reloads
 	debounceDelay := 100 * time.Millisecond
 	debounce := time.NewTimer(1 * time.Minute)
@@ -628,19 +942,30 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 			folderChanged := watchingFolder &&
 				(strings.HasSuffix(cleanedFilename, ".yaml") || strings.HasSuffix(cleanedFilename, ".yml"))
 
-			if folderChanged || watchedFiles[cleanedFilename] {
+			// Kubernetes updates mounted ConfigMaps by swapping the `..data`
+			// symlink, which the tools files link through, without any event
+			// on the tools files themselves
+			configMapSwapped := filepath.Base(cleanedFilename) == configMapDataDir && watchDirs[filepath.Dir(cleanedFilename)]
+
+			if folderChanged || configMapSwapped || watchedFiles[cleanedFilename] {
 				// indicates the write event is on a relevant file
 				debounce.Reset(debounceDelay)
 			}
 
 		case <-debounce.C:
 			debounce.Stop()
+			checksum, err := localToolsFilesChecksum(folderToWatch, watchedFiles)
+			if err == nil && checksum == lastChecksum {
+				logger.DebugContext(ctx, "Tools files unchanged, skipping reload.")
+				continue
+			}
 			var reloadedToolsFile ToolsFile
 
 			if watchingFolder {
 				logger.DebugContext(ctx, "Reloading tools folder.")
 				reloadedToolsFile, err = loadAndMergeToolsFolder(ctx, folderToWatch)
 				if err != nil {
+					s.ResourceMgr.RecordReload(err)
 					logger.WarnContext(ctx, "error loading tools folder %s", err)
 					continue
 				}
@@ -648,19 +973,62 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 				logger.DebugContext(ctx, "Reloading tools file(s).")
 				reloadedToolsFile, err = loadAndMergeToolsFiles(ctx, slices.Collect(maps.Keys(watchedFiles)))
 				if err != nil {
+					s.ResourceMgr.RecordReload(err)
 					logger.WarnContext(ctx, "error loading tools files %s", err)
 					continue
 				}
 			}
 
+			if len(prebuiltConfigs) > 0 {
+				reloadedToolsFile, err = applyPrebuiltConfigs(ctx, prebuiltConfigs, reloadedToolsFile)
+				if err != nil {
+					s.ResourceMgr.RecordReload(err)
+					logger.WarnContext(ctx, "error applying prebuilt tool configurations %s", err)
+					continue
+				}
+			}
+
 			err = handleDynamicReload(ctx, reloadedToolsFile, s)
 			if err != nil {
-				errMsg := fmt.Errorf("unable to parse reloaded tools file at %q: %w", reloadedToolsFile, err)
+				errMsg := fmt.Errorf("unable to parse reloaded tools file: %w", err)
 				logger.WarnContext(ctx, errMsg.Error())
 				continue
 			}
+			// tools files failing to reload are reloaded by the next event,
+			// even if unchanged
+			lastChecksum = checksum
+		}
+	}
+}
+
+// configMapDataDir is the symlink through which the files of a mounted
+// Kubernetes ConfigMap link to their current version.
+const configMapDataDir = "..data"
+
+// localToolsFilesChecksum returns the checksum of the YAML files of folder,
+// or of files if folder is empty.
+func localToolsFilesChecksum(folder string, files map[string]bool) (string, error) {
+	paths := slices.Sorted(maps.Keys(files))
+	if folder != "" {
+		yamlFiles, err := filepath.Glob(filepath.Join(folder, "*.yaml"))
+		if err != nil {
+			return "", err
+		}
+		ymlFiles, err := filepath.Glob(filepath.Join(folder, "*.yml"))
+		if err != nil {
+			return "", err
+		}
+		paths = append(yamlFiles, ymlFiles...)
+	}
+	var docs []configprovider.Document
+	for _, path := range paths {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
 		}
+		docs = append(docs, configprovider.Document{Location: path, Data: buf})
 	}
+	return configprovider.Checksum(docs), nil
 }
 
 func resolveWatcherInputs(toolsFile string, toolsFiles []string, toolsFolder string) (map[string]bool, map[string]bool) {
@@ -741,7 +1109,7 @@ func run(cmd *Command) error {
 	ctx = util.WithLogger(ctx, cmd.logger)
 
 	// Set up OpenTelemetry
-	otelShutdown, err := telemetry.SetupOTel(ctx, cmd.cfg.Version, cmd.cfg.TelemetryOTLP, cmd.cfg.TelemetryGCP, cmd.cfg.TelemetryServiceName)
+	otelShutdown, err := telemetry.SetupOTel(ctx, cmd.cfg.Version, cmd.cfg.TelemetryOTLP, cmd.cfg.TelemetryGCP, cmd.cfg.TelemetryPrometheus, cmd.cfg.TelemetryServiceName)
 	if err != nil {
 		errMsg := fmt.Errorf("error setting up OpenTelemetry: %w", err)
 		cmd.logger.ErrorContext(ctx, errMsg.Error())
@@ -755,87 +1123,18 @@ func run(cmd *Command) error {
 		}
 	}()
 
-	var toolsFile ToolsFile
-
-	if cmd.prebuiltConfig != "" {
-		// Make sure --prebuilt and --tools-file/--tools-files/--tools-folder flags are mutually exclusive
-		if cmd.tools_file != "" || len(cmd.tools_files) > 0 || cmd.tools_folder != "" {
-			errMsg := fmt.Errorf("--prebuilt and --tools-file/--tools-files/--tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-		// Use prebuilt tools
-		buf, err := prebuiltconfigs.Get(cmd.prebuiltConfig)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-		logMsg := fmt.Sprint("Using prebuilt tool configuration for ", cmd.prebuiltConfig)
-		cmd.logger.InfoContext(ctx, logMsg)
+	toolsFile, err := loadToolsFile(ctx, cmd)
+	if err != nil {
+		cmd.logger.ErrorContext(ctx, err.Error())
+		return err
+	}
+	if len(cmd.prebuiltConfigs) > 0 {
 		// Append prebuilt.source to Version string for the User Agent
-		cmd.cfg.Version += "+prebuilt." + cmd.prebuiltConfig
-
-		toolsFile, err = parseToolsFile(ctx, buf)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to parse prebuilt tool configuration: %w", err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-	} else if len(cmd.tools_files) > 0 {
-		// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
-		if cmd.tools_file != "" || cmd.tools_folder != "" {
-			errMsg := fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		// Use multiple tools files
-		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
-		var err error
-		toolsFile, err = loadAndMergeToolsFiles(ctx, cmd.tools_files)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-	} else if cmd.tools_folder != "" {
-		// Make sure --tools-folder and other flags are mutually exclusive
-		if cmd.tools_file != "" || len(cmd.tools_files) > 0 {
-			errMsg := fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		// Use tools folder
-		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
-		var err error
-		toolsFile, err = loadAndMergeToolsFolder(ctx, cmd.tools_folder)
-		if err != nil {
-			cmd.logger.ErrorContext(ctx, err.Error())
-			return err
-		}
-	} else {
-		// Set default value of tools-file flag to tools.yaml
-		if cmd.tools_file == "" {
-			cmd.tools_file = "tools.yaml"
-		}
-
-		// Read single tool file contents
-		buf, err := os.ReadFile(cmd.tools_file)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
-
-		toolsFile, err = parseToolsFile(ctx, buf)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
-		}
+		cmd.cfg.Version += "+prebuilt." + strings.Join(cmd.prebuiltConfigs, ".")
 	}
 
 	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cmd.cfg.Origins = toolsFile.Origins
 	authSourceConfigs := toolsFile.AuthSources
 	if authSourceConfigs != nil {
 		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
@@ -851,6 +1150,12 @@ func run(cmd *Command) error {
 
 	ctx = util.WithInstrumentation(ctx, instrumentation)
 
+	// the admin token is preferably set through the environment, so that it
+	// is not visible in the command line
+	if cmd.cfg.AdminToken == "" {
+		cmd.cfg.AdminToken = os.Getenv(adminTokenEnv)
+	}
+
 	// start server
 	s, err := server.NewServer(ctx, cmd.cfg)
 	if err != nil {
@@ -858,6 +1163,10 @@ func run(cmd *Command) error {
 		cmd.logger.ErrorContext(ctx, errMsg.Error())
 		return errMsg
 	}
+	s.SetReloadFunc(func(ctx context.Context) error { return reloadToolsFiles(ctx, cmd, s) })
+	if cmd.cfg.AdminToken != "" {
+		cmd.logger.InfoContext(ctx, "Admin API enabled at /admin.")
+	}
 
 	// run server in background
 	srvErr := make(chan error)
@@ -890,11 +1199,21 @@ func run(cmd *Command) error {
 		}()
 	}
 
-	watchDirs, watchedFiles := resolveWatcherInputs(cmd.tools_file, cmd.tools_files, cmd.tools_folder)
-
-	if !cmd.cfg.DisableReload {
+	// Prebuilt tool configurations are embedded, so there is nothing to watch
+	// without user tools files.
+	hasToolsFiles := cmd.tools_file != "" || len(cmd.tools_files) > 0 || cmd.tools_folder != ""
+	if !cmd.cfg.DisableReload && cmd.configWatcher != nil {
+		// remote tools files are polled for changes
+		go cmd.configWatcher.Run(ctx, func(ctx context.Context, docs []configprovider.Document) error {
+			return reloadDocuments(ctx, docs, cmd.prebuiltConfigs, s)
+		}, func(err error) {
+			s.ResourceMgr.RecordReload(err)
+			cmd.logger.WarnContext(ctx, fmt.Sprintf("error polling tools files: %s", err))
+		})
+	} else if !cmd.cfg.DisableReload && hasToolsFiles {
+		watchDirs, watchedFiles := resolveWatcherInputs(cmd.tools_file, cmd.tools_files, cmd.tools_folder)
 		// start watching the file(s) or folder for changes to trigger dynamic reloading
-		go watchChanges(ctx, watchDirs, watchedFiles, s)
+		go watchChanges(ctx, watchDirs, watchedFiles, cmd.prebuiltConfigs, s)
 	}
 
 	// wait for either the server to error out or the command's context to be canceled
@@ -20,6 +20,8 @@ import (
 	_ "embed"
 	"fmt"
 	"io"
+	nethttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
@@ -31,9 +33,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/googleapis/genai-toolbox/internal/audit"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	cloudsqlpgsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
@@ -60,6 +64,24 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.Audit.FileMaxSizeMB == 0 {
+		c.Audit.FileMaxSizeMB = 100
+	}
+	if c.Audit.FileMaxBackups == 0 {
+		c.Audit.FileMaxBackups = 5
+	}
+	if c.Audit.Redact == nil {
+		c.Audit.Redact = []string{"*password*", "*secret*", "*token*"}
+	}
+	if c.Audit.Claims == nil {
+		c.Audit.Claims = []string{"sub", "email"}
+	}
+	if c.ReadinessSources == nil {
+		c.ReadinessSources = []string{}
+	}
+	if c.ReadinessTimeout == 0 {
+		c.ReadinessTimeout = 5 * time.Second
+	}
 	return c
 }
 
@@ -191,6 +213,26 @@ func TestServerConfigFlags(t *testing.T) {
 				DisableReload: true,
 			}),
 		},
+		{
+			desc: "audit",
+			args: []string{"--audit-file", "audit.jsonl", "--audit-stdout", "--audit-redact", "pin,*_iban", "--audit-claims", "email"},
+			want: withDefaults(server.ServerConfig{
+				Audit: audit.Config{
+					File:   "audit.jsonl",
+					Stdout: true,
+					Redact: []string{"pin", "*_iban"},
+					Claims: []string{"email"},
+				},
+			}),
+		},
+		{
+			desc: "readiness",
+			args: []string{"--readiness-sources", "my-pg,my-hana", "--readiness-timeout", "2s"},
+			want: withDefaults(server.ServerConfig{
+				ReadinessSources: []string{"my-pg", "my-hana"},
+				ReadinessTimeout: 2 * time.Second,
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -407,6 +449,41 @@ func TestPrebuiltFlag(t *testing.T) {
 	}
 }
 
+func TestMultiplePrebuiltFlag(t *testing.T) {
+	tcs := []struct {
+		desc string
+		args []string
+		want []string
+	}{
+		{
+			desc: "default value",
+			args: []string{},
+			want: []string{},
+		},
+		{
+			desc: "repeated flag",
+			args: []string{"--prebuilt", "hana", "--prebuilt", "postgres"},
+			want: []string{"hana", "postgres"},
+		},
+		{
+			desc: "comma separated flag",
+			args: []string{"--prebuilt", "hana,postgres", "--tools-file", "tools.yaml"},
+			want: []string{"hana", "postgres"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, _, err := invokeCommand(tc.args)
+			if err != nil {
+				t.Fatalf("unexpected error invoking command: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, c.prebuiltConfigs); diff != "" {
+				t.Fatalf("incorrect prebuilt configs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestFailServerConfigFlags(t *testing.T) {
 	tcs := []struct {
 		desc string
@@ -530,7 +607,7 @@ func TestParseToolFile(t *testing.T) {
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
-			toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			toolsFile, err := parseToolsFile(ctx, "", testutils.FormatYaml(tc.in))
 			if err != nil {
 				t.Fatalf("failed to parse input: %v", err)
 			}
@@ -551,6 +628,175 @@ func TestParseToolFile(t *testing.T) {
 
 }
 
+func TestParseToolFileWithTemplates(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		list_hotels:
+			extends: hotel-query
+			description: List hotels.
+			statement: SELECT * FROM hotels LIMIT $1 OFFSET $2
+		search_hotels:
+			extends: list_hotels
+			description: Search hotels by name.
+			statement: SELECT * FROM hotels WHERE name = $1 LIMIT $2 OFFSET $3
+			parameters:
+				- name: name
+					type: string
+					description: The name of the hotel.
+				- parameterSet: paging
+	templates:
+		hotel-query:
+			kind: postgres-sql
+			source: my-pg-instance
+			authRequired: [my-google-auth]
+			parameters:
+				- parameterSet: paging
+	parameterSets:
+		paging:
+			- name: limit
+				type: integer
+				description: The maximum number of rows.
+			- parameterSet: offset
+		offset:
+			- name: offset
+				type: integer
+				description: The number of rows to skip.
+	`
+	toolsFile, err := parseToolsFile(ctx, "", testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	paging := []tools.Parameter{
+		tools.NewIntParameter("limit", "The maximum number of rows."),
+		tools.NewIntParameter("offset", "The number of rows to skip."),
+	}
+	want := server.ToolConfigs{
+		"list_hotels": postgressql.Config{
+			Name:         "list_hotels",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "List hotels.",
+			Statement:    "SELECT * FROM hotels LIMIT $1 OFFSET $2",
+			Parameters:   paging,
+			AuthRequired: []string{"my-google-auth"},
+		},
+		"search_hotels": postgressql.Config{
+			Name:         "search_hotels",
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "Search hotels by name.",
+			Statement:    "SELECT * FROM hotels WHERE name = $1 LIMIT $2 OFFSET $3",
+			Parameters:   append([]tools.Parameter{tools.NewStringParameter("name", "The name of the hotel.")}, paging...),
+			AuthRequired: []string{"my-google-auth"},
+		},
+	}
+	if diff := cmp.Diff(want, toolsFile.Tools); diff != "" {
+		t.Fatalf("incorrect tools parse: diff %v", diff)
+	}
+}
+
+func TestParseToolFileWithTemplatesErrors(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		description string
+		in          string
+		want        string
+	}{
+		{
+			description: "missing template",
+			in: `
+			tools:
+				example_tool:
+					extends: missing
+			`,
+			want: `line 4: tool "example_tool" extends "missing", which is not a template or tool`,
+		},
+		{
+			description: "extends cycle",
+			in: `
+			templates:
+				first:
+					extends: second
+				second:
+					extends: first
+			tools:
+				example_tool:
+					extends: first
+			`,
+			want: `line 4: template "first" extends itself: template "first" -> template "second" -> template "first"`,
+		},
+		{
+			description: "missing parameter set",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1
+					parameters:
+						- parameterSet: missing
+			`,
+			want: `line 4: tool "example_tool" references parameterSet "missing", which does not exist`,
+		},
+		{
+			description: "parameter set cycle",
+			in: `
+			parameterSets:
+				paging:
+					- parameterSet: paging
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1
+					parameters:
+						- parameterSet: paging
+			`,
+			want: `line 4: parameterSet "paging" references itself: parameterSet "paging" -> parameterSet "paging"`,
+		},
+		{
+			description: "missing kind in template",
+			in: `
+			templates:
+				base:
+					source: my-pg-instance
+			tools:
+				example_tool:
+					extends: base
+			`,
+			want: `line 7: missing 'kind' field for tool "example_tool"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseToolsFile(ctx, "", testutils.FormatYaml(tc.in))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	// the path of the tools file is added to the error
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, testutils.FormatYaml(tcs[0].in), 0o600); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+	_, err = loadAndMergeToolsFiles(ctx, []string{path})
+	want := fmt.Sprintf("%s:%s", path, strings.TrimPrefix(tcs[0].want, "line "))
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
 func TestParseToolFileWithAuth(t *testing.T) {
 	ctx, err := testutils.ContextWithNewLogger()
 	if err != nil {
@@ -863,7 +1109,7 @@ func TestParseToolFileWithAuth(t *testing.T) {
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
-			toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			toolsFile, err := parseToolsFile(ctx, "", testutils.FormatYaml(tc.in))
 			if err != nil {
 				t.Fatalf("failed to parse input: %v", err)
 			}
@@ -1028,7 +1274,7 @@ func TestEnvVarReplacement(t *testing.T) {
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
-			toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(tc.in))
+			toolsFile, err := parseToolsFile(ctx, "", testutils.FormatYaml(tc.in))
 			if err != nil {
 				t.Fatalf("failed to parse input: %v", err)
 			}
@@ -1049,6 +1295,71 @@ func TestEnvVarReplacement(t *testing.T) {
 
 }
 
+// fakeSecrets resolves secret references from a map.
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) Resolve(_ context.Context, ref string) (string, error) {
+	v, ok := f[ref]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", ref)
+	}
+	return v, nil
+}
+
+func TestSecretReplacement(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	restore := secrets.Replace("fake", fakeSecrets{"pg#user": "my_user"})
+	defer restore()
+	t.Setenv("PG_INSTANCE", "my-instance")
+	passwordFile := filepath.Join(t.TempDir(), "pg_password")
+	// secrets are neither expanded as environment variables nor parsed as YAML
+	password := "${PG_INSTANCE}: #my\npass"
+	if err := os.WriteFile(passwordFile, []byte(password+"\n"), 0o600); err != nil {
+		t.Fatalf("unable to write secret file: %s", err)
+	}
+
+	in := fmt.Sprintf(`
+	sources:
+		# references in comments are not resolved: ${fake:missing}
+		my-pg-instance:
+			kind: cloud-sql-postgres
+			project: my-project
+			region: my-region
+			instance: ${PG_INSTANCE}
+			database: ${PG_DATABASE:my_db}
+			user: ${fake:pg#user}
+			password: ${file:%s}
+	`, passwordFile)
+	toolsFile, err := parseToolsFile(ctx, "", testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	want := server.SourceConfigs{
+		"my-pg-instance": cloudsqlpgsrc.Config{
+			Name:     "my-pg-instance",
+			Kind:     cloudsqlpgsrc.SourceKind,
+			Project:  "my-project",
+			Region:   "my-region",
+			Instance: "my-instance",
+			IPType:   "public",
+			Database: "my_db",
+			User:     "my_user",
+			Password: password,
+		},
+	}
+	if diff := cmp.Diff(want, toolsFile.Sources); diff != "" {
+		t.Fatalf("incorrect sources parse: diff %v", diff)
+	}
+
+	_, err = parseToolsFile(ctx, "", []byte("sources: ${fake:missing}"))
+	if err == nil || !strings.Contains(err.Error(), "error resolving secrets") {
+		t.Fatalf("expected secret resolution error, got %v", err)
+	}
+}
+
 // normalizeFilepaths is a helper function to allow same filepath formats for Mac and Windows.
 // this prevents needing multiple "want" cases for TestResolveWatcherInputs
 func normalizeFilepaths(m map[string]bool) map[string]bool {
@@ -1199,7 +1510,7 @@ func TestSingleEdit(t *testing.T) {
 	watchedFiles := map[string]bool{cleanFileToWatch: true}
 	watchDirs := map[string]bool{watchDir: true}
 
-	go watchChanges(ctx, watchDirs, watchedFiles, mockServer)
+	go watchChanges(ctx, watchDirs, watchedFiles, nil, mockServer)
 
 	// escape backslash so regex doesn't fail on windows filepaths
 	regexEscapedPathFile := strings.ReplaceAll(cleanFileToWatch, `\`, `\\\\*\\`)
@@ -1227,6 +1538,114 @@ func TestSingleEdit(t *testing.T) {
 	}
 }
 
+func TestConfigMapSwap(t *testing.T) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelCtx()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	defer pr.Close()
+
+	logger, err := log.NewStdLogger(pw, pw, "DEBUG")
+	if err != nil {
+		t.Fatalf("failed to setup logger %s", err)
+	}
+	ctx = util.WithLogger(ctx, logger)
+
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		t.Fatalf("failed to setup instrumentation %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	// lay out the directory like a mounted ConfigMap, whose files link
+	// through the `..data` symlink to the current version
+	dir := t.TempDir()
+	writeVersion := func(version, content string) {
+		if err := os.Mkdir(filepath.Join(dir, version), 0o755); err != nil {
+			t.Fatalf("unable to create version: %s", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version, "tools.yaml"), []byte(content), 0o600); err != nil {
+			t.Fatalf("unable to write tools file: %s", err)
+		}
+	}
+	swap := func(version string) {
+		tmp := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(version, tmp); err != nil {
+			t.Fatalf("unable to create symlink: %s", err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, configMapDataDir)); err != nil {
+			t.Fatalf("unable to swap symlink: %s", err)
+		}
+	}
+	writeVersion("..v1", "tools: {}\n")
+	swap("..v1")
+	toolsFile := filepath.Join(dir, "tools.yaml")
+	if err := os.Symlink(filepath.Join(configMapDataDir, "tools.yaml"), toolsFile); err != nil {
+		t.Fatalf("unable to create symlink: %s", err)
+	}
+
+	mockServer := &server.Server{ResourceMgr: server.NewResourceManager(nil, nil, nil, nil)}
+	go watchChanges(ctx, map[string]bool{dir: true}, map[string]bool{toolsFile: true}, nil, mockServer)
+
+	if _, err := testutils.WaitForString(ctx, regexp.MustCompile(`Added directory .* to watcher.`), pr); err != nil {
+		t.Fatalf("timeout or error waiting for watcher to start: %s", err)
+	}
+
+	// a swap to the same content does not reload the tools file
+	writeVersion("..v2", "tools: {}\n")
+	swap("..v2")
+	if _, err := testutils.WaitForString(ctx, regexp.MustCompile(`Tools files unchanged, skipping reload.`), pr); err != nil {
+		t.Fatalf("timeout or error waiting for unchanged tools file: %s", err)
+	}
+
+	writeVersion("..v3", "tools: [\n")
+	swap("..v3")
+	if _, err := testutils.WaitForString(ctx, regexp.MustCompile(`error loading tools files`), pr); err != nil {
+		t.Fatalf("timeout or error waiting for reload: %s", err)
+	}
+	if status, ok := mockServer.ResourceMgr.LastReload(); !ok || status.Error == "" {
+		t.Errorf("expected a failed reload, got %+v", status)
+	}
+}
+
+func TestRemoteToolsFile(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		requests++
+		fmt.Fprint(w, `
+tools:
+  search:
+    kind: http
+    source: my-http-instance
+    method: GET
+    path: /search
+    description: Search.
+sources:
+  my-http-instance:
+    kind: http
+    baseUrl: http://127.0.0.1:1
+`)
+	}))
+	defer ts.Close()
+
+	_, _, err := invokeCommand([]string{"list", "--tools-files", ts.URL + "/tools.yaml," + ts.URL + "/other.yaml"})
+	if err == nil || !strings.Contains(err.Error(), "resource conflicts detected") {
+		t.Fatalf("expected conflicts between the remote tools files, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("unexpected number of requests: got %d, want 2", requests)
+	}
+
+	_, out, err := invokeCommand([]string{"list", "--tools-file", ts.URL + "/tools.yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out, "  search\n    Search.\n") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
 func TestPrebuiltTools(t *testing.T) {
 	// Get prebuilt configs
 	alloydb_admin_config, _ := prebuiltconfigs.Get("alloydb-postgres-admin")
@@ -1618,7 +2037,7 @@ func TestPrebuiltTools(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			toolsFile, err := parseToolsFile(ctx, tc.in)
+			toolsFile, err := parseToolsFile(ctx, "", tc.in)
 			if err != nil {
 				t.Fatalf("failed to parse input: %v", err)
 			}
@@ -0,0 +1,58 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/googleapis/genai-toolbox/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCommand(root *Command) *cobra.Command {
+	var output string
+	c := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the tools file",
+		Long: "Schema prints a JSON Schema of the tools file, generated from the " +
+			"configuration of every registered source and tool kind, for editors to " +
+			"validate and autocomplete tools files.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			ctx, err := subcommandContext(c, root)
+			if err != nil {
+				return err
+			}
+			s, err := schema.Generate(ctx)
+			if err != nil {
+				return err
+			}
+			b, err := json.MarshalIndent(s, "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to encode schema: %w", err)
+			}
+			b = append(b, '\n')
+			if output != "" {
+				return os.WriteFile(output, b, 0o644)
+			}
+			_, err = c.OutOrStdout().Write(b)
+			return err
+		},
+	}
+	c.Flags().StringVarP(&output, "output", "o", "", "File path to write the schema to instead of stdout.")
+	return c
+}
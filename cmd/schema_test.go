@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	_, out, err := invokeCommand([]string{"schema"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got struct {
+		Definitions map[string]any `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unable to parse schema: %s", err)
+	}
+	for _, def := range []string{"source.hana", "source.postgres", "tool.hana-sql", "tool.postgres-execute-sql", "authService.google", "parameter.string"} {
+		if _, ok := got.Definitions[def]; !ok {
+			t.Errorf("schema has no definition %q", def)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if _, _, err := invokeCommand([]string{"schema", "--output", path}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read schema file: %s", err)
+	}
+	if string(b) != out {
+		t.Errorf("schema written to --output differs from the schema written to stdout")
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/spf13/cobra"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// Lint rules reported by the lint subcommand.
+const (
+	ruleUnquotedTemplateParameter = "unquoted-template-parameter"
+	ruleExecuteSQLWithoutAuth     = "execute-sql-without-auth"
+	ruleMissingDescription        = "missing-description"
+	ruleUnusedSource              = "unused-source"
+)
+
+// finding is a problem found in a tools file.
+type finding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f finding) String() string {
+	var b strings.Builder
+	b.WriteString(f.Severity)
+	b.WriteString(": ")
+	if f.Resource != "" {
+		fmt.Fprintf(&b, "%s %q: ", f.Resource, f.Name)
+	}
+	b.WriteString(f.Message)
+	if f.Rule != "" {
+		fmt.Fprintf(&b, " (%s)", f.Rule)
+	}
+	return b.String()
+}
+
+// report is the result of validating or linting a tools file.
+type report struct {
+	Valid    bool      `json:"valid"`
+	Errors   []finding `json:"errors"`
+	Warnings []finding `json:"warnings"`
+}
+
+func newValidateCommand(root *Command) *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a tools file without connecting to its sources",
+		Long: "Validate parses the tools file, checks that every kind is registered and that " +
+			"tools, toolsets and auth services reference each other correctly, without " +
+			"connecting to any source.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			return runCheck(c, root, format, false, false)
+		},
+	}
+	c.Flags().StringVar(&format, "format", formatText, "Output format. Allowed: 'text' or 'json'.")
+	return c
+}
+
+func newLintCommand(root *Command) *cobra.Command {
+	var format string
+	var strict bool
+	c := &cobra.Command{
+		Use:   "lint",
+		Short: "Validate a tools file and warn about risky patterns",
+		Long: "Lint validates the tools file like validate and also warns about unquoted " +
+			"template parameters, execute-sql tools without authRequired, missing " +
+			"descriptions and unused sources.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			return runCheck(c, root, format, true, strict)
+		},
+	}
+	c.Flags().StringVar(&format, "format", formatText, "Output format. Allowed: 'text' or 'json'.")
+	c.Flags().BoolVar(&strict, "strict", false, "Exit with an error if any warning is reported.")
+	return c
+}
+
+// runCheck loads the tools file selected by the flags of root, validates it
+// and, if lint is set, lints it. It returns an error if the tools file is
+// invalid, or if strict is set and a warning was reported.
+func runCheck(c *cobra.Command, root *Command, format string, lint, strict bool) error {
+	format = strings.ToLower(format)
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("output format invalid: %q", format)
+	}
+
+	ctx, err := subcommandContext(c, root)
+	if err != nil {
+		return err
+	}
+
+	rep := checkToolsFile(ctx, root, lint)
+	if err := writeReport(c.OutOrStdout(), format, rep); err != nil {
+		return err
+	}
+	if !rep.Valid {
+		return fmt.Errorf("tools file is invalid: %d error(s) found", len(rep.Errors))
+	}
+	if strict && len(rep.Warnings) > 0 {
+		return fmt.Errorf("%d warning(s) found", len(rep.Warnings))
+	}
+	return nil
+}
+
+// checkToolsFile loads the tools file selected by the flags of cmd and
+// reports the problems found in it. Secret references and unset environment
+// variables are replaced with placeholders.
+func checkToolsFile(ctx context.Context, cmd *Command, lint bool) report {
+	rep := report{Errors: []finding{}, Warnings: []finding{}}
+	toolsFile, err := loadToolsFile(withPlaceholders(ctx), cmd)
+	if err != nil {
+		rep.Errors = append(rep.Errors, finding{Severity: severityError, Message: err.Error()})
+		return rep
+	}
+	rep.Errors = validateToolsFile(toolsFile)
+	if lint {
+		rep.Warnings = lintToolsFile(toolsFile)
+	}
+	rep.Valid = len(rep.Errors) == 0
+	return rep
+}
+
+func writeReport(w io.Writer, format string, rep report) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+	for _, f := range slices.Concat(rep.Errors, rep.Warnings) {
+		fmt.Fprintln(w, f)
+	}
+	if !rep.Valid {
+		fmt.Fprintf(w, "tools file is invalid (%d error(s), %d warning(s))\n", len(rep.Errors), len(rep.Warnings))
+		return nil
+	}
+	fmt.Fprintf(w, "tools file is valid (%d warning(s))\n", len(rep.Warnings))
+	return nil
+}
+
+// validateToolsFile checks the references between the resources of a parsed
+// tools file. Kinds and fields are already checked while parsing.
+func validateToolsFile(tf ToolsFile) []finding {
+	findings := []finding{}
+	addError := func(resource, name, format string, a ...any) {
+		findings = append(findings, finding{Severity: severityError, Resource: resource, Name: name, Message: fmt.Sprintf(format, a...)})
+	}
+
+	authServices := tf.AuthServices
+	if tf.AuthSources != nil {
+		// authSources is deprecated, but replaces authServices when set.
+		authServices = tf.AuthSources
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(tf.Tools)) {
+		tc := tf.Tools[name]
+		for _, src := range server.ToolSourceNames(tc) {
+			if _, ok := tf.Sources[src]; !ok {
+				addError("tool", name, "source %q does not exist", src)
+			}
+		}
+		authRequired, _ := toolConfigField(tc, "AuthRequired").([]string)
+		for _, a := range authRequired {
+			if _, ok := authServices[a]; !ok {
+				addError("tool", name, "authRequired %q is not a configured auth service", a)
+			}
+		}
+		if composed, ok := tc.(tools.ComposedToolConfig); ok {
+			for _, t := range composed.ToolNames() {
+				if _, ok := tf.Tools[t]; !ok {
+					addError("tool", name, "invoked tool %q does not exist", t)
+				}
+			}
+		}
+		params := toolConfigParameters(tc)
+		if err := tools.CheckDuplicateParameters(params); err != nil {
+			addError("tool", name, "%s", err)
+		}
+		for _, p := range params {
+			for _, a := range p.GetAuthServices() {
+				if _, ok := authServices[a.Name]; !ok {
+					addError("tool", name, "parameter %q uses auth service %q, which is not configured", p.GetName(), a.Name)
+				}
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(tf.Toolsets)) {
+		for _, t := range tf.Toolsets[name].ToolNames {
+			if _, ok := tf.Tools[t]; !ok {
+				addError("toolset", name, "tool %q does not exist", t)
+			}
+		}
+	}
+	return findings
+}
+
+// lintToolsFile warns about risky patterns in a parsed tools file.
+func lintToolsFile(tf ToolsFile) []finding {
+	findings := []finding{}
+	addWarning := func(rule, resource, name, format string, a ...any) {
+		findings = append(findings, finding{Severity: severityWarning, Rule: rule, Resource: resource, Name: name, Message: fmt.Sprintf(format, a...)})
+	}
+
+	usedSources := make(map[string]bool)
+	for _, name := range slices.Sorted(maps.Keys(tf.Tools)) {
+		tc := tf.Tools[name]
+		if src, ok := toolConfigField(tc, "Source").(string); ok {
+			usedSources[src] = true
+		}
+
+		if desc, ok := toolConfigField(tc, "Description").(string); ok && strings.TrimSpace(desc) == "" {
+			addWarning(ruleMissingDescription, "tool", name, "tool has no description")
+		}
+		for _, p := range toolConfigParameters(tc) {
+			if strings.TrimSpace(p.Manifest().Description) == "" {
+				addWarning(ruleMissingDescription, "tool", name, "parameter %q has no description", p.GetName())
+			}
+		}
+
+		authRequired, _ := toolConfigField(tc, "AuthRequired").([]string)
+		if strings.HasSuffix(tc.ToolConfigKind(), "-execute-sql") && len(authRequired) == 0 {
+			addWarning(ruleExecuteSQLWithoutAuth, "tool", name, "%q tools run arbitrary SQL and should set authRequired", tc.ToolConfigKind())
+		}
+
+		statement, _ := toolConfigField(tc, "Statement").(string)
+		templateParams, _ := toolConfigField(tc, "TemplateParameters").(tools.Parameters)
+		for _, p := range templateParams {
+			if unquotedTemplateParameter(statement, p.GetName()) {
+				addWarning(ruleUnquotedTemplateParameter, "tool", name, "template parameter %q is inserted into the statement without quotes", p.GetName())
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(tf.Sources)) {
+		if !usedSources[name] {
+			addWarning(ruleUnusedSource, "source", name, "source is not used by any tool")
+		}
+	}
+	return findings
+}
+
+// unquotedTemplateParameter reports whether the template parameter name is
+// inserted into statement without being enclosed in quotes.
+func unquotedTemplateParameter(statement, name string) bool {
+	re := regexp.MustCompile(`\{\{-?\s*\.` + regexp.QuoteMeta(name) + `\s*-?\}\}`)
+	for _, loc := range re.FindAllStringIndex(statement, -1) {
+		if loc[0] == 0 || loc[1] == len(statement) {
+			return true
+		}
+		before, after := statement[loc[0]-1], statement[loc[1]]
+		if !strings.ContainsRune("'\"`", rune(before)) || before != after {
+			return true
+		}
+	}
+	return false
+}
+
+// toolConfigField returns the value of the field name of a tool
+// configuration, looking into embedded tool configurations, or nil if there is
+// no such field.
+func toolConfigField(tc tools.ToolConfig, name string) any {
+	v := reflect.ValueOf(tc)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	if f := v.FieldByName(name); f.IsValid() && f.CanInterface() {
+		return f.Interface()
+	}
+	if f := v.FieldByName("ToolConfig"); f.IsValid() && f.CanInterface() {
+		if embedded, ok := f.Interface().(tools.ToolConfig); ok {
+			return toolConfigField(embedded, name)
+		}
+	}
+	return nil
+}
+
+// toolConfigParameters returns the parameters of a tool configuration, which
+// are all its fields of type tools.Parameters, such as parameters and
+// templateParameters.
+func toolConfigParameters(tc tools.ToolConfig) tools.Parameters {
+	v := reflect.ValueOf(tc)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var params tools.Parameters
+	for i := range v.NumField() {
+		f := v.Field(i)
+		if !f.CanInterface() {
+			continue
+		}
+		switch fv := f.Interface().(type) {
+		case tools.Parameters:
+			params = append(params, fv...)
+		case tools.ToolConfig:
+			if v.Type().Field(i).Anonymous {
+				params = append(params, toolConfigParameters(fv)...)
+			}
+		}
+	}
+	return params
+}
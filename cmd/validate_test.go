@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validateSources = `
+sources:
+  my-pg-instance:
+    kind: postgres
+    host: 127.0.0.1
+    port: 5432
+    database: my_db
+    user: my_user
+    password: my_pass
+`
+
+func TestValidateAndLint(t *testing.T) {
+	tcs := []struct {
+		desc         string
+		args         []string
+		in           string
+		wantErr      bool
+		parseErr     bool
+		wantErrors   []string
+		wantWarnings []string
+	}{
+		{
+			desc: "valid tools file",
+			args: []string{"validate"},
+			in: validateSources + `
+authServices:
+  my-google-auth:
+    kind: google
+    clientId: my-client-id
+tools:
+  search:
+    kind: postgres-sql
+    source: my-pg-instance
+    description: Search hotels by name.
+    statement: SELECT * FROM hotels WHERE name = $1 AND owner = $2
+    authRequired: [my-google-auth]
+    parameters:
+      - name: name
+        type: string
+        description: The name of the hotel.
+      - name: owner
+        type: string
+        description: The owner of the hotel.
+        authServices:
+          - name: my-google-auth
+            field: email
+toolsets:
+  my-toolset: [search]
+`,
+		},
+		{
+			desc: "invalid references",
+			args: []string{"validate"},
+			in: validateSources + `
+tools:
+  search:
+    kind: postgres-sql
+    source: other-pg-instance
+    description: Search hotels by name.
+    statement: SELECT * FROM hotels WHERE name = $1
+    authRequired: [my-google-auth]
+    parameters:
+      - name: name
+        type: string
+        description: The name of the hotel.
+        authServices:
+          - name: other-auth
+            field: email
+      - name: name
+        type: string
+        description: The name of the hotel.
+toolsets:
+  my-toolset: [search, missing]
+`,
+			wantErr: true,
+			wantErrors: []string{
+				`source "other-pg-instance" does not exist`,
+				`authRequired "my-google-auth" is not a configured auth service`,
+				`parameter name must be unique across all parameter fields. Duplicate parameter: name`,
+				`parameter "name" uses auth service "other-auth", which is not configured`,
+				`tool "missing" does not exist`,
+			},
+		},
+		{
+			desc: "missing cache source",
+			args: []string{"validate"},
+			in: validateSources + `
+tools:
+  search:
+    kind: postgres-sql
+    source: my-pg-instance
+    description: Search hotels.
+    statement: SELECT * FROM hotels
+    cache:
+      source: my-redis
+`,
+			wantErr:    true,
+			wantErrors: []string{`source "my-redis" does not exist`},
+		},
+		{
+			// secrets are not read, and unset variables do not fail
+			desc: "secret references and unset environment variables",
+			args: []string{"validate"},
+			in: `
+sources:
+  my-pg-instance:
+    kind: postgres
+    host: ${TOOLBOX_TEST_UNSET_HOST}
+    port: ${TOOLBOX_TEST_UNSET_PORT}
+    database: my_db
+    user: ${file:/does/not/exist}
+    password: ${vault:kv/data/pg#password}
+tools:
+  search:
+    kind: postgres-sql
+    source: my-pg-instance
+    description: Search hotels.
+    statement: SELECT * FROM hotels
+`,
+		},
+		{
+			desc:     "unknown kind",
+			args:     []string{"validate"},
+			in:       validateSources + "tools:\n  search:\n    kind: unknown-kind\n",
+			wantErr:  true,
+			parseErr: true,
+		},
+		{
+			desc: "lint warnings",
+			args: []string{"lint"},
+			in: validateSources + `
+  other-pg-instance:
+    kind: postgres
+    host: 127.0.0.1
+    port: 5432
+    database: my_db
+    user: my_user
+    password: my_pass
+tools:
+  execute:
+    kind: postgres-execute-sql
+    source: my-pg-instance
+    description: " "
+  list:
+    kind: postgres-sql
+    source: my-pg-instance
+    description: List rows of a table.
+    statement: SELECT * FROM {{.table}} WHERE name = '{{.name}}'
+    templateParameters:
+      - name: table
+        type: string
+        description: The table.
+      - name: name
+        type: string
+        description: The name.
+`,
+			wantWarnings: []string{
+				`tool has no description`,
+				`"postgres-execute-sql" tools run arbitrary SQL and should set authRequired`,
+				`template parameter "table" is inserted into the statement without quotes`,
+				`source is not used by any tool`,
+			},
+		},
+		{
+			desc:         "lint strict",
+			args:         []string{"lint", "--strict"},
+			in:           validateSources + "tools:\n  execute:\n    kind: postgres-execute-sql\n    source: my-pg-instance\n    description: Run SQL.\n",
+			wantErr:      true,
+			wantWarnings: []string{`"postgres-execute-sql" tools run arbitrary SQL and should set authRequired`},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tools.yaml")
+			if err := os.WriteFile(path, []byte(tc.in), 0o600); err != nil {
+				t.Fatalf("unable to write tools file: %s", err)
+			}
+			args := append(tc.args, "--tools-file", path, "--format", "json")
+			_, out, err := invokeCommand(args)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: got %v, want error %t", err, tc.wantErr)
+			}
+
+			var got report
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("unable to parse output %q: %s", out, err)
+			}
+			if wantValid := !tc.parseErr && len(tc.wantErrors) == 0; got.Valid != wantValid {
+				t.Errorf("unexpected valid: got %t, want %t", got.Valid, wantValid)
+			}
+			if tc.parseErr {
+				// the message of parse errors is checked by the parsing tests
+				if len(got.Errors) != 1 {
+					t.Errorf("unexpected errors: got %v, want 1 error", got.Errors)
+				}
+			} else if diff := cmp.Diff(tc.wantErrors, messages(got.Errors)); diff != "" {
+				t.Errorf("unexpected errors (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantWarnings, messages(got.Warnings)); diff != "" {
+				t.Errorf("unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func messages(findings []finding) []string {
+	var msgs []string
+	for _, f := range findings {
+		msgs = append(msgs, f.Message)
+	}
+	return msgs
+}
+
+func TestUnquotedTemplateParameter(t *testing.T) {
+	tcs := []struct {
+		statement string
+		want      bool
+	}{
+		{statement: "SELECT * FROM {{.table}}", want: true},
+		{statement: "SELECT * FROM {{ .table }} LIMIT 10", want: true},
+		{statement: `SELECT * FROM "{{.table}}"`, want: false},
+		{statement: "SELECT * FROM `{{.table}}`", want: false},
+		{statement: `SELECT * FROM '{{.table}}"`, want: true},
+		{statement: `SELECT * FROM '{{.table}}' JOIN {{.table}}`, want: true},
+		{statement: "SELECT * FROM {{.tables}}", want: false},
+		{statement: "SELECT * FROM hotels", want: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.statement, func(t *testing.T) {
+			if got := unquotedTemplateParameter(tc.statement, "table"); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records tool invocations to one or more sinks. Every entry
+// carries the hash of the entry written before it, so removing or editing an
+// entry breaks the chain and can be detected with Verify.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TransportREST    = "rest"
+	TransportMCPHTTP = "mcp-http"
+	TransportSSE     = "sse"
+	TransportStdio   = "stdio"
+
+	// RedactedValue replaces parameter values that match a redaction pattern.
+	RedactedValue = "[REDACTED]"
+)
+
+// Config defines where audit entries are written and what they contain.
+type Config struct {
+	// File is the path of a JSON lines file that entries are appended to.
+	File string
+	// FileMaxSizeMB is the size at which the audit file is rotated.
+	FileMaxSizeMB int
+	// FileMaxBackups is the number of rotated files that are kept.
+	FileMaxBackups int
+	// Stdout enables writing entries to the standard output stream.
+	Stdout bool
+	// OTLP is the OTLP collector endpoint that entries are exported to as logs.
+	OTLP string
+	// Redact is a list of case-insensitive glob patterns. Parameters with a
+	// matching name are recorded as RedactedValue.
+	Redact []string
+	// Claims is the list of claims recorded for each verified auth service.
+	Claims []string
+}
+
+// Enabled returns true if at least one sink is configured.
+func (c Config) Enabled() bool {
+	return c.File != "" || c.Stdout || c.OTLP != ""
+}
+
+// Entry is a single audited tool invocation.
+type Entry struct {
+	Timestamp   time.Time                 `json:"timestamp"`
+	Transport   string                    `json:"transport"`
+	Toolset     string                    `json:"toolset"`
+	Tool        string                    `json:"tool"`
+	Principals  map[string]map[string]any `json:"principals,omitempty"`
+	Parameters  map[string]any            `json:"parameters,omitempty"`
+	ResultRows  int                       `json:"resultRows"`
+	ResultBytes int                       `json:"resultBytes"`
+	DurationMs  float64                   `json:"durationMs"`
+	Error       string                    `json:"error,omitempty"`
+	PrevHash    string                    `json:"prevHash"`
+	Hash        string                    `json:"hash"`
+}
+
+// computeHash returns the chained hash of the entry. The Hash field itself is
+// excluded from the calculation.
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal audit entry: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(e.PrevHash), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Sink is a destination for audit entries.
+type Sink interface {
+	Write(context.Context, Entry) error
+	Close(context.Context) error
+}
+
+// Auditor chains entries together and fans them out to its sinks.
+type Auditor struct {
+	mu       sync.Mutex
+	sinks    []Sink
+	redact   []string
+	claims   []string
+	prevHash string
+}
+
+// New returns an Auditor with the sinks described by cfg. Entries destined for
+// standard output are written to out. A nil Auditor is returned if no sinks
+// are configured.
+func New(ctx context.Context, cfg Config, out io.Writer) (*Auditor, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	a := &Auditor{claims: cfg.Claims}
+	for _, p := range cfg.Redact {
+		a.redact = append(a.redact, strings.ToLower(p))
+	}
+
+	if cfg.File != "" {
+		fs, err := newFileSink(cfg.File, cfg.FileMaxSizeMB, cfg.FileMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		a.sinks = append(a.sinks, fs)
+		// continue the chain of an existing audit file
+		a.prevHash = fs.lastHash
+	}
+	if cfg.Stdout {
+		a.sinks = append(a.sinks, newWriterSink(out))
+	}
+	if cfg.OTLP != "" {
+		otlp, err := newOTLPSink(ctx, cfg.OTLP)
+		if err != nil {
+			_ = a.Close(ctx)
+			return nil, err
+		}
+		a.sinks = append(a.sinks, otlp)
+	}
+	return a, nil
+}
+
+// Record completes the hash chain for e and writes it to every sink.
+func (a *Auditor) Record(ctx context.Context, e Entry) error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e.PrevHash = a.prevHash
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+	a.prevHash = hash
+
+	var errs error
+	for _, s := range a.sinks {
+		if err := s.Write(ctx, e); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// Close flushes and closes every sink.
+func (a *Auditor) Close(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var errs error
+	for _, s := range a.sinks {
+		errs = errors.Join(errs, s.Close(ctx))
+	}
+	a.sinks = nil
+	return errs
+}
+
+// isRedacted returns true if the parameter name matches a redaction pattern.
+func (a *Auditor) isRedacted(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range a.redact {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// principals keeps the configured claims of each verified auth service.
+func (a *Auditor) principals(claimsFromAuth map[string]map[string]any) map[string]map[string]any {
+	if len(claimsFromAuth) == 0 {
+		return nil
+	}
+	p := make(map[string]map[string]any, len(claimsFromAuth))
+	for name, claims := range claimsFromAuth {
+		kept := make(map[string]any)
+		for _, c := range a.claims {
+			if v, ok := claims[c]; ok {
+				kept[c] = v
+			}
+		}
+		p[name] = kept
+	}
+	return p
+}
+
+// Verify reads JSON lines audit entries from r and checks that the hash chain
+// is intact. The first entry may continue a chain from a rotated file.
+func Verify(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	prev := ""
+	line := 0
+	first := true
+	for scanner.Scan() {
+		line++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		// decode numbers as json.Number so they are re-encoded byte for byte
+		var e Entry
+		d := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		d.UseNumber()
+		if err := d.Decode(&e); err != nil {
+			return fmt.Errorf("line %d: unable to parse audit entry: %w", line, err)
+		}
+		if !first && e.PrevHash != prev {
+			return fmt.Errorf("line %d: chain broken: previous hash %q does not match %q", line, e.PrevHash, prev)
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if hash != e.Hash {
+			return fmt.Errorf("line %d: entry has been modified", line)
+		}
+		prev = e.Hash
+		first = false
+	}
+	return scanner.Err()
+}
+
+type contextKey string
+
+const (
+	auditorKey   contextKey = "auditor"
+	transportKey contextKey = "auditTransport"
+	toolsetKey   contextKey = "auditToolset"
+)
+
+// WithAuditor adds an auditor into the context as a value.
+func WithAuditor(ctx context.Context, a *Auditor) context.Context {
+	return context.WithValue(ctx, auditorKey, a)
+}
+
+// FromContext retrieves the auditor, or nil if auditing is disabled.
+func FromContext(ctx context.Context) *Auditor {
+	a, _ := ctx.Value(auditorKey).(*Auditor)
+	return a
+}
+
+// WithRequestInfo adds the transport and toolset of the current request into
+// the context so they can be recorded with the invocation.
+func WithRequestInfo(ctx context.Context, transport, toolset string) context.Context {
+	ctx = context.WithValue(ctx, transportKey, transport)
+	return context.WithValue(ctx, toolsetKey, toolset)
+}
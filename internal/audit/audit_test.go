@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func invoke(ctx context.Context, toolName string, params tools.ParamValues, rows, size int, err error) {
+	inv := audit.Begin(ctx, toolName)
+	inv.SetPrincipals(map[string]map[string]any{
+		"my-google-auth": {"sub": "1234", "email": "alice@example.com", "name": "Alice"},
+	})
+	inv.SetParams(params)
+	inv.SetResult(rows, size)
+	inv.End(ctx, err)
+}
+
+func TestInvocation(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	a, err := audit.New(ctx, audit.Config{
+		Stdout: true,
+		Redact: []string{"*PASSWORD*", "pin"},
+		Claims: []string{"email"},
+	}, &buf)
+	if err != nil {
+		t.Fatalf("unable to create auditor: %s", err)
+	}
+	ctx = audit.WithRequestInfo(audit.WithAuditor(ctx, a), audit.TransportSSE, "my-toolset")
+
+	params := tools.ParamValues{
+		{Name: "id", Value: 3},
+		{Name: "db_password", Value: "hunter2"},
+		{Name: "PIN", Value: "0000"},
+	}
+	invoke(ctx, "my-tool", params, 2, 17, nil)
+	invoke(ctx, "my-tool", nil, 0, 0, errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d: %s", len(lines), buf.String())
+	}
+	var got audit.Entry
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("unable to unmarshal audit entry: %s", err)
+	}
+	if got.Transport != audit.TransportSSE || got.Toolset != "my-toolset" || got.Tool != "my-tool" {
+		t.Errorf("unexpected request info: %+v", got)
+	}
+	wantParams := map[string]any{"id": float64(3), "db_password": audit.RedactedValue, "PIN": audit.RedactedValue}
+	if diff := cmp.Diff(wantParams, got.Parameters); diff != "" {
+		t.Errorf("unexpected parameters (-want +got):\n%s", diff)
+	}
+	wantPrincipals := map[string]map[string]any{"my-google-auth": {"email": "alice@example.com"}}
+	if diff := cmp.Diff(wantPrincipals, got.Principals); diff != "" {
+		t.Errorf("unexpected principals (-want +got):\n%s", diff)
+	}
+	if got.ResultRows != 2 || got.ResultBytes != 17 {
+		t.Errorf("unexpected result size: rows %d, bytes %d", got.ResultRows, got.ResultBytes)
+	}
+
+	var second audit.Entry
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("unable to unmarshal audit entry: %s", err)
+	}
+	if second.Error != "boom" {
+		t.Errorf("expected error to be recorded, got %q", second.Error)
+	}
+	if second.PrevHash != got.Hash {
+		t.Errorf("expected entries to be chained")
+	}
+	if err := audit.Verify(&buf); err != nil {
+		t.Errorf("unexpected verification error: %s", err)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	a, err := audit.New(context.Background(), audit.Config{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a != nil {
+		t.Fatalf("expected nil auditor when no sinks are configured")
+	}
+	// must not panic without an auditor in the context
+	invoke(context.Background(), "my-tool", nil, 0, 0, nil)
+}
+
+func TestVerifyTampered(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	a, err := audit.New(ctx, audit.Config{Stdout: true}, &buf)
+	if err != nil {
+		t.Fatalf("unable to create auditor: %s", err)
+	}
+	ctx = audit.WithAuditor(ctx, a)
+	for range 3 {
+		invoke(ctx, "my-tool", tools.ParamValues{{Name: "id", Value: 1}}, 0, 0, nil)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	tcs := []struct {
+		desc  string
+		lines []string
+	}{
+		{
+			desc:  "modified entry",
+			lines: []string{lines[0], strings.Replace(lines[1], "my-tool", "other-tool", 1), lines[2]},
+		},
+		{
+			desc:  "removed entry",
+			lines: []string{lines[0], lines[2]},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := audit.Verify(strings.NewReader(strings.Join(tc.lines, "\n")))
+			if err == nil {
+				t.Fatalf("expected verification to fail")
+			}
+		})
+	}
+}
+
+func TestFileRotation(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := audit.Config{File: file, FileMaxSizeMB: 1, FileMaxBackups: 2}
+	a, err := audit.New(ctx, cfg, nil)
+	if err != nil {
+		t.Fatalf("unable to create auditor: %s", err)
+	}
+	ctx = audit.WithAuditor(ctx, a)
+	// each entry is roughly 100KB, so the file rotates every ~10 entries
+	big := strings.Repeat("x", 100*1024)
+	for range 35 {
+		invoke(ctx, "my-tool", tools.ParamValues{{Name: "blob", Value: big}}, 0, 0, nil)
+	}
+	if err := a.Close(ctx); err != nil {
+		t.Fatalf("unable to close auditor: %s", err)
+	}
+
+	for _, f := range []string{file, file + ".1", file + ".2"} {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("expected audit file %q to exist: %s", f, err)
+		}
+	}
+	if _, err := os.Stat(file + ".3"); err == nil {
+		t.Errorf("expected at most 2 backups")
+	}
+
+	// chain continues across rotation and restarts
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unable to read audit file: %s", err)
+	}
+	a, err = audit.New(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("unable to reopen auditor: %s", err)
+	}
+	ctx = audit.WithAuditor(context.Background(), a)
+	invoke(ctx, "my-tool", nil, 0, 0, nil)
+	if err := a.Close(ctx); err != nil {
+		t.Fatalf("unable to close auditor: %s", err)
+	}
+	after, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unable to read audit file: %s", err)
+	}
+	if !bytes.HasPrefix(after, b) {
+		t.Fatalf("expected entries to be appended")
+	}
+	if err := audit.Verify(bytes.NewReader(after)); err != nil {
+		t.Errorf("unexpected verification error: %s", err)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Invocation collects the details of a single tool invocation. It is safe to
+// use when auditing is disabled, in which case every method is a no-op.
+type Invocation struct {
+	auditor *Auditor
+	start   time.Time
+	entry   Entry
+}
+
+// Begin starts auditing an invocation of the named tool. The auditor,
+// transport and toolset are taken from ctx. End must be called once the
+// invocation is complete.
+func Begin(ctx context.Context, toolName string) *Invocation {
+	a := FromContext(ctx)
+	if a == nil {
+		return &Invocation{}
+	}
+	transport, _ := ctx.Value(transportKey).(string)
+	toolset, _ := ctx.Value(toolsetKey).(string)
+	now := time.Now()
+	return &Invocation{
+		auditor: a,
+		start:   now,
+		entry: Entry{
+			Timestamp: now.UTC(),
+			Transport: transport,
+			Toolset:   toolset,
+			Tool:      toolName,
+		},
+	}
+}
+
+// SetPrincipals records the configured claims of each verified auth service.
+func (i *Invocation) SetPrincipals(claimsFromAuth map[string]map[string]any) {
+	if i.auditor == nil {
+		return
+	}
+	i.entry.Principals = i.auditor.principals(claimsFromAuth)
+}
+
+// SetParams records the parsed parameter values, redacting sensitive ones.
+func (i *Invocation) SetParams(params tools.ParamValues) {
+	if i.auditor == nil {
+		return
+	}
+	p := make(map[string]any, len(params))
+	for _, v := range params {
+		if i.auditor.isRedacted(v.Name) {
+			p[v.Name] = RedactedValue
+			continue
+		}
+		p[v.Name] = v.Value
+	}
+	i.entry.Parameters = p
+}
+
+// SetResult records the number of rows of the result and its size as encoded
+// in the response.
+func (i *Invocation) SetResult(rows, size int) {
+	if i.auditor == nil {
+		return
+	}
+	i.entry.ResultRows, i.entry.ResultBytes = rows, size
+}
+
+// End records the invocation with err as its outcome.
+func (i *Invocation) End(ctx context.Context, err error) {
+	if i.auditor == nil {
+		return
+	}
+	i.entry.DurationMs = float64(time.Since(i.start).Microseconds()) / 1000
+	if err != nil {
+		i.entry.Error = err.Error()
+	}
+	if err := i.auditor.Record(ctx, i.entry); err != nil {
+		if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("unable to write audit entry: %s", err))
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+const (
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 5
+	otlpLoggerName    = "github.com/googleapis/genai-toolbox/internal/audit"
+)
+
+var _ Sink = &writerSink{}
+
+// writerSink writes each entry as a JSON line to an io.Writer.
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newWriterSink(w io.Writer) *writerSink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(_ context.Context, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit entry: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintf(s.w, "%s\n", b)
+	return err
+}
+
+func (s *writerSink) Close(context.Context) error {
+	return nil
+}
+
+var _ Sink = &fileSink{}
+
+// fileSink appends JSON lines to a file and rotates it once it grows past
+// maxSize. Rotated files are renamed to <path>.1, <path>.2, ... with the
+// highest number being the oldest.
+type fileSink struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	// lastHash is the hash of the last entry found in an existing file.
+	lastHash string
+}
+
+func newFileSink(path string, maxSizeMB, maxBackups int) (*fileSink, error) {
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultMaxSizeMB
+	}
+	if maxBackups <= 0 {
+		maxBackups = defaultMaxBackups
+	}
+	s := &fileSink{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	lastHash, err := readLastHash(path)
+	if err != nil {
+		return nil, err
+	}
+	s.lastHash = lastHash
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open audit file %q: %w", s.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("unable to stat audit file %q: %w", s.path, err)
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+// rotate shifts existing backups by one and moves the current file to <path>.1.
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("unable to close audit file %q: %w", s.path, err)
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", s.path, i)
+		if _, err := os.Stat(src); err == nil {
+			if err := os.Rename(src, fmt.Sprintf("%s.%d", s.path, i+1)); err != nil {
+				return fmt.Errorf("unable to rotate audit file %q: %w", src, err)
+			}
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return fmt.Errorf("unable to rotate audit file %q: %w", s.path, err)
+	}
+	return s.open()
+}
+
+func (s *fileSink) Write(_ context.Context, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit entry: %w", err)
+	}
+	b = append(b, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("audit file %q is closed", s.path)
+	}
+	if s.size > 0 && s.size+int64(len(b)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(b)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("unable to write audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSink) Close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// readLastHash returns the hash of the last entry in an existing audit file.
+func readLastHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to open audit file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("unable to stat audit file %q: %w", path, err)
+	}
+	// read a growing tail of the file until it holds the whole last line
+	var last []byte
+	for tailSize := int64(64 * 1024); ; tailSize *= 2 {
+		offset := max(info.Size()-tailSize, 0)
+		buf := make([]byte, info.Size()-offset)
+		if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+			return "", fmt.Errorf("unable to read audit file %q: %w", path, err)
+		}
+		buf = bytes.TrimSpace(buf)
+		i := bytes.LastIndexByte(buf, '\n')
+		if i >= 0 || offset == 0 {
+			last = buf[i+1:]
+			break
+		}
+	}
+	if len(last) == 0 {
+		return "", nil
+	}
+	var e Entry
+	if err := json.Unmarshal(last, &e); err != nil {
+		return "", fmt.Errorf("unable to parse last entry of audit file %q: %w", path, err)
+	}
+	return e.Hash, nil
+}
+
+var _ Sink = &otlpSink{}
+
+// otlpSink exports entries as OpenTelemetry log records.
+type otlpSink struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+func newOTLPSink(ctx context.Context, endpoint string) (*otlpSink, error) {
+	// otlploghttp provides an OTLP log exporter using HTTP with protobuf payloads.
+	// By default, the logs are sent to https://localhost:4318/v1/logs.
+	exporter, err := otlploghttp.New(ctx, otlploghttp.WithEndpoint(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP audit exporter: %w", err)
+	}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+	return &otlpSink{
+		provider: provider,
+		logger:   provider.Logger(otlpLoggerName),
+	}, nil
+}
+
+func (s *otlpSink) Write(ctx context.Context, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit entry: %w", err)
+	}
+
+	var r otellog.Record
+	r.SetTimestamp(e.Timestamp)
+	r.SetEventName("toolbox.audit.tool_invocation")
+	r.SetSeverity(otellog.SeverityInfo)
+	if e.Error != "" {
+		r.SetSeverity(otellog.SeverityWarn)
+	}
+	r.SetBody(otellog.StringValue(string(b)))
+	r.AddAttributes(
+		otellog.String("toolbox.audit.transport", e.Transport),
+		otellog.String("toolbox.audit.toolset", e.Toolset),
+		otellog.String("toolbox.audit.tool", e.Tool),
+		otellog.Int("toolbox.audit.result.rows", e.ResultRows),
+		otellog.Int("toolbox.audit.result.bytes", e.ResultBytes),
+		otellog.Float64("toolbox.audit.duration_ms", e.DurationMs),
+		otellog.String("toolbox.audit.hash", e.Hash),
+	)
+	s.logger.Emit(ctx, r)
+	return nil
+}
+
+func (s *otlpSink) Close(ctx context.Context) error {
+	return s.provider.Shutdown(ctx)
+}
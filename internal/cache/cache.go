@@ -0,0 +1,288 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides an opt-in response cache for idempotent tools.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	defaultTTL        = 5 * time.Minute
+	defaultMaxEntries = 1000
+	keyPrefix         = "toolbox:cache:"
+)
+
+// Config is the `cache` block of a tool.
+type Config struct {
+	// TTL is how long a result is served from the cache, e.g. "30s" or "5m".
+	TTL string `yaml:"ttl"`
+	// MaxEntries is the number of results kept by the in-memory cache.
+	MaxEntries int `yaml:"maxEntries"`
+	// PerPrincipal keys results by the verified claims and access token of
+	// the caller in addition to the parameters.
+	PerPrincipal bool `yaml:"perPrincipal"`
+	// Source optionally names a redis or valkey source to store results in
+	// instead of memory.
+	Source string `yaml:"source"`
+}
+
+// ToolConfig wraps the config of a tool with a cache.
+type ToolConfig struct {
+	tools.ToolConfig
+	Name  string
+	Cache Config
+}
+
+var _ tools.ComposedToolConfig = ToolConfig{}
+
+// Unwrap returns the config of the cached tool.
+func (cfg ToolConfig) Unwrap() tools.ToolConfig { return cfg.ToolConfig }
+
+// ToolNames returns the tools invoked by the cached tool.
+func (cfg ToolConfig) ToolNames() []string {
+	return tools.WrappedToolNames(cfg.ToolConfig)
+}
+
+func (cfg ToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return cfg.InitializeWithTools(srcs, nil)
+}
+
+func (cfg ToolConfig) InitializeWithTools(srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	ttl := defaultTTL
+	if cfg.Cache.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(cfg.Cache.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid cache ttl %q: must be a positive duration", cfg.Cache.TTL)
+		}
+	}
+
+	var store Store
+	if cfg.Cache.Source != "" {
+		s, ok := srcs[cfg.Cache.Source]
+		if !ok {
+			return nil, fmt.Errorf("no source named %q configured for cache", cfg.Cache.Source)
+		}
+		var err error
+		store, err = newSourceStore(s)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		maxEntries := cfg.Cache.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultMaxEntries
+		}
+		store = NewLRU(maxEntries)
+	}
+
+	t, err := tools.InitializeWrapped(cfg.ToolConfig, srcs, toolsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys are namespaced by the tool config, so that a shared store does not
+	// serve results of a tool that has since been changed.
+	b, err := json.Marshal(cfg.ToolConfig)
+	if err != nil {
+		b = []byte(cfg.Name)
+	}
+	sum := sha256.Sum256(b)
+
+	// The tool, or the tools it invokes, may use any source routing requests
+	// by tenant.
+	tenants := make(map[string]sources.TenantSource)
+	for name, s := range srcs {
+		if ts, ok := s.(sources.TenantSource); ok {
+			tenants[name] = ts
+		}
+	}
+	return Tool{
+		Tool:         t,
+		tenants:      tenants,
+		store:        store,
+		ttl:          ttl,
+		perPrincipal: cfg.Cache.PerPrincipal,
+		toolName:     cfg.Name,
+		prefix:       keyPrefix + hex.EncodeToString(sum[:8]) + ":",
+	}, nil
+}
+
+// Tool serves results of the wrapped tool from a Store.
+type Tool struct {
+	tools.Tool
+	store        Store
+	ttl          time.Duration
+	perPrincipal bool
+	toolName     string
+	prefix       string
+	tenants      map[string]sources.TenantSource
+}
+
+var _ tools.Tool = Tool{}
+
+// Unwrap returns the wrapped tool.
+func (t Tool) Unwrap() tools.Tool { return t.Tool }
+
+// entry is the encoded form of a cached result.
+type entry struct {
+	Result json.RawMessage `json:"result"`
+	// Paged is set if the result was a tools.PagedResult.
+	Paged         bool   `json:"paged,omitempty"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	// Columns is the order of the columns of the rows of the result, if it
+	// was recorded.
+	Columns []string `json:"columns,omitempty"`
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	// streamed rows are not returned, so they cannot be cached
+	if tools.RowSinkFromContext(ctx) != nil {
+		return t.Tool.Invoke(ctx, params, accessToken)
+	}
+	key, err := t.key(ctx, params, accessToken)
+	if err != nil {
+		return t.Tool.Invoke(ctx, params, accessToken)
+	}
+
+	b, ok, err := t.store.Get(ctx, key)
+	if err != nil {
+		t.warn(ctx, fmt.Sprintf("unable to read from cache: %s", err))
+	}
+	if ok {
+		res, columns, err := decode(b)
+		if err == nil {
+			t.count(ctx, true)
+			tools.SetColumns(ctx, columns)
+			return res, nil
+		}
+		t.warn(ctx, fmt.Sprintf("unable to decode cached result: %s", err))
+	}
+	t.count(ctx, false)
+
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		// errors are never cached
+		return res, err
+	}
+	if b, err := encode(res, tools.ColumnOrderFromContext(ctx).Columns()); err != nil {
+		t.warn(ctx, fmt.Sprintf("unable to encode result for cache: %s", err))
+	} else if err := t.store.Set(ctx, key, b, t.ttl); err != nil {
+		t.warn(ctx, fmt.Sprintf("unable to write to cache: %s", err))
+	}
+	return res, nil
+}
+
+// key returns the cache key of an invocation.
+func (t Tool) key(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (string, error) {
+	k := map[string]any{"params": params.AsMap()}
+	// rows filtered by a data policy depend on the claims of the caller
+	if filters := tools.RowFiltersFromContext(ctx); len(filters) > 0 {
+		k["rowFilters"] = filters
+	}
+	// results of sources routing requests by tenant depend on the tenant
+	tenants := make(map[string]string)
+	for name, s := range t.tenants {
+		// requests whose tenant cannot be determined fail, and are not cached
+		if tenant, err := s.Tenant(ctx); err == nil && tenant != "" {
+			tenants[name] = tenant
+		}
+	}
+	if len(tenants) > 0 {
+		k["tenants"] = tenants
+	}
+	if t.perPrincipal {
+		k["claims"] = util.ClaimsFromContext(ctx)
+		if accessToken != "" {
+			sum := sha256.Sum256([]byte(accessToken))
+			k["token"] = hex.EncodeToString(sum[:])
+		}
+	}
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return t.prefix + hex.EncodeToString(sum[:]), nil
+}
+
+func (t Tool) count(ctx context.Context, hit bool) {
+	instrumentation, err := util.InstrumentationFromContext(ctx)
+	if err != nil {
+		return
+	}
+	attrs := metric.WithAttributes(attribute.String("toolbox.name", t.toolName))
+	if hit {
+		instrumentation.CacheHit.Add(ctx, 1, attrs)
+	} else {
+		instrumentation.CacheMiss.Add(ctx, 1, attrs)
+	}
+}
+
+func (t Tool) warn(ctx context.Context, msg string) {
+	if logger, err := util.LoggerFromContext(ctx); err == nil {
+		logger.WarnContext(ctx, msg)
+	}
+}
+
+func encode(res any, columns []string) ([]byte, error) {
+	e := entry{Columns: columns}
+	if p, ok := res.(tools.PagedResult); ok {
+		res = p.Rows
+		e.Paged = true
+		e.NextPageToken = p.NextPageToken
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	e.Result = b
+	return json.Marshal(e)
+}
+
+func decode(b []byte) (any, []string, error) {
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, nil, err
+	}
+	// use json.Number so that large integers are returned unchanged
+	d := json.NewDecoder(bytes.NewReader(e.Result))
+	d.UseNumber()
+	var res any
+	if err := d.Decode(&res); err != nil {
+		return nil, nil, err
+	}
+	if e.Paged {
+		rows, _ := res.([]any)
+		if rows == nil {
+			rows = []any{}
+		}
+		return tools.PagedResult{Rows: rows, NextPageToken: e.NextPageToken}, e.Columns, nil
+	}
+	return res, e.Columns, nil
+}
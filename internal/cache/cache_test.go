@@ -0,0 +1,435 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/cache"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/pipeline"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestParseFromYamlCache(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM t;
+			cache:
+				ttl: 30s
+				maxEntries: 10
+				perPrincipal: true
+	`
+	want := server.ToolConfigs{
+		"example_tool": cache.ToolConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "example_tool",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT * FROM t;",
+				AuthRequired: []string{},
+			},
+			Name:  "example_tool",
+			Cache: cache.Config{TTL: "30s", MaxEntries: 10, PerPrincipal: true},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFailParseFromYamlCache(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc  string
+		cache string
+		err   string
+	}{
+		{
+			desc:  "extra field",
+			cache: "foo: bar",
+			err:   `unknown field "foo"`,
+		},
+		{
+			desc:  "invalid ttl",
+			cache: "ttl: soon",
+			err:   `invalid ttl "soon"`,
+		},
+		{
+			desc:  "negative ttl",
+			cache: "ttl: -1m",
+			err:   `invalid ttl "-1m"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			in := `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT * FROM t;
+					cache:
+						` + tc.cache
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestTool(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{Name: "my-tool", Result: []any{map[string]any{"id": 9007199254740993}}, Calls: &calls},
+		Name:       "my-tool",
+		Cache:      cache.Config{TTL: "1m"},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	params := tools.ParamValues{{Name: "id", Value: 1}}
+	first, err := tool.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := tool.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected second invocation to be served from cache, got %d calls", calls)
+	}
+	firstJSON, _ := json.Marshal(first)
+	secondJSON, _ := json.Marshal(second)
+	if string(firstJSON) != string(secondJSON) {
+		t.Errorf("unexpected cached result: got %s, want %s", secondJSON, firstJSON)
+	}
+
+	if _, err := tool.Invoke(ctx, tools.ParamValues{{Name: "id", Value: 2}}, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected invocation with other parameters to miss the cache, got %d calls", calls)
+	}
+
+	// a new tool from the same config, e.g. after a reload, starts empty
+	tool, err = cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	if _, err := tool.Invoke(ctx, params, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected cache to be empty after reinitialization, got %d calls", calls)
+	}
+}
+
+func TestToolPipeline(t *testing.T) {
+	var calls int
+	list, err := toolstest.Config{Name: "list", Result: []any{"row"}, Calls: &calls}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	cfg := cache.ToolConfig{
+		ToolConfig: pipeline.Config{
+			Name:        "explore",
+			Kind:        "pipeline",
+			Description: "some description",
+			Steps:       []pipeline.StepConfig{{Name: "list", Tool: "list"}},
+		},
+		Name:  "explore",
+		Cache: cache.Config{TTL: "1m"},
+	}
+	if diff := cmp.Diff([]string{"list"}, cfg.ToolNames()); diff != "" {
+		t.Fatalf("unexpected tool names (-want +got):\n%s", diff)
+	}
+	tool, err := cfg.InitializeWithTools(nil, map[string]tools.Tool{"list": list})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	for range 2 {
+		if _, err := tool.Invoke(context.Background(), nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected second invocation of the pipeline to be served from cache, got %d calls", calls)
+	}
+}
+
+func TestToolPerPrincipal(t *testing.T) {
+	var calls int
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{Name: "my-tool", Result: []any{"row"}, Calls: &calls},
+		Name:       "my-tool",
+		Cache:      cache.Config{PerPrincipal: true},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	alice := util.WithClaims(context.Background(), map[string]map[string]any{"my-auth": {"sub": "alice"}})
+	bob := util.WithClaims(context.Background(), map[string]map[string]any{"my-auth": {"sub": "bob"}})
+	for _, ctx := range []context.Context{alice, bob, alice, bob} {
+		if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected one invocation per principal, got %d calls", calls)
+	}
+	if _, err := tool.Invoke(alice, nil, "other-token"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected access token to be part of the key, got %d calls", calls)
+	}
+}
+
+// tenantSource selects the tenant of a request from the X-Tenant header.
+type tenantSource struct{}
+
+func (tenantSource) SourceKind() string { return "tenant" }
+
+func (tenantSource) Tenant(ctx context.Context) (string, error) {
+	tenant := util.HeaderFromContext(ctx).Get("X-Tenant")
+	if tenant == "" {
+		return "", errors.New("missing tenant")
+	}
+	return tenant, nil
+}
+
+func TestToolTenants(t *testing.T) {
+	var calls int
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{Name: "my-tool", Result: []any{"row"}, Calls: &calls},
+		Name:       "my-tool",
+	}
+	tool, err := cfg.Initialize(map[string]sources.Source{"my-hana": tenantSource{}})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	withTenant := func(tenant string) context.Context {
+		header := http.Header{}
+		header.Set("X-Tenant", tenant)
+		return util.WithHeader(context.Background(), header)
+	}
+	for _, tenant := range []string{"acme", "acme", "globex"} {
+		if _, err := tool.Invoke(withTenant(tenant), nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected results to be cached per tenant, got %d calls", calls)
+	}
+}
+
+func TestToolRowFilters(t *testing.T) {
+	var calls int
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{Name: "my-tool", Result: []any{"row"}, Calls: &calls},
+		Name:       "my-tool",
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	acme := tools.WithRowFilters(context.Background(), []tools.RowFilter{{Column: "TENANT", Value: "acme"}})
+	initech := tools.WithRowFilters(context.Background(), []tools.RowFilter{{Column: "TENANT", Value: "initech"}})
+	for _, ctx := range []context.Context{acme, initech, acme} {
+		if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected one invocation per row filter, got %d calls", calls)
+	}
+}
+
+func TestToolErrorsNotCached(t *testing.T) {
+	var calls int
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{Name: "my-tool", Err: errors.New("boom"), Calls: &calls},
+		Name:       "my-tool",
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	for range 2 {
+		if _, err := tool.Invoke(context.Background(), nil, ""); err == nil {
+			t.Fatalf("expected error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected errors not to be cached, got %d calls", calls)
+	}
+}
+
+func TestToolPagedResult(t *testing.T) {
+	var calls int
+	want := tools.PagedResult{Rows: []any{"a", "b"}, NextPageToken: "next"}
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{Name: "my-tool", Result: want, Calls: &calls},
+		Name:       "my-tool",
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	var got any
+	for range 2 {
+		if got, err = tool.Invoke(context.Background(), nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected cached result (-want +got):\n%s", diff)
+	}
+}
+
+func TestToolColumnOrder(t *testing.T) {
+	var calls int
+	want := []string{"name", "id"}
+	cfg := cache.ToolConfig{
+		ToolConfig: toolstest.Config{
+			Name:  "my-tool",
+			Calls: &calls,
+			InvokeFunc: func(ctx context.Context, _ tools.ParamValues) (any, error) {
+				tools.SetColumns(ctx, want)
+				return []any{map[string]any{"name": "a", "id": 1}}, nil
+			},
+		},
+		Name: "my-tool",
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	for i := range 2 {
+		ctx, columns := tools.WithColumnOrder(context.Background())
+		if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		// cached results keep the order of the columns of the statement
+		if diff := cmp.Diff(want, columns.Columns()); diff != "" {
+			t.Errorf("unexpected columns of invocation %d (-want +got):\n%s", i, diff)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the tool to be invoked once, got %d", calls)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	var calls int
+	tcs := []struct {
+		desc  string
+		cache cache.Config
+		err   string
+	}{
+		{
+			desc:  "invalid ttl",
+			cache: cache.Config{TTL: "0s"},
+			err:   "invalid cache ttl",
+		},
+		{
+			desc:  "missing source",
+			cache: cache.Config{Source: "my-redis"},
+			err:   `no source named "my-redis"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := cache.ToolConfig{ToolConfig: toolstest.Config{Calls: &calls}, Cache: tc.cache}
+			_, err := cfg.Initialize(nil)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLRU(2)
+	for _, k := range []string{"a", "b"} {
+		if err := c.Set(ctx, k, []byte(k), time.Minute); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	// "a" becomes the most recently used entry, so "b" is evicted
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatalf("expected entry %q", "a")
+	}
+	if err := c.Set(ctx, "c", []byte("c"), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Errorf("expected entry %q to be evicted", "b")
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+
+	if err := c.Set(ctx, "d", []byte("d"), time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := c.Get(ctx, "d"); ok {
+		t.Errorf("expected entry %q to be expired", "d")
+	}
+}
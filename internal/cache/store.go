@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	valkeysrc "github.com/googleapis/genai-toolbox/internal/sources/valkey"
+	"github.com/redis/go-redis/v9"
+	"github.com/valkey-io/valkey-go"
+)
+
+// Store holds encoded results by key.
+type Store interface {
+	// Get returns the value stored for key, and false if there is none or it
+	// has expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value for key until ttl has passed.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// newSourceStore returns a Store backed by a redis or valkey source.
+func newSourceStore(s sources.Source) (Store, error) {
+	switch s := s.(type) {
+	case *redissrc.Source:
+		return &redisStore{client: s.RedisClient()}, nil
+	case *valkeysrc.Source:
+		return &valkeyStore{client: s.ValkeyClient()}, nil
+	default:
+		return nil, fmt.Errorf("invalid source for cache: source kind must be one of %q", []string{redissrc.SourceKind, valkeysrc.SourceKind})
+	}
+}
+
+var _ Store = &LRU{}
+
+// LRU is an in-memory Store that evicts the least recently used entry once
+// it holds maxEntries entries.
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type lruItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRU returns an empty LRU holding up to maxEntries entries.
+func NewLRU(maxEntries int) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := e.Value.(*lruItem)
+	if !time.Now().Before(item.expires) {
+		c.ll.Remove(e)
+		delete(c.items, key)
+		return nil, false, nil
+	}
+	c.ll.MoveToFront(e)
+	return item.value, true, nil
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := time.Now().Add(ttl)
+	if e, ok := c.items[key]; ok {
+		item := e.Value.(*lruItem)
+		item.value, item.expires = value, expires
+		c.ll.MoveToFront(e)
+		return nil
+	}
+	c.items[key] = c.ll.PushFront(&lruItem{key: key, value: value, expires: expires})
+	for c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+var _ Store = &redisStore{}
+
+// redisStore stores entries in Redis, which expires them.
+type redisStore struct {
+	client redissrc.RedisClient
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := s.client.Do(ctx, "GET", key).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(b), true, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Do(ctx, "SET", key, value, "PX", ttl.Milliseconds()).Err()
+}
+
+var _ Store = &valkeyStore{}
+
+// valkeyStore stores entries in Valkey, which expires them.
+type valkeyStore struct {
+	client valkey.Client
+}
+
+func (s *valkeyStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := s.client.Do(ctx, s.client.B().Get().Key(key).Build()).AsBytes()
+	if valkey.IsValkeyNil(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+func (s *valkeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	cmd := s.client.B().Set().Key(key).Value(valkey.BinaryString(value)).Px(ttl).Build()
+	return s.client.Do(ctx, cmd).Error()
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configprovider loads tools files from remote locations, such as
+// HTTP(S) URLs and Cloud Storage objects, and polls them for changes.
+package configprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Provider fetches a tools file from a location.
+type Provider interface {
+	// Fetch returns the content of the tools file. Providers may send
+	// conditional requests, and return the previous content if it did not
+	// change.
+	Fetch(ctx context.Context) ([]byte, error)
+	// Location returns the location of the tools file, for messages.
+	Location() string
+}
+
+// Factory returns the Provider of a location with a registered scheme.
+type Factory func(u *url.URL) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register registers a Factory for a URL scheme. It returns false if a
+// factory is already registered for the scheme.
+func Register(scheme string, f Factory) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[scheme]; exists {
+		return false
+	}
+	registry[scheme] = f
+	return true
+}
+
+func lookup(location string) (*url.URL, Factory, bool) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" {
+		return nil, nil, false
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[u.Scheme]
+	return u, f, ok
+}
+
+// IsRemote reports whether location is a URL with a registered scheme, as
+// opposed to a local path.
+func IsRemote(location string) bool {
+	_, _, ok := lookup(location)
+	return ok
+}
+
+// New returns the Provider of a location, which is either a URL with a
+// registered scheme or a local path.
+func New(location string) (Provider, error) {
+	u, f, ok := lookup(location)
+	if !ok {
+		return FileProvider{Path: location}, nil
+	}
+	p, err := f(u)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tools file location %q: %w", location, err)
+	}
+	return p, nil
+}
+
+// Document is the content of a tools file fetched by a Provider.
+type Document struct {
+	Location string
+	Data     []byte
+}
+
+// Checksum returns a checksum of the content and locations of documents.
+func Checksum(docs []Document) string {
+	h := sha256.New()
+	for _, d := range docs {
+		fmt.Fprintf(h, "%s\x00%d\x00", d.Location, len(d.Data))
+		h.Write(d.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Watcher fetches tools files from providers, and polls them for changes.
+type Watcher struct {
+	providers []Provider
+	interval  time.Duration
+
+	mu       sync.Mutex
+	checksum string
+}
+
+// NewWatcher returns a Watcher of the tools files of providers, which polls
+// them every interval.
+func NewWatcher(providers []Provider, interval time.Duration) *Watcher {
+	return &Watcher{providers: providers, interval: interval}
+}
+
+// Load fetches the tools files of the providers.
+func (w *Watcher) Load(ctx context.Context) ([]Document, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	docs := make([]Document, 0, len(w.providers))
+	for _, p := range w.providers {
+		data, err := p.Fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch tools file at %q: %w", p.Location(), err)
+		}
+		docs = append(docs, Document{Location: p.Location(), Data: data})
+	}
+	return docs, nil
+}
+
+// Applied records the checksum of the tools files applied to the server, so
+// that Run only reports changes to them.
+func (w *Watcher) Applied(docs []Document) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checksum = Checksum(docs)
+}
+
+// changed reports whether docs differ from the tools files last applied.
+func (w *Watcher) changed(docs []Document) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return Checksum(docs) != w.checksum
+}
+
+// Run polls the providers until ctx is done. onChange is called with the
+// tools files whenever they differ from the tools files last applied, and
+// onError with the errors fetching them. The tools files are applied once
+// onChange returns nil, and are otherwise reported again by the next poll.
+func (w *Watcher) Run(ctx context.Context, onChange func(context.Context, []Document) error, onError func(error)) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+	if w.interval <= 0 {
+		logger.WarnContext(ctx, "tools file polling is disabled, since the poll interval is not positive")
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.DebugContext(ctx, "tools file poller context cancelled")
+			return
+		case <-ticker.C:
+		}
+		docs, err := w.Load(ctx)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		if !w.changed(docs) {
+			continue
+		}
+		logger.DebugContext(ctx, fmt.Sprintf("Change detected in tools files (checksum %s).", Checksum(docs)))
+		if err := onChange(ctx, docs); err == nil {
+			w.Applied(docs)
+		}
+	}
+}
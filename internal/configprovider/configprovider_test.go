@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/configprovider"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestNew(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		location string
+		remote   bool
+		want     string
+		err      string
+	}{
+		{desc: "local path", location: "tools.yaml", want: "tools.yaml"},
+		{desc: "absolute path", location: "/etc/toolbox/tools.yaml", want: "/etc/toolbox/tools.yaml"},
+		{desc: "https", location: "https://example.com/tools.yaml", remote: true, want: "https://example.com/tools.yaml"},
+		{desc: "gcs", location: "gs://my-bucket/configs/tools.yaml", remote: true, want: "gs://my-bucket/configs/tools.yaml"},
+		{desc: "gcs without object", location: "gs://my-bucket", remote: true, err: "gs://<bucket>/<object>"},
+		{desc: "http without host", location: "http:///tools.yaml", remote: true, err: "missing host"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := configprovider.IsRemote(tc.location); got != tc.remote {
+				t.Errorf("IsRemote(%q) = %t, want %t", tc.location, got, tc.remote)
+			}
+			p, err := configprovider.New(tc.location)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := p.Location(); got != tc.want {
+				t.Errorf("unexpected location: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	var mu sync.Mutex
+	content, etag := "tools: {}\n", `"v1"`
+	var conditional int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, content)
+	}))
+	defer ts.Close()
+
+	p := &configprovider.HTTPProvider{URL: ts.URL}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		data, err := p.Fetch(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(data) != "tools: {}\n" {
+			t.Fatalf("unexpected content: %q", data)
+		}
+	}
+	if conditional != 1 {
+		t.Errorf("expected the second request to be answered with 304, got %d", conditional)
+	}
+
+	mu.Lock()
+	content, etag = "tools: []\n", `"v2"`
+	mu.Unlock()
+	data, err := p.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(data) != "tools: []\n" {
+		t.Errorf("unexpected content after change: %q", data)
+	}
+}
+
+func TestHTTPProviderError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "access denied", http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	p := &configprovider.HTTPProvider{URL: ts.URL}
+	_, err := p.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden: access denied") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGCSProvider(t *testing.T) {
+	var gotPaths, gotGenerations []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.EscapedPath())
+		gen := r.URL.Query().Get("ifGenerationNotMatch")
+		gotGenerations = append(gotGenerations, gen)
+		if gen == "42" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("X-Goog-Generation", "42")
+		fmt.Fprint(w, "tools: {}\n")
+	}))
+	defer ts.Close()
+
+	p := &configprovider.GCSProvider{Bucket: "my-bucket", Object: "configs/tools.yaml", Endpoint: ts.URL, Client: ts.Client()}
+	for i := 0; i < 2; i++ {
+		data, err := p.Fetch(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(data) != "tools: {}\n" {
+			t.Fatalf("unexpected content: %q", data)
+		}
+	}
+	wantPath := "/storage/v1/b/my-bucket/o/configs%2Ftools.yaml"
+	for _, got := range gotPaths {
+		if got != wantPath {
+			t.Errorf("unexpected path: got %q, want %q", got, wantPath)
+		}
+	}
+	if len(gotGenerations) != 2 || gotGenerations[0] != "" || gotGenerations[1] != "42" {
+		t.Errorf("unexpected generation preconditions: %q", gotGenerations)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, []byte("tools: {}\n"), 0o600); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+	w := configprovider.NewWatcher([]configprovider.Provider{configprovider.FileProvider{Path: path}}, 10*time.Millisecond)
+	docs, err := w.Load(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(docs) != 1 || docs[0].Location != path {
+		t.Fatalf("unexpected documents: %+v", docs)
+	}
+
+	w.Applied(docs)
+
+	changes := make(chan []configprovider.Document)
+	go w.Run(ctx, func(_ context.Context, docs []configprovider.Document) error {
+		changes <- docs
+		return nil
+	}, func(err error) {
+		t.Errorf("unexpected error: %s", err)
+	})
+
+	// polls of the unchanged file do not report a change
+	select {
+	case docs := <-changes:
+		t.Fatalf("unexpected change: %+v", docs)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(path, []byte("tools: []\n"), 0o600); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+	select {
+	case docs := <-changes:
+		if string(docs[0].Data) != "tools: []\n" {
+			t.Errorf("unexpected content: %q", docs[0].Data)
+		}
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for change")
+	}
+}
+
+func TestWatcherFailedChange(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, []byte("tools: {}\n"), 0o600); err != nil {
+		t.Fatalf("unable to write tools file: %s", err)
+	}
+	w := configprovider.NewWatcher([]configprovider.Provider{configprovider.FileProvider{Path: path}}, 10*time.Millisecond)
+
+	// tools files failing to apply are reported again until applied
+	var calls int
+	done := make(chan struct{})
+	go w.Run(ctx, func(context.Context, []configprovider.Document) error {
+		calls++
+		if calls == 2 {
+			close(done)
+			return nil
+		}
+		if calls > 2 {
+			t.Errorf("unexpected change after the tools files were applied")
+		}
+		return errors.New("invalid tools file")
+	}, func(err error) {
+		t.Errorf("unexpected error: %s", err)
+	})
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatalf("timeout waiting for the change to be reported again")
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+}
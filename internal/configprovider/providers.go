@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	HTTPScheme  = "http"
+	HTTPSScheme = "https"
+	GCSScheme   = "gs"
+
+	defaultGCSEndpoint = "https://storage.googleapis.com"
+	storageReadScope   = "https://www.googleapis.com/auth/devstorage.read_only"
+
+	// fetchTimeout bounds the requests fetching a tools file, so that an
+	// unresponsive server does not block polling.
+	fetchTimeout = 30 * time.Second
+)
+
+// defaultClient is the client of providers without one.
+var defaultClient = &http.Client{Timeout: fetchTimeout}
+
+func init() {
+	for scheme, f := range map[string]Factory{
+		HTTPScheme:  newHTTPProvider,
+		HTTPSScheme: newHTTPProvider,
+		GCSScheme:   newGCSProvider,
+	} {
+		if !Register(scheme, f) {
+			panic(fmt.Sprintf("tools file scheme %q already registered", scheme))
+		}
+	}
+}
+
+var _ Provider = FileProvider{}
+
+// FileProvider reads a local tools file.
+type FileProvider struct {
+	Path string
+}
+
+func (p FileProvider) Fetch(_ context.Context) ([]byte, error) {
+	return os.ReadFile(p.Path)
+}
+
+func (p FileProvider) Location() string {
+	return p.Path
+}
+
+var _ Provider = &HTTPProvider{}
+
+// HTTPProvider fetches a tools file from an HTTP(S) URL. The ETag and
+// Last-Modified headers of the response are sent back in conditional requests,
+// so that an unchanged tools file is not downloaded again.
+type HTTPProvider struct {
+	URL string
+	// Client defaults to a client whose requests time out after 30s.
+	Client *http.Client
+
+	mu           sync.Mutex
+	etag         string
+	lastModified string
+	data         []byte
+}
+
+func newHTTPProvider(u *url.URL) (Provider, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+	return &HTTPProvider{URL: u.String()}, nil
+}
+
+func (p *HTTPProvider) Fetch(ctx context.Context) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	header := http.Header{}
+	if p.data != nil {
+		if p.etag != "" {
+			header.Set("If-None-Match", p.etag)
+		}
+		if p.lastModified != "" {
+			header.Set("If-Modified-Since", p.lastModified)
+		}
+	}
+	data, resp, err := conditionalGet(ctx, p.Client, p.URL, header)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return p.data, nil
+	}
+	p.data, p.etag, p.lastModified = data, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	return p.data, nil
+}
+
+func (p *HTTPProvider) Location() string {
+	return p.URL
+}
+
+var _ Provider = &GCSProvider{}
+
+// GCSProvider fetches a tools file from a Cloud Storage object, e.g.
+// gs://my-bucket/tools.yaml, using Application Default Credentials. The
+// object is only downloaded again if its generation changed.
+type GCSProvider struct {
+	Bucket string
+	Object string
+	// Endpoint defaults to the Cloud Storage API.
+	Endpoint string
+	// Client defaults to a client using Application Default Credentials, whose
+	// requests time out after 30s.
+	Client *http.Client
+
+	mu         sync.Mutex
+	generation string
+	data       []byte
+}
+
+func newGCSProvider(u *url.URL) (Provider, error) {
+	object := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || object == "" {
+		return nil, fmt.Errorf("location must be of the form gs://<bucket>/<object>")
+	}
+	return &GCSProvider{Bucket: u.Host, Object: object}, nil
+}
+
+func (p *GCSProvider) Fetch(ctx context.Context) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Client == nil {
+		client, err := google.DefaultClient(ctx, storageReadScope)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find default credentials: %w", err)
+		}
+		client.Timeout = fetchTimeout
+		p.Client = client
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGCSEndpoint
+	}
+
+	query := url.Values{"alt": {"media"}}
+	if p.data != nil && p.generation != "" {
+		query.Set("ifGenerationNotMatch", p.generation)
+	}
+	u := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?%s", strings.TrimSuffix(endpoint, "/"), url.PathEscape(p.Bucket), url.PathEscape(p.Object), query.Encode())
+	data, resp, err := conditionalGet(ctx, p.Client, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return p.data, nil
+	}
+	p.data, p.generation = data, resp.Header.Get("X-Goog-Generation")
+	return p.data, nil
+}
+
+func (p *GCSProvider) Location() string {
+	return fmt.Sprintf("%s://%s/%s", GCSScheme, p.Bucket, p.Object)
+}
+
+// conditionalGet sends a GET request, and returns the body of the response,
+// or nil if the resource was not modified.
+func conditionalGet(ctx context.Context, client *http.Client, url string, header http.Header) ([]byte, *http.Response, error) {
+	if client == nil {
+		client = defaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if body == nil {
+		body = []byte{}
+	}
+	return body, resp, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package confirmation asks a human to confirm the invocation of tools
+// configured with `requireConfirmation` before they are invoked.
+package confirmation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const defaultMessage = `Invoke tool "{{ .tool }}" with parameters {{ json .params }}?`
+
+// ErrDeclined is returned when the invocation of a tool is declined.
+var ErrDeclined = errors.New("invocation of the tool was declined")
+
+// Config is the `requireConfirmation` block of a tool.
+type Config struct {
+	// Message is a Go template of the summary shown to the human. It is
+	// executed with the name of the tool as `.tool`, the parameters as
+	// `.params` and the result of the preview tool as `.preview`.
+	Message string `yaml:"message"`
+	// Preview optionally names a tool supporting dry runs, which is invoked
+	// with the same parameters and `dryRun` before asking for confirmation,
+	// such as to count the rows a statement would affect. It may name the
+	// tool itself.
+	Preview string `yaml:"preview"`
+}
+
+// Request describes an invocation awaiting confirmation.
+type Request struct {
+	Tool    string
+	Params  map[string]any
+	Message string
+	// AccessToken is the access token of the invocation, which confirmation
+	// tokens are bound to along with the verified claims.
+	AccessToken tools.AccessToken
+}
+
+// Confirmer asks for the confirmation of an invocation. It returns nil if the
+// invocation is confirmed, ErrDeclined if it is declined, or another error if
+// it cannot be confirmed yet.
+type Confirmer interface {
+	Confirm(ctx context.Context, req Request) error
+}
+
+// ConfirmerFunc is a function implementing Confirmer.
+type ConfirmerFunc func(ctx context.Context, req Request) error
+
+func (f ConfirmerFunc) Confirm(ctx context.Context, req Request) error { return f(ctx, req) }
+
+// AutoConfirm confirms every invocation, for invocations already confirmed
+// by the caller.
+var AutoConfirm Confirmer = ConfirmerFunc(func(context.Context, Request) error { return nil })
+
+type confirmerKey struct{}
+
+// WithConfirmer returns a context asking c to confirm invocations.
+func WithConfirmer(ctx context.Context, c Confirmer) context.Context {
+	return context.WithValue(ctx, confirmerKey{}, c)
+}
+
+// ConfirmerFromContext returns the confirmer of ctx, or nil if the caller
+// cannot confirm invocations.
+func ConfirmerFromContext(ctx context.Context) Confirmer {
+	c, _ := ctx.Value(confirmerKey{}).(Confirmer)
+	return c
+}
+
+// ToolConfig wraps the config of a tool requiring confirmation.
+type ToolConfig struct {
+	tools.ToolConfig
+	Name         string
+	Confirmation Config
+}
+
+var _ tools.ComposedToolConfig = ToolConfig{}
+
+// Unwrap returns the config of the wrapped tool.
+func (cfg ToolConfig) Unwrap() tools.ToolConfig { return cfg.ToolConfig }
+
+// ToolNames returns the preview tool and the tools invoked by the wrapped
+// tool.
+func (cfg ToolConfig) ToolNames() []string {
+	// the names of the wrapped tool are clipped, so that appending copies them
+	names := slices.Clip(tools.WrappedToolNames(cfg.ToolConfig))
+	if cfg.Confirmation.Preview != "" && cfg.Confirmation.Preview != cfg.Name {
+		names = append(names, cfg.Confirmation.Preview)
+	}
+	return names
+}
+
+func (cfg ToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return cfg.InitializeWithTools(srcs, nil)
+}
+
+func (cfg ToolConfig) InitializeWithTools(srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	tmpl, err := parseMessage(cfg.Confirmation.Message)
+	if err != nil {
+		return nil, err
+	}
+	t, err := tools.InitializeWrapped(cfg.ToolConfig, srcs, toolsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	var preview tools.Tool
+	switch cfg.Confirmation.Preview {
+	case "":
+	case cfg.Name:
+		preview = t
+	default:
+		var ok bool
+		preview, ok = toolsMap[cfg.Confirmation.Preview]
+		if !ok {
+			return nil, fmt.Errorf("no preview tool named %q configured", cfg.Confirmation.Preview)
+		}
+	}
+	// the preview runs before the invocation is confirmed, so it must not
+	// execute anything
+	if preview != nil && !supportsDryRun(preview) {
+		return nil, fmt.Errorf("preview tool %q does not support dry runs: it must be configured with dryRun", cfg.Confirmation.Preview)
+	}
+	return Tool{Tool: t, toolName: cfg.Name, message: tmpl, preview: preview}, nil
+}
+
+// supportsDryRun reports whether t takes the dryRun parameter.
+func supportsDryRun(t tools.Tool) bool {
+	return slices.ContainsFunc(t.Manifest().Parameters, func(p tools.ParameterManifest) bool {
+		return p.Name == tools.DryRunParameterName && p.Type == "boolean"
+	})
+}
+
+// parseMessage parses the message template, defaulting to a summary of the
+// parameters.
+func parseMessage(message string) (*template.Template, error) {
+	if message == "" {
+		message = defaultMessage
+	}
+	tmpl, err := template.New("message").Option("missingkey=zero").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid confirmation message: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Tool invokes the wrapped tool once the invocation is confirmed.
+type Tool struct {
+	tools.Tool
+	toolName string
+	message  *template.Template
+	preview  tools.Tool
+}
+
+var _ tools.Tool = Tool{}
+
+// Unwrap returns the wrapped tool.
+func (t Tool) Unwrap() tools.Tool { return t.Tool }
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	c := ConfirmerFromContext(ctx)
+	if c == nil {
+		return nil, fmt.Errorf("tool %q requires confirmation, which the client does not support", t.toolName)
+	}
+	msg, err := t.render(ctx, params, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Confirm(ctx, Request{Tool: t.toolName, Params: params.AsMap(), Message: msg, AccessToken: accessToken}); err != nil {
+		return nil, err
+	}
+	return t.Tool.Invoke(ctx, params, accessToken)
+}
+
+// render returns the message summarizing the invocation, invoking the
+// preview tool if there is one.
+func (t Tool) render(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (string, error) {
+	data := map[string]any{"tool": t.toolName, "params": params.AsMap()}
+	if t.preview != nil {
+		previewData := maps.Clone(params.AsMap())
+		previewData[tools.DryRunParameterName] = true
+		previewParams, err := t.preview.ParseParams(previewData, util.ClaimsFromContext(ctx))
+		if err != nil {
+			return "", fmt.Errorf("unable to parse params of preview tool: %w", err)
+		}
+		res, err := t.preview.Invoke(ctx, previewParams, accessToken)
+		if err != nil {
+			return "", fmt.Errorf("unable to invoke preview tool: %w", err)
+		}
+		// the result is passed as its JSON encoding, so that the template
+		// reads the fields of a dry run, such as `.preview.rowsAffected`,
+		// by the names clients see
+		b, err := json.Marshal(res)
+		if err != nil {
+			return "", fmt.Errorf("unable to encode result of preview tool: %w", err)
+		}
+		var preview any
+		if err := json.Unmarshal(b, &preview); err != nil {
+			return "", fmt.Errorf("unable to decode result of preview tool: %w", err)
+		}
+		data["preview"] = preview
+	}
+	var sb strings.Builder
+	if err := t.message.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("unable to render confirmation message: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// Authorized also requires the client to be authorized to invoke the preview
+// tool.
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	if t.preview != nil && !t.preview.Authorized(verifiedAuthServices) {
+		return false
+	}
+	return t.Tool.Authorized(verifiedAuthServices)
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confirmation_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+)
+
+// fakeConfig returns the config of a fake tool with an `id` parameter, which
+// returns result and counts its invocations in calls. If dryRuns is not nil,
+// the tool supports dry runs and counts them in dryRuns.
+func fakeConfig(name string, result any, calls, dryRuns *int) toolstest.Config {
+	params := tools.Parameters{tools.NewIntParameter("id", "id")}
+	if dryRuns != nil {
+		params, _ = tools.WithDryRunParameter(params)
+	}
+	return toolstest.Config{
+		Name:       name,
+		Parameters: params,
+		Calls:      calls,
+		InvokeFunc: func(_ context.Context, params tools.ParamValues) (any, error) {
+			if dryRuns != nil && tools.IsDryRun(params.AsMap()) {
+				*dryRuns++
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestParseFromYamlRequireConfirmation(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pgConfig := func(name string) postgressql.Config {
+		return postgressql.Config{
+			Name:         name,
+			Kind:         "postgres-sql",
+			Source:       "my-pg-instance",
+			Description:  "some description",
+			Statement:    "DELETE FROM t;",
+			AuthRequired: []string{},
+		}
+	}
+	in := `
+	tools:
+		default_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: DELETE FROM t;
+			requireConfirmation: true
+		disabled_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: DELETE FROM t;
+			requireConfirmation: false
+		preview_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: DELETE FROM t;
+			requireConfirmation:
+				message: Delete {{ .preview }} rows?
+				preview: count_tool
+	`
+	want := server.ToolConfigs{
+		"default_tool":  confirmation.ToolConfig{ToolConfig: pgConfig("default_tool"), Name: "default_tool"},
+		"disabled_tool": pgConfig("disabled_tool"),
+		"preview_tool": confirmation.ToolConfig{
+			ToolConfig:   pgConfig("preview_tool"),
+			Name:         "preview_tool",
+			Confirmation: confirmation.Config{Message: "Delete {{ .preview }} rows?", Preview: "count_tool"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFailParseFromYamlRequireConfirmation(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: DELETE FROM t;
+			requireConfirmation:
+				foo: bar
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "foo"`) {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestTool(t *testing.T) {
+	var calls, previewCalls, dryRuns int
+	cfg := confirmation.ToolConfig{
+		ToolConfig: fakeConfig("delete", "deleted", &calls, nil),
+		Name:       "delete",
+		Confirmation: confirmation.Config{
+			Message: "Delete {{ .params.id }} from {{ .tool }} ({{ json .preview }})?",
+			Preview: "count",
+		},
+	}
+	if diff := cmp.Diff([]string{"count"}, cfg.ToolNames()); diff != "" {
+		t.Fatalf("unexpected tool names (-want +got):\n%s", diff)
+	}
+	preview, err := fakeConfig("count", []any{map[string]any{"count": 3}}, &previewCalls, &dryRuns).Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := cfg.InitializeWithTools(nil, map[string]tools.Tool{"count": preview})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	params := tools.ParamValues{{Name: "id", Value: 7}}
+
+	// without a confirmer, the tool is not invoked
+	_, err = tool.Invoke(context.Background(), params, "")
+	if err == nil || !strings.Contains(err.Error(), "client does not support") {
+		t.Fatalf("expected unsupported confirmation error, got %v", err)
+	}
+
+	var got confirmation.Request
+	declined := confirmation.WithConfirmer(context.Background(), confirmation.ConfirmerFunc(func(_ context.Context, req confirmation.Request) error {
+		got = req
+		return confirmation.ErrDeclined
+	}))
+	if _, err := tool.Invoke(declined, params, ""); !errors.Is(err, confirmation.ErrDeclined) {
+		t.Fatalf("expected declined error, got %v", err)
+	}
+	want := confirmation.Request{Tool: "delete", Params: map[string]any{"id": 7}, Message: `Delete 7 from delete ([{"count":3}])?`}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected confirmation request (-want +got):\n%s", diff)
+	}
+	if calls != 0 {
+		t.Errorf("expected declined tool not to be invoked, got %d calls", calls)
+	}
+
+	confirmed := confirmation.WithConfirmer(context.Background(), confirmation.AutoConfirm)
+	res, err := tool.Invoke(confirmed, params, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if res != "deleted" || calls != 1 {
+		t.Errorf("expected confirmed tool to be invoked once, got %v and %d calls", res, calls)
+	}
+	if previewCalls != 2 || dryRuns != 2 {
+		t.Errorf("expected a dry run of the preview before each confirmation, got %d calls and %d dry runs", previewCalls, dryRuns)
+	}
+}
+
+func TestToolPreviewingItself(t *testing.T) {
+	var calls, dryRuns int
+	rows := int64(3)
+	cfg := confirmation.ToolConfig{
+		ToolConfig: fakeConfig("delete", tools.DryRunResult{Statement: "DELETE", RowsAffected: &rows}, &calls, &dryRuns),
+		Name:       "delete",
+		Confirmation: confirmation.Config{
+			Message: "Delete {{ .preview.rowsAffected }} rows?",
+			Preview: "delete",
+		},
+	}
+	if names := cfg.ToolNames(); len(names) != 0 {
+		t.Fatalf("expected no tool names, got %v", names)
+	}
+	tool, err := cfg.InitializeWithTools(nil, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	var got string
+	ctx := confirmation.WithConfirmer(context.Background(), confirmation.ConfirmerFunc(func(_ context.Context, req confirmation.Request) error {
+		got = req.Message
+		return confirmation.ErrDeclined
+	}))
+	if _, err := tool.Invoke(ctx, tools.ParamValues{{Name: "id", Value: 7}}, ""); !errors.Is(err, confirmation.ErrDeclined) {
+		t.Fatalf("expected declined error, got %v", err)
+	}
+	if got != "Delete 3 rows?" {
+		t.Errorf("unexpected message: %q", got)
+	}
+	if calls != 1 || dryRuns != 1 {
+		t.Errorf("expected only a dry run, got %d calls and %d dry runs", calls, dryRuns)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	var calls int
+	tcs := []struct {
+		desc         string
+		confirmation confirmation.Config
+		err          string
+	}{
+		{
+			desc:         "invalid message",
+			confirmation: confirmation.Config{Message: "{{ .params"},
+			err:          "invalid confirmation message",
+		},
+		{
+			desc:         "missing preview",
+			confirmation: confirmation.Config{Preview: "count"},
+			err:          `no preview tool named "count"`,
+		},
+		{
+			desc:         "preview without dry runs",
+			confirmation: confirmation.Config{Preview: "list"},
+			err:          `preview tool "list" does not support dry runs`,
+		},
+	}
+	list, err := fakeConfig("list", nil, &calls, nil).Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := confirmation.ToolConfig{ToolConfig: fakeConfig("", nil, &calls, nil), Confirmation: tc.confirmation}
+			_, err := cfg.InitializeWithTools(nil, map[string]tools.Tool{"list": list})
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confirmation
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// TokenHeader is the header echoing the confirmation token of an invocation
+// over the REST API.
+const TokenHeader = "Toolbox-Confirmation-Token"
+
+const tokenTTL = 5 * time.Minute
+
+// RequiredError is returned when an invocation must be repeated with Token to
+// confirm it.
+type RequiredError struct {
+	Message string
+	Token   string
+}
+
+// Error points clients that cannot confirm the invocation themselves, such
+// as MCP clients without elicitation, to the REST API.
+func (e *RequiredError) Error() string {
+	return fmt.Sprintf("confirmation required: %s (to confirm, repeat the invocation over the REST API with the %s header set to %q)", e.Message, TokenHeader, e.Token)
+}
+
+// Signer signs and verifies confirmation tokens. A token confirms a single
+// invocation of a tool, with the same parameters, set of verified claims and
+// access token, before it expires.
+type Signer struct {
+	key []byte
+	now func() time.Time
+
+	// mu guards used, the nonces of the tokens that confirmed an invocation,
+	// with their expiry, so that tokens cannot be replayed.
+	mu   sync.Mutex
+	used map[string]int64
+}
+
+// NewSigner returns a Signer with a random key, so that tokens are only valid
+// for the server that issued them.
+func NewSigner() (*Signer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("unable to generate confirmation key: %w", err)
+	}
+	return &Signer{key: key, now: time.Now, used: make(map[string]int64)}, nil
+}
+
+// Confirmer returns a Confirmer accepting invocations confirmed by token. If
+// token is missing or invalid, invocations fail with a RequiredError
+// carrying a new token.
+func (s *Signer) Confirmer(token string) Confirmer {
+	return ConfirmerFunc(func(ctx context.Context, req Request) error {
+		if token != "" && s.verify(ctx, token, req) {
+			return nil
+		}
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("unable to generate confirmation token: %w", err)
+		}
+		expiry := s.now().Add(tokenTTL).Unix()
+		return &RequiredError{Message: req.Message, Token: s.sign(ctx, req, expiry, base64.RawURLEncoding.EncodeToString(nonce))}
+	})
+}
+
+// sign returns a token for req expiring at expiry, in Unix seconds, and
+// identified by nonce.
+func (s *Signer) sign(ctx context.Context, req Request, expiry int64, nonce string) string {
+	exp := strconv.FormatInt(expiry, 10)
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(exp))
+	mac.Write([]byte{0})
+	mac.Write([]byte(nonce))
+	mac.Write([]byte{0})
+	mac.Write([]byte(req.Tool))
+	mac.Write([]byte{0})
+	// the access token is hashed, as by the cache keys of results, so that
+	// tokens are bound to the caller of tools using client authorization
+	sum := sha256.Sum256([]byte(req.AccessToken))
+	mac.Write(sum[:])
+	// maps are encoded with sorted keys, making the encoding canonical
+	b, _ := json.Marshal(map[string]any{"params": req.Params, "claims": util.ClaimsFromContext(ctx)})
+	mac.Write(b)
+	return exp + "." + nonce + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify reports whether token confirms req, has not expired and has not
+// confirmed another invocation. A token is used once verified.
+func (s *Signer) verify(ctx context.Context, token string, req Request) bool {
+	exp, rest, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	nonce, _, ok := strings.Cut(rest, ".")
+	if !ok {
+		return false
+	}
+	expiry, err := strconv.ParseInt(exp, 10, 64)
+	now := s.now().Unix()
+	if err != nil || now > expiry {
+		return false
+	}
+	if !hmac.Equal([]byte(token), []byte(s.sign(ctx, req, expiry, nonce))) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for n, e := range s.used {
+		if now > e {
+			delete(s.used, n)
+		}
+	}
+	if _, ok := s.used[nonce]; ok {
+		return false
+	}
+	s.used[nonce] = expiry
+	return true
+}
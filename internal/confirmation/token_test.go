@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confirmation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestSignerConfirmer(t *testing.T) {
+	s, err := NewSigner()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+	ctx := util.WithClaims(context.Background(), map[string]map[string]any{"my-auth": {"sub": "alice"}})
+	req := Request{Tool: "delete", Params: map[string]any{"id": 7}, Message: "Delete 7?"}
+
+	// without a token, a token is issued
+	err = s.Confirmer("").Confirm(ctx, req)
+	var required *RequiredError
+	if !errors.As(err, &required) {
+		t.Fatalf("expected RequiredError, got %v", err)
+	}
+	if required.Message != req.Message || required.Token == "" {
+		t.Fatalf("unexpected RequiredError: %+v", required)
+	}
+	token := required.Token
+
+	if err := s.Confirmer(token).Confirm(ctx, req); err != nil {
+		t.Errorf("expected token to confirm the invocation, got %v", err)
+	}
+	// a token confirms a single invocation
+	if err := s.Confirmer(token).Confirm(ctx, req); !errors.As(err, &required) {
+		t.Errorf("expected replayed token to be rejected, got %v", err)
+	}
+
+	// issue returns a new token for req
+	issue := func(t *testing.T) string {
+		s.now = func() time.Time { return now }
+		var required *RequiredError
+		if err := s.Confirmer("").Confirm(ctx, req); !errors.As(err, &required) {
+			t.Fatalf("expected RequiredError, got %v", err)
+		}
+		return required.Token
+	}
+
+	other, err := NewSigner()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	bob := util.WithClaims(context.Background(), map[string]map[string]any{"my-auth": {"sub": "bob"}})
+	tcs := []struct {
+		desc  string
+		ctx   context.Context
+		s     *Signer
+		token string
+		req   Request
+		now   time.Time
+	}{
+		{desc: "other params", ctx: ctx, s: s, req: Request{Tool: "delete", Params: map[string]any{"id": 8}}, now: now},
+		{desc: "other tool", ctx: ctx, s: s, req: Request{Tool: "drop", Params: req.Params}, now: now},
+		{desc: "other claims", ctx: bob, s: s, req: req, now: now},
+		{desc: "other access token", ctx: ctx, s: s, req: Request{Tool: req.Tool, Params: req.Params, AccessToken: "other-token"}, now: now},
+		{desc: "other server", ctx: ctx, s: other, req: req, now: now},
+		{desc: "expired", ctx: ctx, s: s, req: req, now: now.Add(tokenTTL + time.Second)},
+		{desc: "malformed", ctx: ctx, s: s, token: "not-a-token", req: req, now: now},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			token := tc.token
+			if token == "" {
+				token = issue(t)
+			}
+			tc.s.now = func() time.Time { return tc.now }
+			err := tc.s.Confirmer(token).Confirm(tc.ctx, tc.req)
+			if !errors.As(err, &required) {
+				t.Fatalf("expected RequiredError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embeddingmodels embeds text as vectors, for tools searching rows
+// by similarity. Kinds of embedding models register themselves like kinds of
+// sources and tools, and are configured by the `embeddingModel` block of a
+// tool.
+package embeddingmodels
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// EmbeddingModelConfigFactory decodes the config of a kind of embedding
+// model.
+type EmbeddingModelConfigFactory func(ctx context.Context, decoder *yaml.Decoder) (EmbeddingModelConfig, error)
+
+var modelRegistry = make(map[string]EmbeddingModelConfigFactory)
+
+// Register registers a kind of embedding model with its factory. It returns
+// false if the kind is already registered.
+func Register(kind string, factory EmbeddingModelConfigFactory) bool {
+	if _, exists := modelRegistry[kind]; exists {
+		return false
+	}
+	modelRegistry[kind] = factory
+	return true
+}
+
+// Kinds returns the registered kinds of embedding models in sorted order.
+func Kinds() []string {
+	return slices.Sorted(maps.Keys(modelRegistry))
+}
+
+// DecodeConfig decodes the config of an embedding model using the factory
+// registered for kind.
+func DecodeConfig(ctx context.Context, kind string, decoder *yaml.Decoder) (EmbeddingModelConfig, error) {
+	factory, found := modelRegistry[kind]
+	if !found {
+		return nil, fmt.Errorf("unknown embedding model kind: %q", kind)
+	}
+	cfg, err := factory(ctx, decoder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse embedding model as kind %q: %w", kind, err)
+	}
+	return cfg, nil
+}
+
+// EmbeddingModelConfig is the config of an embedding model.
+type EmbeddingModelConfig interface {
+	EmbeddingModelConfigKind() string
+	Initialize() (EmbeddingModel, error)
+}
+
+// EmbeddingModel embeds text as vectors.
+type EmbeddingModel interface {
+	EmbeddingModelKind() string
+	// Embed returns the embedding of a query, whose dimension is the
+	// dimension of the embeddings searched.
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// Config is the `embeddingModel` block of a tool, decoded by the kind it
+// names.
+type Config struct {
+	EmbeddingModelConfig
+}
+
+var _ yaml.InterfaceUnmarshalerContext = &Config{}
+
+func (c *Config) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var raw map[string]any
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	kind, ok := raw["kind"].(string)
+	if !ok {
+		return fmt.Errorf("missing or invalid 'kind' field for embedding model")
+	}
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return fmt.Errorf("error creating YAML decoder for embedding model: %w", err)
+	}
+	cfg, err := DecodeConfig(ctx, kind, dec)
+	if err != nil {
+		return err
+	}
+	c.EmbeddingModelConfig = cfg
+	return nil
+}
+
+// Initialize initializes the configured embedding model.
+func (c Config) Initialize() (EmbeddingModel, error) {
+	if c.EmbeddingModelConfig == nil {
+		return nil, fmt.Errorf("missing embedding model")
+	}
+	return c.EmbeddingModelConfig.Initialize()
+}
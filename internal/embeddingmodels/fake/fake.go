@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides an embedding model computed locally from the words of
+// the text, for testing vector search tools without a model provider.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+)
+
+const EmbeddingModelKind string = "fake"
+
+func init() {
+	if !embeddingmodels.Register(EmbeddingModelKind, newConfig) {
+		panic(fmt.Sprintf("embedding model kind %q already registered", EmbeddingModelKind))
+	}
+}
+
+func newConfig(ctx context.Context, decoder *yaml.Decoder) (embeddingmodels.EmbeddingModelConfig, error) {
+	actual := Config{}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Kind      string `yaml:"kind" validate:"required"`
+	Dimension int    `yaml:"dimension" validate:"required"`
+}
+
+var _ embeddingmodels.EmbeddingModelConfig = Config{}
+
+func (cfg Config) EmbeddingModelConfigKind() string { return EmbeddingModelKind }
+
+func (cfg Config) Initialize() (embeddingmodels.EmbeddingModel, error) {
+	if cfg.Dimension <= 0 {
+		return nil, fmt.Errorf("invalid dimension %d: must be positive", cfg.Dimension)
+	}
+	return Model{dimension: cfg.Dimension}, nil
+}
+
+// Model embeds text by hashing each of its words into one of the dimensions
+// of the embedding, so that texts sharing words are similar.
+type Model struct {
+	dimension int
+}
+
+var _ embeddingmodels.EmbeddingModel = Model{}
+
+func (m Model) EmbeddingModelKind() string { return EmbeddingModelKind }
+
+func (m Model) Embed(_ context.Context, text string) ([]float32, error) {
+	v := make([]float64, m.dimension)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(w))
+		sum := h.Sum32()
+		// the top bit signs the word, so that collisions tend to cancel out
+		sign := 1.0
+		if sum&(1<<31) != 0 {
+			sign = -1
+		}
+		v[int(sum&^(1<<31))%m.dimension] += sign
+	}
+
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float32, m.dimension)
+	for i, x := range v {
+		if norm > 0 {
+			out[i] = float32(x / norm)
+		}
+	}
+	return out, nil
+}
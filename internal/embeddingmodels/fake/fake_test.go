@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels/fake"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlFake(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+            embeddingModel:
+                kind: fake
+                dimension: 16
+            `
+	got := struct {
+		EmbeddingModel embeddingmodels.Config `yaml:"embeddingModel"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	want := embeddingmodels.Config{EmbeddingModelConfig: fake.Config{Kind: "fake", Dimension: 16}}
+	if diff := cmp.Diff(want, got.EmbeddingModel); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	m, err := fake.Config{Kind: "fake", Dimension: 64}.Initialize()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	embed := func(text string) []float32 {
+		v, err := m.Embed(context.Background(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return v
+	}
+	dot := func(a, b []float32) float64 {
+		var s float64
+		for i := range a {
+			s += float64(a[i]) * float64(b[i])
+		}
+		return s
+	}
+
+	q := embed("How do I return a damaged product?")
+	if len(q) != 64 {
+		t.Fatalf("incorrect dimension: got %d, want 64", len(q))
+	}
+	if n := dot(q, q); math.Abs(n-1) > 1e-5 {
+		t.Errorf("embedding is not normalized: squared norm %f", n)
+	}
+	if diff := cmp.Diff(q, embed("how do i RETURN a damaged product")); diff != "" {
+		t.Errorf("embedding is not deterministic: diff %v", diff)
+	}
+	similar := dot(q, embed("return a damaged product"))
+	other := dot(q, embed("shipping times to Canada"))
+	if similar <= other {
+		t.Errorf("similar text scored %f, not above unrelated text %f", similar, other)
+	}
+}
+
+func TestInitializeInvalidDimension(t *testing.T) {
+	if _, err := (fake.Config{Kind: "fake", Dimension: -1}).Initialize(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gemini embeds text with the Gemini API.
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const EmbeddingModelKind string = "gemini"
+
+const (
+	defaultModel   = "gemini-embedding-001"
+	requestTimeout = 30 * time.Second
+	// queryTaskType optimizes embeddings for queries searching documents.
+	queryTaskType = "RETRIEVAL_QUERY"
+)
+
+// baseURL is the endpoint of the Gemini API, replaced in tests.
+var baseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+func init() {
+	if !embeddingmodels.Register(EmbeddingModelKind, newConfig) {
+		panic(fmt.Sprintf("embedding model kind %q already registered", EmbeddingModelKind))
+	}
+}
+
+func newConfig(ctx context.Context, decoder *yaml.Decoder) (embeddingmodels.EmbeddingModelConfig, error) {
+	actual := Config{}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Kind   string `yaml:"kind" validate:"required"`
+	APIKey string `yaml:"apiKey" validate:"required"`
+	// Model defaults to gemini-embedding-001.
+	Model string `yaml:"model"`
+	// Dimension is the dimension of the embeddings, which must match the
+	// embeddings searched. Defaults to the dimension of the model.
+	Dimension int `yaml:"dimension"`
+}
+
+var _ embeddingmodels.EmbeddingModelConfig = Config{}
+
+func (cfg Config) EmbeddingModelConfigKind() string { return EmbeddingModelKind }
+
+func (cfg Config) Initialize() (embeddingmodels.EmbeddingModel, error) {
+	if cfg.Dimension < 0 {
+		return nil, fmt.Errorf("invalid dimension %d: must be positive", cfg.Dimension)
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+	return Model{
+		url:       fmt.Sprintf("%s/models/%s:embedContent", baseURL, url.PathEscape(model)),
+		model:     "models/" + model,
+		apiKey:    cfg.APIKey,
+		dimension: cfg.Dimension,
+		client:    &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Model embeds text with the embedContent method of the Gemini API.
+type Model struct {
+	url       string
+	model     string
+	apiKey    string
+	dimension int
+	client    *http.Client
+}
+
+var _ embeddingmodels.EmbeddingModel = Model{}
+
+func (m Model) EmbeddingModelKind() string { return EmbeddingModelKind }
+
+type part struct {
+	Text string `json:"text"`
+}
+
+type content struct {
+	Parts []part `json:"parts"`
+}
+
+type embedRequest struct {
+	Model                string  `json:"model"`
+	Content              content `json:"content"`
+	TaskType             string  `json:"taskType"`
+	OutputDimensionality int     `json:"outputDimensionality,omitempty"`
+}
+
+type embedResponse struct {
+	Embedding struct {
+		Values []float32 `json:"values"`
+	} `json:"embedding"`
+}
+
+func (m Model) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(embedRequest{
+		Model:                m.model,
+		Content:              content{Parts: []part{{Text: text}}},
+		TaskType:             queryTaskType,
+		OutputDimensionality: m.dimension,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", m.apiKey)
+	if ua, err := util.UserAgentFromContext(ctx); err == nil {
+		req.Header.Set("User-Agent", ua)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to embed text: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read embedding: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to embed text: unexpected status %d: %s", resp.StatusCode, b)
+	}
+	var res embedResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("unable to parse embedding: %w", err)
+	}
+	if len(res.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("unable to embed text: empty embedding")
+	}
+	return res.Embedding.Values, nil
+}
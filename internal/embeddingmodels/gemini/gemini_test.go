@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEmbed(t *testing.T) {
+	var gotPath, gotKey string
+	var gotReq embedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotKey = r.URL.Path, r.Header.Get("x-goog-api-key")
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"embedding": {"values": [0.25, -0.5, 1]}}`))
+	}))
+	defer srv.Close()
+	defer func(u string) { baseURL = u }(baseURL)
+	baseURL = srv.URL
+
+	m, err := Config{Kind: EmbeddingModelKind, APIKey: "secret", Dimension: 3}.Initialize()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := m.Embed(context.Background(), "refund policy")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]float32{0.25, -0.5, 1}, got); diff != "" {
+		t.Errorf("incorrect embedding: diff %v", diff)
+	}
+	if gotPath != "/models/gemini-embedding-001:embedContent" || gotKey != "secret" {
+		t.Errorf("unexpected request to %q with key %q", gotPath, gotKey)
+	}
+	wantReq := embedRequest{
+		Model:                "models/gemini-embedding-001",
+		Content:              content{Parts: []part{{Text: "refund policy"}}},
+		TaskType:             "RETRIEVAL_QUERY",
+		OutputDimensionality: 3,
+	}
+	if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+		t.Errorf("incorrect request: diff %v", diff)
+	}
+}
+
+func TestEmbedError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "API key not valid", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	defer func(u string) { baseURL = u }(baseURL)
+	baseURL = srv.URL
+
+	m, err := Config{Kind: EmbeddingModelKind, APIKey: "bad"}.Initialize()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = m.Embed(context.Background(), "refund policy")
+	if err == nil || !strings.Contains(err.Error(), "unexpected status 400: API key not valid") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
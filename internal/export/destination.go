@@ -0,0 +1,257 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// destination creates exported files and deletes them once expired.
+type destination interface {
+	create(ctx context.Context, name, mimeType string) (file, error)
+	// cleanup deletes the exported files expired before t.
+	cleanup(ctx context.Context, t time.Time) error
+}
+
+// fileExpiry returns the expiry in the name of an exported file.
+func fileExpiry(name string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(name, filePrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	ts, _, _ := strings.Cut(rest, "-")
+	expiry, err := time.Parse(expiryLayout, ts)
+	return expiry, err == nil
+}
+
+// cleaner deletes the expired files of a destination in the background, at
+// most once per cleanupInterval.
+type cleaner struct {
+	dest destination
+
+	mu      sync.Mutex
+	last    time.Time
+	running bool
+}
+
+// run starts deleting the files expired before now, unless files were
+// deleted recently or are being deleted.
+func (c *cleaner) run(ctx context.Context, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running || (!c.last.IsZero() && now.Sub(c.last) < cleanupInterval) {
+		return
+	}
+	c.running, c.last = true, now
+
+	// the cleanup outlives the export starting it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			c.running = false
+			c.mu.Unlock()
+		}()
+		if err := c.dest.cleanup(ctx, now); err != nil {
+			if logger, err2 := util.LoggerFromContext(ctx); err2 == nil {
+				logger.WarnContext(ctx, fmt.Sprintf("unable to delete expired exports: %s", err))
+			}
+		}
+	}()
+}
+
+// file is an exported file being written. Close makes it available, and
+// Abort discards it.
+type file interface {
+	io.WriteCloser
+	Abort()
+	URI() string
+}
+
+// newDestination returns the destination of "gs://bucket/prefix", or of a
+// local directory, which is created if it does not exist.
+func newDestination(dest string) (destination, error) {
+	if rest, ok := strings.CutPrefix(dest, "gs://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid export destination %q: missing bucket", dest)
+		}
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		return &gcsDestination{bucket: bucket, prefix: prefix}, nil
+	}
+	dir, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export destination %q: %w", dest, err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("unable to create export directory: %w", err)
+	}
+	return localDestination{dir: dir}, nil
+}
+
+// localDestination writes files to a local directory.
+type localDestination struct {
+	dir string
+}
+
+func (d localDestination) create(_ context.Context, name, _ string) (file, error) {
+	path := filepath.Join(d.dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &localFile{File: f, path: path}, nil
+}
+
+func (d localDestination) cleanup(_ context.Context, t time.Time) error {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	// entries are sorted by name, and exported files by expiry
+	for _, e := range entries {
+		expiry, ok := fileExpiry(e.Name())
+		if e.IsDir() || !ok {
+			continue
+		}
+		if !expiry.Before(t) {
+			break
+		}
+		if err := os.Remove(filepath.Join(d.dir, e.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type localFile struct {
+	*os.File
+	path string
+}
+
+func (f *localFile) Abort() {
+	_ = f.File.Close()
+	_ = os.Remove(f.path)
+}
+
+func (f *localFile) URI() string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(f.path)}).String()
+}
+
+// gcsDestination writes files to a Cloud Storage bucket, using Application
+// Default Credentials.
+type gcsDestination struct {
+	bucket string
+	prefix string
+
+	once   sync.Once
+	client *storage.Client
+	err    error
+}
+
+// handle returns the bucket, creating the client on first use.
+func (d *gcsDestination) handle(ctx context.Context) (*storage.BucketHandle, error) {
+	d.once.Do(func() {
+		var opts []option.ClientOption
+		if ua, err := util.UserAgentFromContext(ctx); err == nil {
+			opts = append(opts, option.WithUserAgent(ua))
+		}
+		// the client outlives the request creating it
+		d.client, d.err = storage.NewClient(context.WithoutCancel(ctx), opts...)
+	})
+	if d.err != nil {
+		return nil, fmt.Errorf("unable to create Cloud Storage client: %w", d.err)
+	}
+	return d.client.Bucket(d.bucket), nil
+}
+
+func (d *gcsDestination) create(ctx context.Context, name, mimeType string) (file, error) {
+	b, err := d.handle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// canceling the context of the writer aborts the upload
+	ctx, cancel := context.WithCancel(ctx)
+	w := b.Object(d.prefix + name).NewWriter(ctx)
+	w.ContentType = mimeType
+	return &gcsFile{Writer: w, cancel: cancel, uri: fmt.Sprintf("gs://%s/%s%s", d.bucket, d.prefix, name)}, nil
+}
+
+func (d *gcsDestination) cleanup(ctx context.Context, t time.Time) error {
+	b, err := d.handle(ctx)
+	if err != nil {
+		return err
+	}
+	q := &storage.Query{Prefix: d.prefix + filePrefix}
+	if err := q.SetAttrSelection([]string{"Name"}); err != nil {
+		return err
+	}
+	// objects are listed by name, and exported files by expiry, so that
+	// only the expired files are listed
+	it := b.Objects(ctx, q)
+	var errs []error
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		expiry, ok := fileExpiry(strings.TrimPrefix(attrs.Name, d.prefix))
+		if !ok {
+			continue
+		}
+		if !expiry.Before(t) {
+			break
+		}
+		if err := b.Object(attrs.Name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type gcsFile struct {
+	*storage.Writer
+	cancel context.CancelFunc
+	uri    string
+}
+
+func (f *gcsFile) Close() error {
+	defer f.cancel()
+	return f.Writer.Close()
+}
+
+func (f *gcsFile) Abort() { f.cancel() }
+
+func (f *gcsFile) URI() string { return f.uri }
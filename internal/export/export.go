@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export writes the rows returned by tools configured with `export`
+// to files in a local directory or a Cloud Storage bucket, returning a link to
+// the file instead of the rows.
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// ParameterName is the name of the optional argument selecting the format of
+// the file the rows are exported to.
+const ParameterName = "export"
+
+const (
+	defaultRetention = 24 * time.Hour
+	// cleanupInterval is the minimum time between two deletions of the
+	// expired files of a destination by a tool.
+	cleanupInterval = time.Minute
+	// filePrefix prefixes the names of exported files, so that only those
+	// are deleted once expired.
+	filePrefix = "toolbox-export-"
+	// expiryLayout formats the expiry of an exported file in its name, after
+	// filePrefix. Files are deleted once expired whichever tool exported
+	// them, and are listed in order of expiry.
+	expiryLayout = "20060102T150405Z"
+)
+
+// Format is the format of an exported file.
+type Format string
+
+const (
+	Parquet Format = "parquet"
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+)
+
+// Enum returns the values allowed for a Format.
+func (Format) Enum() []string {
+	return []string{string(Parquet), string(CSV), string(NDJSON)}
+}
+
+// MimeType returns the media type of files in format f.
+func (f Format) MimeType() string {
+	switch f {
+	case Parquet:
+		return "application/vnd.apache.parquet"
+	case CSV:
+		return "text/csv"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// Config is the `export` block of a tool.
+type Config struct {
+	// Destination is a local directory, or a Cloud Storage bucket and an
+	// optional prefix, e.g. "gs://my-bucket/exports".
+	Destination string `yaml:"destination" validate:"required"`
+	// Retention is how long exported files are kept, e.g. "24h". Expired
+	// files are deleted in the background by the exports to the destination.
+	Retention string `yaml:"retention"`
+}
+
+// Column describes a column of an exported file.
+type Column struct {
+	Name string `json:"name"`
+	// Type is one of string, int64, float64, boolean, timestamp or binary.
+	Type string `json:"type"`
+}
+
+// Result is returned instead of the rows of an exported result.
+type Result struct {
+	URI       string    `json:"uri"`
+	Name      string    `json:"name"`
+	Format    Format    `json:"format"`
+	MimeType  string    `json:"mimeType"`
+	Schema    []Column  `json:"schema"`
+	RowCount  int64     `json:"rowCount"`
+	Size      int64     `json:"size"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ToolConfig wraps the config of a tool whose rows can be exported.
+type ToolConfig struct {
+	tools.ToolConfig
+	Name   string
+	Export Config
+}
+
+var _ tools.ComposedToolConfig = ToolConfig{}
+
+// Unwrap returns the config of the wrapped tool.
+func (cfg ToolConfig) Unwrap() tools.ToolConfig { return cfg.ToolConfig }
+
+// ToolNames returns the tools invoked by the wrapped tool.
+func (cfg ToolConfig) ToolNames() []string {
+	return tools.WrappedToolNames(cfg.ToolConfig)
+}
+
+func (cfg ToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return cfg.InitializeWithTools(srcs, nil)
+}
+
+func (cfg ToolConfig) InitializeWithTools(srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	retention := defaultRetention
+	if cfg.Export.Retention != "" {
+		var err error
+		retention, err = time.ParseDuration(cfg.Export.Retention)
+		if err != nil || retention <= 0 {
+			return nil, fmt.Errorf("invalid export retention %q: must be a positive duration", cfg.Export.Retention)
+		}
+	}
+	dest, err := newDestination(cfg.Export.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := tools.InitializeWrapped(cfg.ToolConfig, srcs, toolsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	param := tools.NewStringParameterWithDefault(ParameterName, "", fmt.Sprintf("If set, the rows are written to a file in this format, one of %q, and a link to the file is returned instead of the rows.", Format("").Enum()))
+	manifest := t.Manifest()
+	if slices.ContainsFunc(manifest.Parameters, func(p tools.ParameterManifest) bool { return p.Name == ParameterName }) {
+		return nil, fmt.Errorf("parameter name %q is reserved when export is set", ParameterName)
+	}
+	manifest.Parameters = append(slices.Clone(manifest.Parameters), param.Manifest())
+	mcpManifest := t.McpManifest()
+	properties := make(map[string]tools.ParameterMcpManifest, len(mcpManifest.InputSchema.Properties)+1)
+	for k, v := range mcpManifest.InputSchema.Properties {
+		properties[k] = v
+	}
+	properties[ParameterName], _ = param.McpManifest()
+	mcpManifest.InputSchema.Properties = properties
+
+	return Tool{
+		Tool:        t,
+		toolName:    cfg.Name,
+		dest:        dest,
+		cleaner:     &cleaner{dest: dest},
+		retention:   retention,
+		param:       param,
+		manifest:    manifest,
+		mcpManifest: mcpManifest,
+		now:         time.Now,
+	}, nil
+}
+
+// Tool exports the rows of the wrapped tool when the export argument is set.
+type Tool struct {
+	tools.Tool
+	toolName    string
+	dest        destination
+	cleaner     *cleaner
+	retention   time.Duration
+	param       tools.Parameter
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+	now         func() time.Time
+}
+
+var _ tools.Tool = Tool{}
+
+// Unwrap returns the wrapped tool.
+func (t Tool) Unwrap() tools.Tool { return t.Tool }
+
+func (t Tool) Manifest() tools.Manifest { return t.manifest }
+
+func (t Tool) McpManifest() tools.McpManifest { return t.mcpManifest }
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	params, err := t.Tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, err
+	}
+	export, err := tools.ParseParams(tools.Parameters{t.param}, data, claims)
+	if err != nil {
+		return nil, err
+	}
+	if f, _ := export[0].Value.(string); f != "" && !slices.Contains(Format("").Enum(), f) {
+		return nil, fmt.Errorf("%q is not a valid export format, must be one of %q", f, Format("").Enum())
+	}
+	return append(params, export...), nil
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	var format Format
+	rest := make(tools.ParamValues, 0, len(params))
+	for _, p := range params {
+		if p.Name == ParameterName {
+			f, _ := p.Value.(string)
+			format = Format(f)
+			continue
+		}
+		rest = append(rest, p)
+	}
+	if format == "" {
+		return t.Tool.Invoke(ctx, rest, accessToken)
+	}
+	return t.export(ctx, format, rest, accessToken)
+}
+
+// export invokes the wrapped tool, writing its rows to a new file.
+func (t Tool) export(ctx context.Context, format Format, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	now := t.now()
+	t.cleaner.run(ctx, now)
+
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	expiresAt := now.Add(t.retention).UTC().Truncate(time.Second)
+	name := fmt.Sprintf("%s%s-%s-%s.%s", filePrefix, expiresAt.Format(expiryLayout), t.toolName, hex.EncodeToString(b), format)
+	f, err := t.dest.create(ctx, name, format.MimeType())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create export file: %w", err)
+	}
+	s := newSink(f, format)
+	err = t.writeRows(ctx, s, params, accessToken)
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		f.Abort()
+		return nil, err
+	}
+	return Result{
+		URI:       f.URI(),
+		Name:      name,
+		Format:    format,
+		MimeType:  format.MimeType(),
+		Schema:    s.columns,
+		RowCount:  s.rows,
+		Size:      s.size,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// writeRows invokes the wrapped tool, streaming its rows to s. The rows of
+// tools that do not stream them are written once returned.
+func (t Tool) writeRows(ctx context.Context, s *sink, params tools.ParamValues, accessToken tools.AccessToken) error {
+	res, err := t.Tool.Invoke(tools.WithRowSink(ctx, s), params, accessToken)
+	if err != nil {
+		return err
+	}
+	if _, ok := res.(tools.StreamedResult); !ok {
+		rows, ok := asRows(res)
+		if !ok {
+			return fmt.Errorf("result of type %T cannot be exported", res)
+		}
+		for _, row := range rows {
+			if s.WriteRow(tools.MapRow(ctx, row)) != nil {
+				break
+			}
+		}
+	}
+	if err := s.close(); err != nil {
+		return fmt.Errorf("unable to write export file: %w", err)
+	}
+	return nil
+}
+
+// asRows returns the rows of a tool result.
+func asRows(res any) ([]map[string]any, bool) {
+	switch r := res.(type) {
+	case tools.PagedResult:
+		res = r.Rows
+	case tools.TransactionResult:
+		res = r.Rows
+	case []map[string]any:
+		return r, true
+	}
+	if res == nil {
+		return nil, true
+	}
+	list, ok := res.([]any)
+	if !ok {
+		return nil, false
+	}
+	rows := make([]map[string]any, len(list))
+	for i, v := range list {
+		if rows[i], ok = v.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+	return rows, true
+}
@@ -0,0 +1,367 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v15/arrow/memory"
+	"github.com/apache/arrow/go/v15/parquet/file"
+	"github.com/apache/arrow/go/v15/parquet/pqarrow"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/export"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+)
+
+var testRows = []any{
+	map[string]any{"id": int64(1), "name": "Alice", "score": 1.5, "joined": time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+	map[string]any{"id": int64(2), "name": nil, "score": int64(2), "joined": nil},
+}
+
+// rowsConfig initializes a fake tool returning rows, or streaming them if
+// stream is set.
+func rowsConfig(rows []any, stream bool) toolstest.Config {
+	return toolstest.Config{InvokeFunc: func(ctx context.Context, _ tools.ParamValues) (any, error) {
+		if !stream {
+			return rows, nil
+		}
+		c, err := tools.NewRowCollector(ctx, tools.ResultLimits{MaxRows: 1}, "", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if !c.Add(r) {
+				break
+			}
+		}
+		return c.Result(), nil
+	}}
+}
+
+func TestParseFromYamlExport(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM sales;
+			export:
+				destination: gs://my-bucket/exports
+				retention: 2h
+	`
+	want := server.ToolConfigs{
+		"example_tool": export.ToolConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "example_tool",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT * FROM sales;",
+				AuthRequired: []string{},
+			},
+			Name:   "example_tool",
+			Export: export.Config{Destination: "gs://my-bucket/exports", Retention: "2h"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFailParseFromYamlExport(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{desc: "missing destination", in: "{retention: 2h}", err: "Destination"},
+		{desc: "invalid retention", in: "{destination: /tmp, retention: soon}", err: `invalid retention "soon"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			in := `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					export: ` + tc.in
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+// initTool returns the export tool wrapping cfg, exporting to dir.
+func initTool(t *testing.T, cfg toolstest.Config, dir string) tools.Tool {
+	t.Helper()
+	tool, err := export.ToolConfig{ToolConfig: cfg, Name: "sales", Export: export.Config{Destination: dir}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+// invoke invokes tool with the export argument set to format.
+func invoke(t *testing.T, tool tools.Tool, format string) export.Result {
+	t.Helper()
+	params, err := tool.ParseParams(map[string]any{export.ParameterName: format}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	res, err := tool.Invoke(context.Background(), params, "")
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	r, ok := res.(export.Result)
+	if !ok {
+		t.Fatalf("unexpected result of type %T", res)
+	}
+	return r
+}
+
+func TestExport(t *testing.T) {
+	wantSchema := []export.Column{
+		{Name: "id", Type: "int64"},
+		{Name: "joined", Type: "timestamp"},
+		{Name: "name", Type: "string"},
+		{Name: "score", Type: "float64"},
+	}
+	tcs := []struct {
+		desc   string
+		format string
+		stream bool
+		want   string
+	}{
+		{
+			desc:   "ndjson",
+			format: "ndjson",
+			want: `{"id":1,"joined":"2025-01-02T03:04:05Z","name":"Alice","score":1.5}` + "\n" +
+				`{"id":2,"joined":null,"name":null,"score":2}` + "\n",
+		},
+		{
+			desc:   "csv",
+			format: "csv",
+			stream: true,
+			want:   "id,joined,name,score\n1,2025-01-02T03:04:05Z,Alice,1.5\n2,,,2\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			tool := initTool(t, rowsConfig(testRows, tc.stream), dir)
+			res := invoke(t, tool, tc.format)
+
+			path := filepath.Join(dir, res.Name)
+			if want := "file://" + filepath.ToSlash(path); res.URI != want {
+				t.Fatalf("incorrect URI: got %q, want %q", res.URI, want)
+			}
+			if !strings.HasPrefix(res.Name, "toolbox-export-") || !strings.Contains(res.Name, "-sales-") || !strings.HasSuffix(res.Name, "."+tc.format) {
+				t.Fatalf("unexpected file name %q", res.Name)
+			}
+			if diff := cmp.Diff(wantSchema, res.Schema); diff != "" {
+				t.Fatalf("incorrect schema (-want +got):\n%s", diff)
+			}
+			if res.RowCount != 2 {
+				t.Fatalf("incorrect row count: got %d, want 2", res.RowCount)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unable to read export: %s", err)
+			}
+			if string(b) != tc.want {
+				t.Fatalf("incorrect export: got %q, want %q", b, tc.want)
+			}
+			if res.Size != int64(len(b)) {
+				t.Fatalf("incorrect size: got %d, want %d", res.Size, len(b))
+			}
+		})
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	dir := t.TempDir()
+	tool := initTool(t, rowsConfig(testRows, true), dir)
+	res := invoke(t, tool, "parquet")
+
+	rdr, err := file.OpenParquetFile(filepath.Join(dir, res.Name), false)
+	if err != nil {
+		t.Fatalf("unable to open parquet file: %s", err)
+	}
+	defer rdr.Close()
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("unable to read parquet file: %s", err)
+	}
+	table, err := fr.ReadTable(context.Background())
+	if err != nil {
+		t.Fatalf("unable to read parquet table: %s", err)
+	}
+	defer table.Release()
+	if table.NumRows() != 2 {
+		t.Fatalf("incorrect number of rows: got %d, want 2", table.NumRows())
+	}
+	var names []string
+	for _, f := range table.Schema().Fields() {
+		names = append(names, f.Name+":"+f.Type.String())
+	}
+	want := []string{"id:int64", "joined:timestamp[us, tz=UTC]", "name:utf8", "score:float64"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Fatalf("incorrect schema (-want +got):\n%s", diff)
+	}
+}
+
+func TestExportNotRequested(t *testing.T) {
+	tool := initTool(t, rowsConfig(testRows, false), t.TempDir())
+	params, err := tool.ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	res, err := tool.Invoke(context.Background(), params, "")
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	if diff := cmp.Diff(testRows, res); diff != "" {
+		t.Fatalf("incorrect result (-want +got):\n%s", diff)
+	}
+}
+
+func TestExportManifest(t *testing.T) {
+	params := tools.Parameters{tools.NewStringParameter("region", "the region")}
+	tool := initTool(t, toolstest.Config{Parameters: params}, t.TempDir())
+
+	var names []string
+	for _, p := range tool.Manifest().Parameters {
+		names = append(names, p.Name)
+	}
+	if diff := cmp.Diff([]string{"region", export.ParameterName}, names); diff != "" {
+		t.Fatalf("incorrect parameters (-want +got):\n%s", diff)
+	}
+	if _, ok := tool.McpManifest().InputSchema.Properties[export.ParameterName]; !ok {
+		t.Fatalf("missing %q in MCP manifest", export.ParameterName)
+	}
+
+	if _, err := tool.ParseParams(map[string]any{"region": "eu", export.ParameterName: "xlsx"}, nil); err == nil {
+		t.Fatalf("expected error parsing invalid export format")
+	}
+
+	reserved := tools.Parameters{tools.NewStringParameter(export.ParameterName, "reserved")}
+	_, err := export.ToolConfig{ToolConfig: toolstest.Config{Parameters: reserved}, Name: "sales", Export: export.Config{Destination: t.TempDir()}}.Initialize(nil)
+	if err == nil {
+		t.Fatalf("expected error initializing tool with a parameter named %q", export.ParameterName)
+	}
+}
+
+func TestExportRowMapper(t *testing.T) {
+	dir := t.TempDir()
+	tool := initTool(t, rowsConfig(testRows, true), dir)
+	params, err := tool.ParseParams(map[string]any{export.ParameterName: "ndjson"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	// a data policy dropping every column but id
+	ctx := tools.WithRowMapper(context.Background(), func(row map[string]any) map[string]any {
+		return map[string]any{"id": row["id"]}
+	})
+	res, err := tool.Invoke(ctx, params, "")
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, res.(export.Result).Name))
+	if err != nil {
+		t.Fatalf("unable to read export: %s", err)
+	}
+	if want := "{\"id\":1}\n{\"id\":2}\n"; string(b) != want {
+		t.Fatalf("incorrect export: got %q, want %q", b, want)
+	}
+}
+
+func TestExportRetention(t *testing.T) {
+	dir := t.TempDir()
+	expired := "toolbox-export-" + time.Now().Add(-time.Hour).UTC().Format("20060102T150405Z") + "-sales-0000.csv"
+	// a file of a tool with a longer retention, exported long ago
+	kept := "toolbox-export-" + time.Now().Add(time.Hour).UTC().Format("20060102T150405Z") + "-archive-0000.csv"
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{expired, kept, "other.csv"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatalf("unable to write file: %s", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("unable to change file times: %s", err)
+		}
+	}
+	tool := initTool(t, rowsConfig(testRows, false), dir)
+	res := invoke(t, tool, "csv")
+
+	// expired files are deleted in the background
+	var names []string
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("unable to read dir: %s", err)
+		}
+		names = names[:0]
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if !slices.Contains(names, expired) {
+			break
+		}
+	}
+	want := []string{"other.csv", kept, res.Name}
+	if diff := cmp.Diff(want, names, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Fatalf("incorrect files (-want +got):\n%s", diff)
+	}
+	if res.ExpiresAt.Before(time.Now().Add(23 * time.Hour)) {
+		t.Fatalf("unexpected expiry %s with the default retention", res.ExpiresAt)
+	}
+	if !strings.HasPrefix(res.Name, "toolbox-export-"+res.ExpiresAt.Format("20060102T150405Z")+"-") {
+		t.Fatalf("expected file name %q to start with its expiry", res.Name)
+	}
+}
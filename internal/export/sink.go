@@ -0,0 +1,386 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/memory"
+	"github.com/apache/arrow/go/v15/parquet"
+	"github.com/apache/arrow/go/v15/parquet/compress"
+	"github.com/apache/arrow/go/v15/parquet/pqarrow"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
+)
+
+// batchSize is the number of rows buffered before they are written. The
+// schema is inferred from the first batch.
+const batchSize = 1024
+
+// Column types.
+const (
+	typeString    = "string"
+	typeInt64     = "int64"
+	typeFloat64   = "float64"
+	typeBoolean   = "boolean"
+	typeTimestamp = "timestamp"
+	typeBinary    = "binary"
+)
+
+// sink is a tools.RowSink writing rows to a file.
+type sink struct {
+	w       *countingWriter
+	format  Format
+	enc     encoder
+	columns []Column
+	pending []map[string]any
+	rows    int64
+	size    int64
+	// err is the first error writing rows, after which rows are dropped.
+	err error
+}
+
+func newSink(w io.Writer, format Format) *sink {
+	return &sink{w: &countingWriter{w: w}, format: format}
+}
+
+func (s *sink) WriteRow(row any) error {
+	if s.err != nil {
+		return s.err
+	}
+	m, ok := row.(map[string]any)
+	if !ok {
+		s.err = fmt.Errorf("unable to export row of type %T", row)
+		return s.err
+	}
+	s.pending = append(s.pending, m)
+	s.rows++
+	if len(s.pending) >= batchSize {
+		s.err = s.flush()
+	}
+	return s.err
+}
+
+// flush writes the pending rows.
+func (s *sink) flush() error {
+	if s.enc == nil {
+		s.columns = inferColumns(s.pending)
+		var err error
+		if s.enc, err = newEncoder(s.format, s.w, s.columns); err != nil {
+			return err
+		}
+	}
+	if len(s.pending) == 0 {
+		return nil
+	}
+	err := s.enc.write(s.pending)
+	s.pending = s.pending[:0]
+	return err
+}
+
+// close writes the pending rows and ends the file.
+func (s *sink) close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.flush(); err != nil {
+		return err
+	}
+	if err := s.enc.close(); err != nil {
+		return err
+	}
+	s.size = s.w.n
+	return nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// inferColumns returns the columns of rows, sorted by name, typed by their
+// non-null values.
+func inferColumns(rows []map[string]any) []Column {
+	types := map[string]string{}
+	for _, row := range rows {
+		for name, v := range row {
+			t, ok := types[name]
+			if v == nil {
+				if !ok {
+					types[name] = ""
+				}
+				continue
+			}
+			types[name] = mergeTypes(t, typeOf(v))
+		}
+	}
+	columns := make([]Column, 0, len(types))
+	for _, name := range slices.Sorted(maps.Keys(types)) {
+		t := types[name]
+		if t == "" {
+			// columns with only null values
+			t = typeString
+		}
+		columns = append(columns, Column{Name: name, Type: t})
+	}
+	return columns
+}
+
+// typeOf returns the column type of a non-null value.
+func typeOf(v any) string {
+	switch v.(type) {
+	case bool:
+		return typeBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return typeInt64
+	case float32, float64:
+		return typeFloat64
+	case time.Time:
+		return typeTimestamp
+	case []byte:
+		return typeBinary
+	default:
+		return typeString
+	}
+}
+
+// mergeTypes returns the type of a column with values of types a and b.
+func mergeTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case (a == typeInt64 && b == typeFloat64) || (a == typeFloat64 && b == typeInt64):
+		return typeFloat64
+	default:
+		return typeString
+	}
+}
+
+// encoder writes rows in a file format.
+type encoder interface {
+	write(rows []map[string]any) error
+	close() error
+}
+
+func newEncoder(format Format, w io.Writer, columns []Column) (encoder, error) {
+	switch format {
+	case Parquet:
+		return newParquetEncoder(w, columns)
+	case CSV:
+		e := &csvEncoder{w: csv.NewWriter(w), columns: columns}
+		header := make([]string, len(columns))
+		for i, c := range columns {
+			header[i] = c.Name
+		}
+		return e, e.w.Write(header)
+	case NDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) write(rows []map[string]any) error {
+	for _, row := range rows {
+		out := make(map[string]any, len(row))
+		for k, v := range row {
+			out[k] = resultformat.JSONValue(v)
+		}
+		if err := e.enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ndjsonEncoder) close() error { return nil }
+
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []Column
+}
+
+func (e *csvEncoder) write(rows []map[string]any) error {
+	record := make([]string, len(e.columns))
+	for _, row := range rows {
+		for i, c := range e.columns {
+			record[i] = resultformat.TextValue(row[c.Name])
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) close() error { return nil }
+
+type parquetEncoder struct {
+	w       *pqarrow.FileWriter
+	columns []Column
+	builder *array.RecordBuilder
+}
+
+func newParquetEncoder(w io.Writer, columns []Column) (*parquetEncoder, error) {
+	fields := make([]arrow.Field, len(columns))
+	for i, c := range columns {
+		fields[i] = arrow.Field{Name: c.Name, Type: arrowType(c.Type), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	// the file is closed by the destination, not by the parquet writer
+	fw, err := pqarrow.NewFileWriter(schema, struct{ io.Writer }{w}, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	return &parquetEncoder{w: fw, columns: columns, builder: array.NewRecordBuilder(memory.DefaultAllocator, schema)}, nil
+}
+
+func arrowType(t string) arrow.DataType {
+	switch t {
+	case typeBoolean:
+		return arrow.FixedWidthTypes.Boolean
+	case typeInt64:
+		return arrow.PrimitiveTypes.Int64
+	case typeFloat64:
+		return arrow.PrimitiveTypes.Float64
+	case typeTimestamp:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	case typeBinary:
+		return arrow.BinaryTypes.Binary
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func (e *parquetEncoder) write(rows []map[string]any) error {
+	for _, row := range rows {
+		for i, c := range e.columns {
+			if err := appendValue(e.builder.Field(i), c, row[c.Name]); err != nil {
+				return err
+			}
+		}
+	}
+	rec := e.builder.NewRecord()
+	defer rec.Release()
+	return e.w.WriteBuffered(rec)
+}
+
+func (e *parquetEncoder) close() error {
+	e.builder.Release()
+	return e.w.Close()
+}
+
+// appendValue appends v to the builder of column c.
+func appendValue(b array.Builder, c Column, v any) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	ok := true
+	switch b := b.(type) {
+	case *array.BooleanBuilder:
+		var x bool
+		if x, ok = v.(bool); ok {
+			b.Append(x)
+		}
+	case *array.Int64Builder:
+		var x int64
+		if x, ok = toInt64(v); ok {
+			b.Append(x)
+		}
+	case *array.Float64Builder:
+		var x float64
+		if x, ok = toFloat64(v); ok {
+			b.Append(x)
+		}
+	case *array.TimestampBuilder:
+		var x time.Time
+		if x, ok = v.(time.Time); ok {
+			b.Append(arrow.Timestamp(x.UnixMicro()))
+		}
+	case *array.BinaryBuilder:
+		var x []byte
+		if x, ok = v.([]byte); ok {
+			b.Append(x)
+		}
+	case *array.StringBuilder:
+		// any value is written as text to string columns
+		b.Append(resultformat.TextValue(v))
+	}
+	if !ok {
+		return fmt.Errorf("column %q of type %s has a value of type %T", c.Name, c.Type, v)
+	}
+	return nil
+}
+
+func toInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	}
+	return 0, false
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	if x, ok := toInt64(v); ok {
+		return float64(x), true
+	}
+	return 0, false
+}
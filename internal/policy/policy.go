@@ -0,0 +1,372 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy protects the data returned by tools with `dataPolicy`
+// blocks, which mask result columns and filter rows by the verified claims of
+// the caller.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const (
+	// ActionMask replaces the values of a column.
+	ActionMask = "mask"
+	// ActionDrop removes a column from the result.
+	ActionDrop = "drop"
+
+	defaultMask = "****"
+)
+
+// Config is the `dataPolicy` block of a tool or source.
+type Config struct {
+	// Columns mask or drop result columns unless a claim grants access.
+	Columns []ColumnRule `yaml:"columns"`
+	// Rows restrict the rows returned by SQL tools to those matching a
+	// claim of the caller.
+	Rows []RowRule `yaml:"rows"`
+}
+
+// ColumnRule masks or drops the result columns matching one of its patterns.
+type ColumnRule struct {
+	// Match are the names of the columns, or patterns such as `*_IBAN`
+	// using the syntax of path.Match. Names are matched case-insensitively.
+	Match []string `yaml:"match" validate:"required"`
+	// Action is either "mask" (the default) or "drop".
+	Action string `yaml:"action"`
+	// Mask is the value replacing the values of masked columns. Defaults to
+	// "****".
+	Mask string `yaml:"mask"`
+	// Allow are the claims granting access to the columns.
+	Allow []Grant `yaml:"allow"`
+}
+
+// RowRule restricts rows to those whose column equals a claim of the caller.
+type RowRule struct {
+	Column      string `yaml:"column" validate:"required"`
+	AuthService string `yaml:"authService" validate:"required"`
+	Claim       string `yaml:"claim" validate:"required"`
+	// Allow are the claims granting access to every row.
+	Allow []Grant `yaml:"allow"`
+}
+
+// Grant is a claim granting access to protected data.
+type Grant struct {
+	AuthService string `yaml:"authService" validate:"required"`
+	Claim       string `yaml:"claim" validate:"required"`
+	// Values are the values of the claim granting access. If empty, any
+	// value other than false or empty grants access. A claim listing
+	// several values, such as groups, grants access if one of them matches.
+	Values []string `yaml:"values"`
+}
+
+// validate checks the patterns and actions of cfg.
+func (cfg Config) validate() error {
+	for i, c := range cfg.Columns {
+		if len(c.Match) == 0 {
+			return fmt.Errorf("column rule %d must match at least one column", i)
+		}
+		for _, m := range c.Match {
+			if _, err := path.Match(m, ""); err != nil {
+				return fmt.Errorf("invalid column pattern %q: %w", m, err)
+			}
+		}
+		switch c.Action {
+		case "", ActionMask, ActionDrop:
+		default:
+			return fmt.Errorf("invalid action %q of column rule %d: must be one of %q", c.Action, i, []string{ActionMask, ActionDrop})
+		}
+	}
+	for i, r := range cfg.Rows {
+		if r.Column == "" || r.AuthService == "" || r.Claim == "" {
+			return fmt.Errorf("row rule %d requires a column, an authService and a claim", i)
+		}
+	}
+	return nil
+}
+
+// granted reports whether one of grants is satisfied by claims.
+func granted(grants []Grant, claims map[string]map[string]any) bool {
+	for _, g := range grants {
+		v, ok := claims[g.AuthService][g.Claim]
+		if !ok {
+			continue
+		}
+		if len(g.Values) == 0 {
+			if v != nil && v != false && v != "" {
+				return true
+			}
+			continue
+		}
+		for _, c := range claimValues(v) {
+			if slices.Contains(g.Values, fmt.Sprint(c)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimValues returns the values of a claim listing several values, or the
+// claim itself.
+func claimValues(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case []string:
+		values := make([]any, len(v))
+		for i, s := range v {
+			values[i] = s
+		}
+		return values
+	default:
+		return []any{v}
+	}
+}
+
+// Policy is a Config resolved for the claims of a caller.
+type Policy struct {
+	columns []ColumnRule
+	filters []tools.RowFilter
+}
+
+// Resolve returns the policy of cfg for claims, failing if a row rule
+// requires a claim the caller does not have.
+func (cfg Config) Resolve(claims map[string]map[string]any) (Policy, error) {
+	var p Policy
+	for _, c := range cfg.Columns {
+		if !granted(c.Allow, claims) {
+			p.columns = append(p.columns, c)
+		}
+	}
+	for _, r := range cfg.Rows {
+		if granted(r.Allow, claims) {
+			continue
+		}
+		v, ok := claims[r.AuthService][r.Claim]
+		if !ok || v == nil {
+			return Policy{}, fmt.Errorf("access denied: missing claim %q of auth service %q", r.Claim, r.AuthService)
+		}
+		if values, ok := v.([]string); ok {
+			v = claimValues(values)
+		}
+		p.filters = append(p.filters, tools.RowFilter{Column: r.Column, Value: v})
+	}
+	return p, nil
+}
+
+// rule returns the rule protecting column, or nil.
+func (p Policy) rule(column string) *ColumnRule {
+	name := strings.ToLower(column)
+	for i, c := range p.columns {
+		for _, m := range c.Match {
+			if ok, _ := path.Match(strings.ToLower(m), name); ok {
+				return &p.columns[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Apply returns res with the protected columns of its rows masked or
+// dropped. Rows are maps of column names to values, in a list, a
+// tools.PagedResult or a tools.TransactionResult. Other results fail, since
+// their columns cannot be protected.
+func (p Policy) Apply(res any) (any, error) {
+	if len(p.columns) == 0 || res == nil {
+		return res, nil
+	}
+	switch r := res.(type) {
+	case []any:
+		return p.applyRows(r)
+	case []map[string]any:
+		out := make([]map[string]any, len(r))
+		for i, row := range r {
+			out[i] = p.applyRow(row)
+		}
+		return out, nil
+	case tools.PagedResult:
+		rows, err := p.applyRows(r.Rows)
+		if err != nil {
+			return nil, err
+		}
+		r.Rows = rows
+		return r, nil
+	case tools.TransactionResult:
+		rows, err := p.applyRows(r.Rows)
+		if err != nil {
+			return nil, err
+		}
+		r.Rows = rows
+		return r, nil
+	default:
+		return nil, fmt.Errorf("unable to apply the column rules of the data policy to a result of type %T", res)
+	}
+}
+
+func (p Policy) applyRows(rows []any) ([]any, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	out := make([]any, len(rows))
+	for i, row := range rows {
+		switch r := row.(type) {
+		case map[string]any:
+			out[i] = p.applyRow(r)
+		case nil:
+		default:
+			return nil, fmt.Errorf("unable to apply the column rules of the data policy to a row of type %T", row)
+		}
+	}
+	return out, nil
+}
+
+// applyRow returns a copy of row with its protected columns masked or
+// dropped.
+func (p Policy) applyRow(row map[string]any) map[string]any {
+	out := make(map[string]any, len(row))
+	for k, v := range row {
+		r := p.rule(k)
+		switch {
+		case r == nil:
+			out[k] = v
+		case r.Action == ActionDrop:
+		case v == nil:
+			out[k] = nil
+		case r.Mask != "":
+			out[k] = r.Mask
+		default:
+			out[k] = defaultMask
+		}
+	}
+	return out
+}
+
+// ToolConfig wraps the config of a tool with a data policy.
+type ToolConfig struct {
+	tools.ToolConfig
+	Name   string
+	Policy Config
+}
+
+var _ tools.ComposedToolConfig = ToolConfig{}
+
+// ToolNames returns the tools invoked by the wrapped tool.
+func (cfg ToolConfig) ToolNames() []string {
+	return tools.WrappedToolNames(cfg.ToolConfig)
+}
+
+func (cfg ToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return cfg.InitializeWithTools(srcs, nil)
+}
+
+func (cfg ToolConfig) InitializeWithTools(srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	if err := cfg.Policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid data policy: %w", err)
+	}
+	if len(cfg.Policy.Rows) > 0 && !supportsRowFilters(cfg.ToolConfig) {
+		return nil, fmt.Errorf("tool kind %q does not support row rules of data policies", cfg.ToolConfigKind())
+	}
+	if len(cfg.Policy.Columns) > 0 && !supportsColumnMasks(cfg.ToolConfig) {
+		return nil, fmt.Errorf("tool kind %q does not support column rules of data policies", cfg.ToolConfigKind())
+	}
+
+	t, err := tools.InitializeWrapped(cfg.ToolConfig, srcs, toolsMap)
+	if err != nil {
+		return nil, err
+	}
+	return Tool{Tool: t, policy: cfg.Policy}, nil
+}
+
+// supportsRowFilters reports whether the tool of tc, which may be wrapped by
+// other configs embedding it, applies row filters.
+func supportsRowFilters(tc tools.ToolConfig) bool {
+	for {
+		if c, ok := tc.(tools.RowFilterConfig); ok {
+			return c.SupportsRowFilters()
+		}
+		switch c := tc.(type) {
+		case ToolConfig:
+			tc = c.ToolConfig
+		case interface{ Unwrap() tools.ToolConfig }:
+			tc = c.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// supportsColumnMasks reports whether the result columns of the tool of tc,
+// which may be wrapped by other configs embedding it, can be masked by name.
+func supportsColumnMasks(tc tools.ToolConfig) bool {
+	for {
+		if c, ok := tc.(tools.ColumnMaskConfig); ok {
+			return c.SupportsColumnMasks()
+		}
+		switch c := tc.(type) {
+		case ToolConfig:
+			tc = c.ToolConfig
+		case interface{ Unwrap() tools.ToolConfig }:
+			tc = c.Unwrap()
+		default:
+			return true
+		}
+	}
+}
+
+// Tool applies a data policy to the invocations of the wrapped tool.
+type Tool struct {
+	tools.Tool
+	policy Config
+}
+
+var _ tools.Tool = Tool{}
+
+// Unwrap returns the wrapped tool.
+func (t Tool) Unwrap() tools.Tool { return t.Tool }
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	p, err := t.policy.Resolve(util.ClaimsFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(p.filters) > 0 {
+		ctx = tools.WithRowFilters(ctx, p.filters)
+	}
+	if len(p.columns) > 0 {
+		// rows streamed to a sink, such as an export, bypass Apply
+		ctx = tools.WithRowMapper(ctx, p.applyRow)
+	}
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	if err != nil {
+		return res, err
+	}
+	return p.Apply(res)
+}
+
+// SourceConfig wraps the config of a source with a data policy applied to
+// every tool using the source.
+type SourceConfig struct {
+	sources.SourceConfig
+	Policy Config
+}
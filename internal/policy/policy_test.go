@@ -0,0 +1,360 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/policy"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/pipeline"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestParseFromYamlDataPolicy(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM accounts;
+			dataPolicy:
+				columns:
+					- match: ["*_iban", "email"]
+					  allow:
+						- authService: my-google-auth
+						  claim: groups
+						  values: ["finance"]
+					- match: ["ssn"]
+					  action: drop
+				rows:
+					- column: tenant_id
+					  authService: my-google-auth
+					  claim: tenant
+	`
+	want := server.ToolConfigs{
+		"example_tool": policy.ToolConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "example_tool",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT * FROM accounts;",
+				AuthRequired: []string{},
+			},
+			Name: "example_tool",
+			Policy: policy.Config{
+				Columns: []policy.ColumnRule{
+					{
+						Match: []string{"*_iban", "email"},
+						Allow: []policy.Grant{{AuthService: "my-google-auth", Claim: "groups", Values: []string{"finance"}}},
+					},
+					{Match: []string{"ssn"}, Action: policy.ActionDrop},
+				},
+				Rows: []policy.RowRule{{Column: "tenant_id", AuthService: "my-google-auth", Claim: "tenant"}},
+			},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestParseFromYamlSourceDataPolicy(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	sources:
+		my-pg-instance:
+			kind: postgres
+			host: localhost
+			port: "5432"
+			database: db
+			user: user
+			password: pass
+			dataPolicy:
+				columns:
+					- match: ["email"]
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	sc, ok := got.Sources["my-pg-instance"].(policy.SourceConfig)
+	if !ok {
+		t.Fatalf("expected source config to be wrapped with its data policy, got %T", got.Sources["my-pg-instance"])
+	}
+	if sc.SourceConfigKind() != "postgres" {
+		t.Errorf("unexpected source kind %q", sc.SourceConfigKind())
+	}
+	want := policy.Config{Columns: []policy.ColumnRule{{Match: []string{"email"}}}}
+	if diff := cmp.Diff(want, sc.Policy); diff != "" {
+		t.Errorf("incorrect policy: diff %v", diff)
+	}
+}
+
+func TestFailParseFromYamlDataPolicy(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM accounts;
+			dataPolicy:
+				columns:
+					- match: ["email"]
+					  redact: true
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil {
+		t.Fatalf("expect parsing to fail")
+	}
+	if !strings.Contains(err.Error(), "unable to parse dataPolicy of tool \"example_tool\"") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestToolColumns(t *testing.T) {
+	rows := []any{
+		map[string]any{"id": 1, "ACCOUNT_IBAN": "DE89370400440532013000", "Email": "a@example.com", "ssn": "123", "note": nil},
+		map[string]any{"id": 2, "ACCOUNT_IBAN": nil, "Email": "b@example.com", "ssn": "456", "note": "x"},
+	}
+	cfg := policy.ToolConfig{
+		ToolConfig: toolstest.Config{Result: tools.PagedResult{Rows: rows, NextPageToken: "next"}},
+		Name:       "my-tool",
+		Policy: policy.Config{Columns: []policy.ColumnRule{
+			{
+				Match: []string{"*_iban", "EMAIL"},
+				Allow: []policy.Grant{{AuthService: "my-auth", Claim: "groups", Values: []string{"finance"}}},
+			},
+			{Match: []string{"ssn"}, Action: policy.ActionDrop},
+			{Match: []string{"note"}, Mask: "[redacted]"},
+		}},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	tcs := []struct {
+		desc   string
+		claims map[string]map[string]any
+		want   []any
+	}{
+		{
+			desc: "masked",
+			want: []any{
+				map[string]any{"id": 1, "ACCOUNT_IBAN": "****", "Email": "****", "note": nil},
+				map[string]any{"id": 2, "ACCOUNT_IBAN": nil, "Email": "****", "note": "[redacted]"},
+			},
+		},
+		{
+			desc:   "granted",
+			claims: map[string]map[string]any{"my-auth": {"groups": []any{"eng", "finance"}}},
+			want: []any{
+				map[string]any{"id": 1, "ACCOUNT_IBAN": "DE89370400440532013000", "Email": "a@example.com", "note": nil},
+				map[string]any{"id": 2, "ACCOUNT_IBAN": nil, "Email": "b@example.com", "note": "[redacted]"},
+			},
+		},
+		{
+			desc:   "claim of another auth service",
+			claims: map[string]map[string]any{"other-auth": {"groups": []any{"finance"}}},
+			want: []any{
+				map[string]any{"id": 1, "ACCOUNT_IBAN": "****", "Email": "****", "note": nil},
+				map[string]any{"id": 2, "ACCOUNT_IBAN": nil, "Email": "****", "note": "[redacted]"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := util.WithClaims(context.Background(), tc.claims)
+			res, err := tool.Invoke(ctx, nil, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			want := tools.PagedResult{Rows: tc.want, NextPageToken: "next"}
+			if diff := cmp.Diff(want, res); diff != "" {
+				t.Errorf("incorrect result: diff %v", diff)
+			}
+		})
+	}
+	// the rows of the wrapped tool are not modified
+	if rows[0].(map[string]any)["Email"] != "a@example.com" {
+		t.Errorf("expected rows of the wrapped tool to be unchanged")
+	}
+}
+
+func TestToolColumnsUnknownResult(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		result any
+		err    string
+	}{
+		{desc: "object", result: map[string]any{"ssn": "123"}, err: "result of type map[string]interface {}"},
+		{desc: "scalar rows", result: []any{"123"}, err: "row of type string"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := policy.ToolConfig{
+				ToolConfig: toolstest.Config{Result: tc.result},
+				Name:       "my-tool",
+				Policy:     policy.Config{Columns: []policy.ColumnRule{{Match: []string{"ssn"}}}},
+			}
+			tool, err := cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			_, err = tool.Invoke(context.Background(), nil, "")
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestToolRows(t *testing.T) {
+	var filters []tools.RowFilter
+	cfg := policy.ToolConfig{
+		ToolConfig: toolstest.Config{
+			RowFilters: true,
+			InvokeFunc: func(ctx context.Context, _ tools.ParamValues) (any, error) {
+				filters = tools.RowFiltersFromContext(ctx)
+				return []any{}, nil
+			},
+		},
+		Name: "my-tool",
+		Policy: policy.Config{Rows: []policy.RowRule{{
+			Column:      "TENANT_ID",
+			AuthService: "my-auth",
+			Claim:       "tenant",
+			Allow:       []policy.Grant{{AuthService: "my-auth", Claim: "admin"}},
+		}}},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	ctx := util.WithClaims(context.Background(), map[string]map[string]any{"my-auth": {"tenant": "acme"}})
+	if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []tools.RowFilter{{Column: "TENANT_ID", Value: "acme"}}
+	if diff := cmp.Diff(want, filters); diff != "" {
+		t.Errorf("incorrect row filters: diff %v", diff)
+	}
+
+	ctx = util.WithClaims(context.Background(), map[string]map[string]any{"my-auth": {"tenant": "acme", "admin": true}})
+	if _, err := tool.Invoke(ctx, nil, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(filters) != 0 {
+		t.Errorf("expected no row filters for a granted caller, got %v", filters)
+	}
+
+	_, err = tool.Invoke(context.Background(), nil, "")
+	if err == nil || !strings.Contains(err.Error(), `missing claim "tenant"`) {
+		t.Errorf("expected error for a caller without the claim, got %v", err)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  policy.ToolConfig
+		err  string
+	}{
+		{
+			desc: "row rules of a tool not supporting them",
+			cfg: policy.ToolConfig{
+				ToolConfig: toolstest.Config{},
+				Policy:     policy.Config{Rows: []policy.RowRule{{Column: "c", AuthService: "a", Claim: "c"}}},
+			},
+			err: `tool kind "fake" does not support row rules of data policies`,
+		},
+		{
+			desc: "column rules of a tool running statements of the caller",
+			cfg: policy.ToolConfig{
+				ToolConfig: postgresexecutesql.Config{Kind: "postgres-execute-sql"},
+				Policy:     policy.Config{Columns: []policy.ColumnRule{{Match: []string{"ssn"}}}},
+			},
+			err: `tool kind "postgres-execute-sql" does not support column rules of data policies`,
+		},
+		{
+			desc: "column rules of a pipeline",
+			cfg: policy.ToolConfig{
+				ToolConfig: pipeline.Config{Kind: "pipeline"},
+				Policy:     policy.Config{Columns: []policy.ColumnRule{{Match: []string{"ssn"}}}},
+			},
+			err: `tool kind "pipeline" does not support column rules of data policies`,
+		},
+		{
+			desc: "invalid pattern",
+			cfg: policy.ToolConfig{
+				ToolConfig: toolstest.Config{},
+				Policy:     policy.Config{Columns: []policy.ColumnRule{{Match: []string{"[a"}}}},
+			},
+			err: `invalid column pattern "[a"`,
+		},
+		{
+			desc: "invalid action",
+			cfg: policy.ToolConfig{
+				ToolConfig: toolstest.Config{},
+				Policy:     policy.Config{Columns: []policy.ColumnRule{{Match: []string{"a"}, Action: "hash"}}},
+			},
+			err: `invalid action "hash"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(nil)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
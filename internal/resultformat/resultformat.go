@@ -0,0 +1,338 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resultformat encodes the rows returned by tools in formats cheaper
+// for LLMs to read than a JSON object per row.
+package resultformat
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// Format is the encoding of the rows of a result.
+type Format string
+
+const (
+	// JSONRows encodes each row as a JSON object. It is the default.
+	JSONRows Format = "json-rows"
+	// JSONColumnar encodes the rows as a JSON object with the names of the
+	// columns and an array of values per row.
+	JSONColumnar Format = "json-columnar"
+	// CSV encodes the rows as CSV with a header.
+	CSV Format = "csv"
+	// Markdown encodes the rows as a Markdown table.
+	Markdown Format = "markdown"
+)
+
+// Header is the header requesting a format for an invocation over the REST
+// API.
+const Header = "Toolbox-Result-Format"
+
+// MetaKey is the key of the `_meta` of MCP tools/call requests requesting a
+// format for an invocation.
+const MetaKey = "toolbox/resultFormat"
+
+// Enum returns the values allowed for a Format.
+func (Format) Enum() []string {
+	return []string{string(JSONRows), string(JSONColumnar), string(CSV), string(Markdown)}
+}
+
+func (f *Format) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return fmt.Errorf("error unmarshalling result format: %s", err)
+	}
+	format, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// Parse returns the Format named s. An empty s returns an empty Format.
+func Parse(s string) (Format, error) {
+	if s != "" && !slices.Contains(Format("").Enum(), s) {
+		return "", fmt.Errorf("%q is not a valid result format, must be one of %q", s, Format("").Enum())
+	}
+	return Format(s), nil
+}
+
+// Resolve returns the format of an invocation of t: the requested format if
+// there is one, or else the format configured for t.
+func Resolve(requested string, t tools.Tool) (Format, error) {
+	f, err := Parse(requested)
+	if err != nil || f != "" {
+		return f, err
+	}
+	return Of(t), nil
+}
+
+// Of returns the format configured for t, which may be wrapped by tools
+// embedding it, or an empty Format.
+func Of(t tools.Tool) Format {
+	for t != nil {
+		if ft, ok := t.(Tool); ok {
+			return ft.format
+		}
+		u, ok := t.(interface{ Unwrap() tools.Tool })
+		if !ok {
+			break
+		}
+		t = u.Unwrap()
+	}
+	return ""
+}
+
+// Encode returns the rows of res encoded in format f, with the columns in
+// order, which is the order of the columns of the statement recorded by the
+// tool if known. It reports false if f is empty or JSONRows, or if res is not
+// a list of rows, in which case res is encoded as JSON by the caller.
+func Encode(res any, f Format, order []string) (string, bool, error) {
+	if f == "" || f == JSONRows {
+		return "", false, nil
+	}
+	rows, ok := asRows(res)
+	if !ok {
+		return "", false, nil
+	}
+	cols := columns(rows, order)
+	var s string
+	var err error
+	switch f {
+	case JSONColumnar:
+		s, err = encodeColumnar(cols, rows)
+	case CSV:
+		s, err = encodeCSV(cols, rows)
+	case Markdown:
+		s = encodeMarkdown(cols, rows)
+	default:
+		return "", false, fmt.Errorf("unsupported result format %q", f)
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("unable to encode result as %s: %w", f, err)
+	}
+	return s, true, nil
+}
+
+// asRows returns res as a list of rows, which are maps of column names to
+// values.
+func asRows(res any) ([]map[string]any, bool) {
+	switch r := res.(type) {
+	case []map[string]any:
+		return r, true
+	case []any:
+		rows := make([]map[string]any, len(r))
+		for i, v := range r {
+			row, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			rows[i] = row
+		}
+		return rows, true
+	default:
+		return nil, false
+	}
+}
+
+// columns returns the names of the columns of rows in order. Rows are maps,
+// which do not preserve the order of the columns of the statement, so columns
+// missing from order, such as when it is unknown, follow sorted by name.
+// Columns of order missing from rows, such as columns dropped by a data
+// policy, are left out.
+func columns(rows []map[string]any, order []string) []string {
+	set := make(map[string]struct{})
+	for _, row := range rows {
+		for k := range row {
+			set[k] = struct{}{}
+		}
+	}
+	cols := make([]string, 0, len(set))
+	for _, c := range order {
+		if _, ok := set[c]; ok {
+			cols = append(cols, c)
+			delete(set, c)
+		}
+	}
+	return append(cols, slices.Sorted(maps.Keys(set))...)
+}
+
+func encodeColumnar(cols []string, rows []map[string]any) (string, error) {
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		values[i] = make([]any, len(cols))
+		for j, c := range cols {
+			values[i][j] = JSONValue(row[c])
+		}
+	}
+	b, err := json.Marshal(map[string]any{"columns": cols, "rows": values})
+	return string(b), err
+}
+
+func encodeCSV(cols []string, rows []map[string]any) (string, error) {
+	if len(cols) == 0 {
+		return "", nil
+	}
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	if err := w.Write(cols); err != nil {
+		return "", err
+	}
+	record := make([]string, len(cols))
+	for _, row := range rows {
+		for j, c := range cols {
+			record[j] = TextValue(row[c])
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func encodeMarkdown(cols []string, rows []map[string]any) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, c := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(markdownEscaper.Replace(c))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(cols)
+	sep := make([]string, len(cols))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+	cells := make([]string, len(cols))
+	for _, row := range rows {
+		for j, c := range cols {
+			cells[j] = TextValue(row[c])
+		}
+		writeRow(cells)
+	}
+	return sb.String()
+}
+
+// JSONValue returns v as a value encoded to JSON without losing precision.
+func JSONValue(v any) any {
+	switch v := v.(type) {
+	case *big.Rat:
+		if v == nil {
+			return nil
+		}
+		return tools.DecimalString(v)
+	default:
+		return v
+	}
+}
+
+// TextValue returns the text of v in a CSV or Markdown cell. NULL values are
+// empty, binary values are encoded in base64, and timestamps in RFC 3339.
+func TextValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *big.Rat:
+		if v == nil {
+			return ""
+		}
+		return tools.DecimalString(v)
+	case json.Number:
+		return v.String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// ToolConfig wraps the config of a tool with the format of its results.
+type ToolConfig struct {
+	tools.ToolConfig
+	Format Format
+}
+
+var _ tools.ComposedToolConfig = ToolConfig{}
+
+// Unwrap returns the config of the wrapped tool.
+func (cfg ToolConfig) Unwrap() tools.ToolConfig { return cfg.ToolConfig }
+
+// ToolNames returns the tools invoked by the wrapped tool.
+func (cfg ToolConfig) ToolNames() []string {
+	return tools.WrappedToolNames(cfg.ToolConfig)
+}
+
+func (cfg ToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return cfg.InitializeWithTools(srcs, nil)
+}
+
+func (cfg ToolConfig) InitializeWithTools(srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	t, err := tools.InitializeWrapped(cfg.ToolConfig, srcs, toolsMap)
+	if err != nil {
+		return nil, err
+	}
+	return Tool{Tool: t, format: cfg.Format}, nil
+}
+
+// Tool is a tool whose results are encoded in a format by default.
+type Tool struct {
+	tools.Tool
+	format Format
+}
+
+var _ tools.Tool = Tool{}
+
+// Unwrap returns the wrapped tool.
+func (t Tool) Unwrap() tools.Tool { return t.Tool }
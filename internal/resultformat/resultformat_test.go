@@ -0,0 +1,244 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultformat_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/cache"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+)
+
+func TestParseFromYamlResultFormat(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT * FROM t;
+			resultFormat: csv
+	`
+	want := server.ToolConfigs{
+		"example_tool": resultformat.ToolConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "example_tool",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT * FROM t;",
+				AuthRequired: []string{},
+			},
+			Format: resultformat.CSV,
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+
+	in = strings.Replace(in, "resultFormat: csv", "resultFormat: xml", 1)
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil || !strings.Contains(err.Error(), `"xml" is not a valid result format`) {
+		t.Fatalf("expected error for invalid format, got %v", err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 5, 6, 7, 800000000, time.UTC)
+	rows := []any{
+		map[string]any{"id": int64(1), "price": big.NewRat(12345, 100), "note": "a|b\nc", "at": ts},
+		map[string]any{"id": int64(2), "price": big.NewRat(1, 3), "note": nil, "data": []byte("hi")},
+	}
+	tcs := []struct {
+		format resultformat.Format
+		want   string
+	}{
+		{
+			format: resultformat.JSONColumnar,
+			want: `{"columns":["at","data","id","note","price"],"rows":[` +
+				`["2025-03-04T05:06:07.8Z",null,1,"a|b\nc","123.45"],` +
+				`[null,"aGk=",2,null,"0.3333333333333333333333333333333333"]]}`,
+		},
+		{
+			format: resultformat.CSV,
+			want: "at,data,id,note,price\n" +
+				"2025-03-04T05:06:07.8Z,,1,\"a|b\nc\",123.45\n" +
+				",aGk=,2,,0.3333333333333333333333333333333333\n",
+		},
+		{
+			format: resultformat.Markdown,
+			want: "| at | data | id | note | price |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| 2025-03-04T05:06:07.8Z |  | 1 | a\\|b<br>c | 123.45 |\n" +
+				"|  | aGk= | 2 |  | 0.3333333333333333333333333333333333 |\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(string(tc.format), func(t *testing.T) {
+			got, ok, err := resultformat.Encode(rows, tc.format, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !ok {
+				t.Fatalf("expected rows to be encoded")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("incorrect encoding: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeColumnOrder(t *testing.T) {
+	rows := []any{
+		map[string]any{"name": "a", "id": 1, "extra": true},
+		map[string]any{"name": "b", "id": 2, "extra": false},
+	}
+	tcs := []struct {
+		desc  string
+		order []string
+		want  string
+	}{
+		{
+			desc:  "order of the statement",
+			order: []string{"name", "id", "extra"},
+			want:  "name,id,extra\na,1,true\nb,2,false\n",
+		},
+		{
+			desc: "unknown order",
+			want: "extra,id,name\ntrue,1,a\nfalse,2,b\n",
+		},
+		{
+			desc:  "dropped and unknown columns",
+			order: []string{"secret", "name", "id"},
+			want:  "name,id,extra\na,1,true\nb,2,false\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, _, err := resultformat.Encode(rows, resultformat.CSV, tc.order)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("incorrect encoding: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeDecimals(t *testing.T) {
+	tcs := []struct {
+		in   *big.Rat
+		want string
+	}{
+		{in: big.NewRat(5, 1), want: "5"},
+		{in: big.NewRat(-1, 8), want: "-0.125"},
+		{in: big.NewRat(1234567, 1000), want: "1234.567"},
+		{in: big.NewRat(1, 10000000), want: "0.0000001"},
+	}
+	for _, tc := range tcs {
+		got, _, err := resultformat.Encode([]any{map[string]any{"v": tc.in}}, resultformat.CSV, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := "v\n" + tc.want + "\n"; got != want {
+			t.Errorf("incorrect encoding of %s: got %q, want %q", tc.in, got, want)
+		}
+	}
+}
+
+func TestEncodeNotRows(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		res    any
+		format resultformat.Format
+	}{
+		{desc: "json-rows", res: []any{map[string]any{"a": 1}}, format: resultformat.JSONRows},
+		{desc: "no format", res: []any{map[string]any{"a": 1}}},
+		{desc: "list of scalars", res: []any{"a", "b"}, format: resultformat.CSV},
+		{desc: "object", res: map[string]any{"a": 1}, format: resultformat.Markdown},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, ok, err := resultformat.Encode(tc.res, tc.format, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if ok {
+				t.Errorf("expected result to be left to the caller")
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	formatted, err := resultformat.ToolConfig{ToolConfig: toolstest.Config{Result: []any{}}, Format: resultformat.Markdown}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	// the format is found through other wrappers
+	cached, err := cache.ToolConfig{ToolConfig: resultformat.ToolConfig{ToolConfig: toolstest.Config{Result: []any{}}, Format: resultformat.Markdown}, Name: "t"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	plain, _ := toolstest.Config{Result: []any{}}.Initialize(nil)
+
+	tcs := []struct {
+		desc      string
+		requested string
+		tool      tools.Tool
+		want      resultformat.Format
+	}{
+		{desc: "format of the tool", tool: formatted, want: resultformat.Markdown},
+		{desc: "format of a wrapped tool", tool: cached, want: resultformat.Markdown},
+		{desc: "requested format", requested: "csv", tool: formatted, want: resultformat.CSV},
+		{desc: "no format", tool: plain, want: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := resultformat.Resolve(tc.requested, tc.tool)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("incorrect format: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+	if _, err := resultformat.Resolve("yaml", plain); err == nil {
+		t.Errorf("expected error for invalid format")
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema generates a JSON Schema of the tools file from the
+// configuration types of the registered source and tool kinds.
+package schema
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/cache"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/export"
+	"github.com/googleapis/genai-toolbox/internal/policy"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// Schema is a JSON Schema document.
+type Schema map[string]any
+
+const (
+	draft = "http://json-schema.org/draft-07/schema#"
+
+	defSource       = "source"
+	defAuthService  = "authService"
+	defTool         = "tool"
+	defParameter    = "parameter"
+	defCache        = "cache"
+	defConfirmation = "requireConfirmation"
+	defPolicy       = "dataPolicy"
+	defExport       = "export"
+	defModel        = "embeddingModel"
+	defPlaceholder  = "placeholder"
+	defParameterSet = "parameterSetReference"
+)
+
+// Enumer is implemented by string types that only allow a fixed set of
+// values, such as sources.Dialect.
+type Enumer interface {
+	Enum() []string
+}
+
+var (
+	enumerType     = reflect.TypeFor[Enumer]()
+	parametersType = reflect.TypeFor[tools.Parameters]()
+	parameterType  = reflect.TypeFor[tools.Parameter]()
+	modelType      = reflect.TypeFor[embeddingmodels.Config]()
+)
+
+// Generate returns the JSON Schema of the tools file, with a definition for
+// each registered source and tool kind.
+func Generate(ctx context.Context) (Schema, error) {
+	defs := Schema{
+		defPlaceholder: Schema{
+			"type":        "string",
+			"pattern":     `^\$\{[^}]+\}$`,
+			"description": "An environment variable or secret reference, replaced before the file is parsed.",
+		},
+		defCache:  objectSchema(reflect.TypeFor[cache.Config]()),
+		defPolicy: objectSchema(reflect.TypeFor[policy.Config]()),
+		defExport: objectSchema(reflect.TypeFor[export.Config]()),
+		defConfirmation: Schema{"anyOf": []any{
+			Schema{"type": "boolean"},
+			objectSchema(reflect.TypeFor[confirmation.Config]()),
+		}},
+		defParameterSet: Schema{
+			"type":                 "object",
+			"required":             []string{"parameterSet"},
+			"properties":           Schema{"parameterSet": Schema{"type": "string"}},
+			"additionalProperties": false,
+		},
+	}
+
+	var sourceKinds []any
+	for _, kind := range sources.Kinds() {
+		cfg, err := sources.DecodeConfig(ctx, kind, "", emptyDecoder())
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate schema of source kind %q: %w", kind, err)
+		}
+		s := kindSchema(reflect.TypeOf(cfg), kind)
+		// `dataPolicy` is supported by every kind of source.
+		s["properties"].(Schema)["dataPolicy"] = ref(defPolicy)
+		defs[kindDef(defSource, kind)] = s
+		sourceKinds = append(sourceKinds, kind)
+	}
+	defs[defSource] = dispatchSchema("kind", defSource, sourceKinds)
+
+	authKinds := []any{google.AuthServiceKind}
+	defs[kindDef(defAuthService, google.AuthServiceKind)] = kindSchema(reflect.TypeFor[google.Config](), google.AuthServiceKind)
+	defs[defAuthService] = dispatchSchema("kind", defAuthService, authKinds)
+
+	// Embedding models are configured by the tools embedding their queries.
+	var modelKinds []any
+	for _, kind := range embeddingmodels.Kinds() {
+		cfg, err := embeddingmodels.DecodeConfig(ctx, kind, emptyDecoder())
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate schema of embedding model kind %q: %w", kind, err)
+		}
+		defs[kindDef(defModel, kind)] = kindSchema(reflect.TypeOf(cfg), kind)
+		modelKinds = append(modelKinds, kind)
+	}
+	defs[defModel] = dispatchSchema("kind", defModel, modelKinds)
+
+	var toolKinds []any
+	for _, kind := range tools.Kinds() {
+		cfg, err := tools.DecodeConfig(ctx, kind, "", emptyDecoder())
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate schema of tool kind %q: %w", kind, err)
+		}
+		s := kindSchema(reflect.TypeOf(cfg), kind)
+		// `cache`, `requireConfirmation`, `dataPolicy`, `resultFormat` and
+		// `export` are supported by every kind of tool.
+		s["properties"].(Schema)["cache"] = ref(defCache)
+		s["properties"].(Schema)["requireConfirmation"] = ref(defConfirmation)
+		s["properties"].(Schema)["dataPolicy"] = ref(defPolicy)
+		s["properties"].(Schema)["resultFormat"] = typeSchema(reflect.TypeFor[resultformat.Format]())
+		s["properties"].(Schema)["export"] = ref(defExport)
+		// so is `timeout`, except by kinds with a `timeout` of their own
+		if c, ok := cfg.(tools.TimeoutConfig); !ok || !c.OwnsTimeout() {
+			s["properties"].(Schema)["timeout"] = Schema{"type": "string"}
+		}
+		defs[kindDef(defTool, kind)] = s
+		toolKinds = append(toolKinds, kind)
+	}
+	// Tools extending a template or tool may omit any field, including the
+	// kind, so they are only checked once resolved, while parsing.
+	defs[defTool] = Schema{
+		"if":   Schema{"required": []string{"extends"}},
+		"then": Schema{"type": "object", "properties": Schema{"extends": Schema{"type": "string"}}},
+		"else": dispatchSchema("kind", defTool, toolKinds),
+	}
+
+	paramTypes := tools.ParameterTypes()
+	var paramTypeNames []any
+	for _, name := range slices.Sorted(maps.Keys(paramTypes)) {
+		s := objectSchema(reflect.TypeOf(paramTypes[name]))
+		s["properties"].(Schema)["type"] = Schema{"const": name}
+		defs[kindDef(defParameter, name)] = s
+		paramTypeNames = append(paramTypeNames, name)
+	}
+	defs[defParameter] = dispatchSchema("type", defParameter, paramTypeNames)
+
+	mapOf := func(def string) Schema {
+		return Schema{"type": "object", "additionalProperties": ref(def)}
+	}
+	return Schema{
+		"$schema":              draft,
+		"title":                "Toolbox tools file",
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": Schema{
+			"sources":      mapOf(defSource),
+			"authServices": mapOf(defAuthService),
+			"authSources":  mapOf(defAuthService),
+			"tools":        mapOf(defTool),
+			"templates": Schema{
+				"type":                 "object",
+				"additionalProperties": Schema{"type": "object", "properties": Schema{"extends": Schema{"type": "string"}}},
+			},
+			"parameterSets": Schema{
+				"type":                 "object",
+				"additionalProperties": typeSchema(parametersType),
+			},
+			"toolsets": Schema{
+				"type":                 "object",
+				"additionalProperties": Schema{"type": "array", "items": Schema{"type": "string"}},
+			},
+		},
+		"definitions": defs,
+	}, nil
+}
+
+// emptyDecoder returns a decoder of an empty document, which factories decode
+// into the zero configuration of their kind.
+func emptyDecoder() *yaml.Decoder {
+	return yaml.NewDecoder(strings.NewReader("{}"))
+}
+
+func ref(def string) Schema {
+	return Schema{"$ref": "#/definitions/" + def}
+}
+
+// kindDef returns the name of the definition of a kind of prefix. Definition
+// names must not contain "/", which separates the segments of a $ref.
+func kindDef(prefix, kind string) string {
+	return prefix + "." + kind
+}
+
+// dispatchSchema returns the schema of an object whose field selects the
+// definition of the kind of prefix it must match.
+func dispatchSchema(field, prefix string, values []any) Schema {
+	var cases []any
+	for _, v := range values {
+		cases = append(cases, Schema{
+			"if":   Schema{"properties": Schema{field: Schema{"const": v}}},
+			"then": ref(kindDef(prefix, fmt.Sprint(v))),
+		})
+	}
+	return Schema{
+		"type":       "object",
+		"required":   []string{field},
+		"properties": Schema{field: Schema{"enum": values}},
+		"allOf":      cases,
+	}
+}
+
+// kindSchema returns the schema of the configuration type t of kind.
+func kindSchema(t reflect.Type, kind string) Schema {
+	s := objectSchema(t)
+	s["properties"].(Schema)["kind"] = Schema{"const": kind}
+	return s
+}
+
+// objectSchema returns the schema of a struct type, whose properties are its
+// fields named after their yaml tags. Fields with a `validate:"required"` tag
+// are required, except for the name, which is the key of the configuration.
+func objectSchema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	props := Schema{}
+	var required []string
+	addFields(t, props, &required)
+	s := Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func addFields(t reflect.Type, props Schema, required *[]string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") || (f.Anonymous && name == "") {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(ft, props, required)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		props[name] = typeSchema(f.Type)
+		if name != "name" && slices.Contains(strings.Split(f.Tag.Get("validate"), ","), "required") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// typeSchema returns the schema of the values of a field of type t.
+func typeSchema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == parametersType:
+		return Schema{"type": "array", "items": Schema{"anyOf": []any{ref(defParameter), ref(defParameterSet)}}}
+	case t == parameterType:
+		return ref(defParameter)
+	case t == modelType:
+		return ref(defModel)
+	case t.Implements(enumerType):
+		values := reflect.Zero(t).Interface().(Enumer).Enum()
+		return orPlaceholder(Schema{"type": "string", "enum": values})
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return orPlaceholder(Schema{"type": "boolean"})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return orPlaceholder(Schema{"type": "integer"})
+	case reflect.Float32, reflect.Float64:
+		return orPlaceholder(Schema{"type": "number"})
+	case reflect.Slice, reflect.Array:
+		return orPlaceholder(Schema{"type": "array", "items": typeSchema(t.Elem())})
+	case reflect.Map:
+		return orPlaceholder(Schema{"type": "object", "additionalProperties": typeSchema(t.Elem())})
+	case reflect.Struct:
+		return objectSchema(t)
+	default:
+		// Interfaces accept any value.
+		return Schema{}
+	}
+}
+
+// orPlaceholder allows a placeholder in place of a value that is not a
+// string, since placeholders are replaced before the tools file is parsed.
+func orPlaceholder(s Schema) Schema {
+	return Schema{"anyOf": []any{s, ref(defPlaceholder)}}
+}
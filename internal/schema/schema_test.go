@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	_ "github.com/googleapis/genai-toolbox/internal/embeddingmodels/fake"
+	"github.com/googleapis/genai-toolbox/internal/schema"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/spanner"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/http"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+)
+
+func TestGenerate(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s, err := schema.Generate(ctx)
+	if err != nil {
+		t.Fatalf("unable to generate schema: %s", err)
+	}
+	// Compare the encoded schema, as a client would read it.
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("unable to encode schema: %s", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unable to decode schema: %s", err)
+	}
+	defs := got["definitions"].(map[string]any)
+	placeholder := map[string]any{"$ref": "#/definitions/placeholder"}
+
+	tcs := []struct {
+		desc string
+		path []string
+		want any
+	}{
+		{
+			desc: "tool kinds",
+			path: []string{"tool", "else", "properties", "kind", "enum"},
+			want: []any{"http", "postgres-sql"},
+		},
+		{
+			desc: "tool dispatch",
+			path: []string{"tool", "else", "allOf"},
+			want: []any{
+				map[string]any{
+					"if":   map[string]any{"properties": map[string]any{"kind": map[string]any{"const": "http"}}},
+					"then": map[string]any{"$ref": "#/definitions/tool.http"},
+				},
+				map[string]any{
+					"if":   map[string]any{"properties": map[string]any{"kind": map[string]any{"const": "postgres-sql"}}},
+					"then": map[string]any{"$ref": "#/definitions/tool.postgres-sql"},
+				},
+			},
+		},
+		{
+			desc: "required fields without name",
+			path: []string{"tool.postgres-sql", "required"},
+			want: []any{"kind", "source", "description", "statement"},
+		},
+		{
+			desc: "kind",
+			path: []string{"source.postgres", "properties", "kind"},
+			want: map[string]any{"const": "postgres"},
+		},
+		{
+			desc: "dialect enum",
+			path: []string{"source.spanner", "properties", "dialect"},
+			want: map[string]any{"anyOf": []any{
+				map[string]any{"type": "string", "enum": []any{"googlesql", "postgresql"}},
+				placeholder,
+			}},
+		},
+		{
+			desc: "http method enum",
+			path: []string{"tool.http", "properties", "method", "anyOf"},
+			want: []any{
+				map[string]any{"type": "string", "enum": []any{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "TRACE", "CONNECT"}},
+				placeholder,
+			},
+		},
+		{
+			desc: "parameters",
+			path: []string{"tool.postgres-sql", "properties", "parameters"},
+			want: map[string]any{"type": "array", "items": map[string]any{"anyOf": []any{
+				map[string]any{"$ref": "#/definitions/parameter"},
+				map[string]any{"$ref": "#/definitions/parameterSetReference"},
+			}}},
+		},
+		{
+			desc: "tools extending a template",
+			path: []string{"tool", "if"},
+			want: map[string]any{"required": []any{"extends"}},
+		},
+		{
+			desc: "cache",
+			path: []string{"tool.http", "properties", "cache"},
+			want: map[string]any{"$ref": "#/definitions/cache"},
+		},
+		{
+			desc: "dataPolicy of tools",
+			path: []string{"tool.postgres-sql", "properties", "dataPolicy"},
+			want: map[string]any{"$ref": "#/definitions/dataPolicy"},
+		},
+		{
+			desc: "dataPolicy of sources",
+			path: []string{"source.spanner", "properties", "dataPolicy"},
+			want: map[string]any{"$ref": "#/definitions/dataPolicy"},
+		},
+		{
+			desc: "resultFormat",
+			path: []string{"tool.http", "properties", "resultFormat", "anyOf"},
+			want: []any{
+				map[string]any{"type": "string", "enum": []any{"json-rows", "json-columnar", "csv", "markdown"}},
+				map[string]any{"$ref": "#/definitions/placeholder"},
+			},
+		},
+		{
+			desc: "export",
+			path: []string{"tool.postgres-sql", "properties", "export"},
+			want: map[string]any{"$ref": "#/definitions/export"},
+		},
+		{
+			desc: "timeout",
+			path: []string{"tool.postgres-sql", "properties", "timeout"},
+			want: map[string]any{"type": "string"},
+		},
+		{
+			desc: "embedding model kinds",
+			path: []string{"embeddingModel", "properties", "kind", "enum"},
+			want: []any{"fake"},
+		},
+		{
+			desc: "embedding model fields",
+			path: []string{"embeddingModel.fake", "required"},
+			want: []any{"kind", "dimension"},
+		},
+		{
+			desc: "export definition",
+			path: []string{"export", "required"},
+			want: []any{"destination"},
+		},
+		{
+			desc: "requireConfirmation",
+			path: []string{"requireConfirmation", "anyOf"},
+			want: []any{
+				map[string]any{"type": "boolean"},
+				map[string]any{
+					"type":                 "object",
+					"additionalProperties": false,
+					"properties": map[string]any{
+						"message": map[string]any{"type": "string"},
+						"preview": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+		{
+			desc: "parameter types",
+			path: []string{"parameter", "properties", "type", "enum"},
+			want: []any{"array", "boolean", "float", "integer", "map", "string"},
+		},
+		{
+			desc: "inline parameter fields",
+			path: []string{"parameter.array", "required"},
+			want: []any{"type", "description"},
+		},
+		{
+			desc: "array items",
+			path: []string{"parameter.array", "properties", "items"},
+			want: map[string]any{"$ref": "#/definitions/parameter"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			var v any = defs
+			for _, p := range tc.path {
+				m, ok := v.(map[string]any)
+				if !ok {
+					t.Fatalf("%v is not an object", v)
+				}
+				v = m[p]
+			}
+			if diff := cmp.Diff(tc.want, v); diff != "" {
+				t.Errorf("unexpected schema (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
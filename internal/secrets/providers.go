@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	FileScheme      = "file"
+	GCPSecretScheme = "gcp-secret"
+	VaultScheme     = "vault"
+
+	defaultSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+	cloudPlatformScope           = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+func init() {
+	for scheme, p := range map[string]Provider{
+		FileScheme:      FileProvider{},
+		GCPSecretScheme: &GCPSecretProvider{},
+		VaultScheme:     &VaultProvider{},
+	} {
+		if !Register(scheme, p) {
+			panic(fmt.Sprintf("secret scheme %q already registered", scheme))
+		}
+	}
+}
+
+var _ Provider = FileProvider{}
+
+// FileProvider reads secrets from files, e.g. ${file:/run/secrets/hana_pw}.
+// A single trailing newline is removed.
+type FileProvider struct{}
+
+func (FileProvider) Resolve(_ context.Context, ref string) (string, error) {
+	b, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimSuffix(string(b), "\n")
+	return strings.TrimSuffix(s, "\r"), nil
+}
+
+var _ Provider = &GCPSecretProvider{}
+
+// GCPSecretProvider accesses secret versions in Google Cloud Secret Manager
+// using Application Default Credentials, e.g.
+// ${gcp-secret:projects/p/secrets/s/versions/latest}.
+type GCPSecretProvider struct {
+	// Endpoint defaults to the Secret Manager API.
+	Endpoint string
+	// Client defaults to a client using Application Default Credentials.
+	Client *http.Client
+}
+
+func (p *GCPSecretProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	if !strings.HasPrefix(ref, "projects/") || !strings.Contains(ref, "/secrets/") {
+		return "", fmt.Errorf("reference must be of the form projects/<project>/secrets/<secret>[/versions/<version>]")
+	}
+	if !strings.Contains(ref, "/versions/") {
+		ref += "/versions/latest"
+	}
+	client := p.Client
+	if client == nil {
+		var err error
+		client, err = google.DefaultClient(ctx, cloudPlatformScope)
+		if err != nil {
+			return "", fmt.Errorf("unable to find default credentials: %w", err)
+		}
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = defaultSecretManagerEndpoint
+	}
+
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := getJSON(ctx, client, endpoint+"/v1/"+ref+":access", nil, &resp); err != nil {
+		return "", err
+	}
+	b, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode secret payload: %w", err)
+	}
+	return string(b), nil
+}
+
+var _ Provider = &VaultProvider{}
+
+// VaultProvider reads a field of a HashiCorp Vault secret, e.g.
+// ${vault:kv/data/hana#password}. Both KV version 1 and 2 secrets engines
+// are supported. The address and token default to the VAULT_ADDR and
+// VAULT_TOKEN environment variables, falling back to ~/.vault-token.
+type VaultProvider struct {
+	Address string
+	Token   string
+	Client  *http.Client
+}
+
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("reference must be of the form <path>#<field>")
+	}
+	addr := p.Address
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if addr == "" {
+		return "", fmt.Errorf("vault address not set: set the VAULT_ADDR environment variable")
+	}
+	token, err := p.token()
+	if err != nil {
+		return "", err
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	header := http.Header{"X-Vault-Token": {token}}
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		header.Set("X-Vault-Namespace", ns)
+	}
+
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	if err := getJSON(ctx, client, url, header, &resp); err != nil {
+		return "", err
+	}
+	data := resp.Data
+	// KV version 2 nests the secret in a second data object
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", field)
+	}
+	s, ok := v.(string)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal field %q: %w", field, err)
+		}
+		s = string(b)
+	}
+	return s, nil
+}
+
+func (p *VaultProvider) token() (string, error) {
+	if p.Token != "" {
+		return p.Token, nil
+	}
+	if t := os.Getenv("VAULT_TOKEN"); t != "" {
+		return t, nil
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if b, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			return strings.TrimSpace(string(b)), nil
+		}
+	}
+	return "", fmt.Errorf("vault token not set: set the VAULT_TOKEN environment variable")
+}
+
+// getJSON sends a GET request and decodes the JSON response into v.
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unable to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves secret references such as ${file:/path} in tools
+// files.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/token"
+)
+
+// Provider resolves references of a single scheme to secret values.
+type Provider interface {
+	// Resolve returns the secret identified by ref, which is the part of a
+	// ${scheme:ref} reference following the scheme.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Provider)
+)
+
+// Register registers a Provider for a scheme. It returns false if a provider
+// is already registered for the scheme.
+func Register(scheme string, p Provider) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[scheme]; exists {
+		return false
+	}
+	registry[scheme] = p
+	return true
+}
+
+// Replace registers p for scheme, replacing any registered provider, and
+// returns a function restoring the previous one. It is intended for tests.
+func Replace(scheme string, p Provider) (restore func()) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	prev, existed := registry[scheme]
+	registry[scheme] = p
+	return func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		if existed {
+			registry[scheme] = prev
+		} else {
+			delete(registry, scheme)
+		}
+	}
+}
+
+func lookup(scheme string) (Provider, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p, ok := registry[scheme]
+	return p, ok
+}
+
+// IsScheme reports whether a Provider is registered for scheme, so that
+// ${scheme:ref} is a secret reference.
+func IsScheme(scheme string) bool {
+	_, ok := lookup(scheme)
+	return ok
+}
+
+var referenceRe = regexp.MustCompile(`\$\{([a-z][a-z0-9-]*):([^}]+)\}`)
+
+// Placeholder replaces the secret references of tools files that are only
+// validated, which must not read secrets. It decodes as a string or a number.
+const Placeholder = "0"
+
+type placeholdersKey struct{}
+
+// WithPlaceholders returns a context in which Resolve replaces secret
+// references with Placeholder, without calling their Provider.
+func WithPlaceholders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, placeholdersKey{}, true)
+}
+
+func placeholders(ctx context.Context) bool {
+	v, _ := ctx.Value(placeholdersKey{}).(bool)
+	return v
+}
+
+// Resolve replaces every ${scheme:ref} reference in input whose scheme has a
+// registered Provider with the secret it refers to, or with Placeholder in a
+// context returned by WithPlaceholders. Other references, such as environment
+// variables with a default value, are left unchanged.
+func Resolve(ctx context.Context, input string) (string, error) {
+	var err error
+	output := referenceRe.ReplaceAllStringFunc(input, func(match string) string {
+		if err != nil {
+			return match
+		}
+		parts := referenceRe.FindStringSubmatch(match)
+		p, ok := lookup(parts[1])
+		if !ok {
+			return match
+		}
+		if placeholders(ctx) {
+			return Placeholder
+		}
+		v, rErr := p.Resolve(ctx, parts[2])
+		if rErr != nil {
+			err = fmt.Errorf("unable to resolve secret %q: %w", match, rErr)
+			return match
+		}
+		return v
+	})
+	return output, err
+}
+
+// ResolveNode replaces the secret references of the string values of the
+// YAML node, as Resolve does. Mapping keys and comments are left unchanged,
+// and the values with a reference become double-quoted scalars, so that a
+// secret is never parsed as YAML.
+func ResolveNode(ctx context.Context, node ast.Node) error {
+	r := &nodeResolver{ctx: ctx}
+	ast.Walk(r, node)
+	return r.err
+}
+
+// nodeResolver is an ast.Visitor resolving the secret references of string
+// values.
+type nodeResolver struct {
+	ctx context.Context
+	err error
+}
+
+func (r *nodeResolver) Visit(node ast.Node) ast.Visitor {
+	if r.err != nil {
+		return nil
+	}
+	switch n := node.(type) {
+	case *ast.MappingValueNode:
+		ast.Walk(r, n.Value)
+		return nil
+	case *ast.StringNode:
+		if !referenceRe.MatchString(n.Value) {
+			return nil
+		}
+		v, err := Resolve(r.ctx, n.Value)
+		if err != nil {
+			r.err = fmt.Errorf("line %d: %w", n.Token.Position.Line, err)
+			return nil
+		}
+		if v != n.Value {
+			n.Value = v
+			n.Token.Type = token.DoubleQuoteType
+		}
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,360 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// sensitiveFields are substrings of the names of config fields whose values
+// are redacted by the admin API.
+var sensitiveFields = []string{"password", "secret", "token", "credential", "authorization", "apikey", "accesskey", "privatekey"}
+
+// adminRouter creates a router that represents the routes under /admin. Every
+// route requires the admin token as a bearer token.
+func adminRouter(s *Server) (chi.Router, error) {
+	if s.adminToken == "" {
+		return nil, fmt.Errorf("admin API requires an admin token")
+	}
+	r := chi.NewRouter()
+
+	r.Use(middleware.StripSlashes)
+	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(adminAuth(s.adminToken))
+
+	r.Get("/status", func(w http.ResponseWriter, r *http.Request) { adminStatusHandler(s, w, r) })
+	r.Get("/sources", func(w http.ResponseWriter, r *http.Request) { adminSourcesHandler(s, w, r) })
+	r.Get("/tools", func(w http.ResponseWriter, r *http.Request) { render.JSON(w, r, s.ResourceMgr.adminTools()) })
+	r.Get("/toolsets", func(w http.ResponseWriter, r *http.Request) { render.JSON(w, r, s.ResourceMgr.adminToolsets()) })
+	r.Post("/tools/{toolName}/disable", func(w http.ResponseWriter, r *http.Request) { adminToolStateHandler(s, w, r, false) })
+	r.Post("/tools/{toolName}/enable", func(w http.ResponseWriter, r *http.Request) { adminToolStateHandler(s, w, r, true) })
+	r.Post("/reload", func(w http.ResponseWriter, r *http.Request) { adminReloadHandler(s, w, r) })
+	r.Post("/drain", func(w http.ResponseWriter, r *http.Request) { adminDrainHandler(s, w, r) })
+
+	return r, nil
+}
+
+// adminAuth rejects requests without the admin token in their Authorization
+// header.
+func adminAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="toolbox-admin"`)
+				_ = render.Render(w, r, newErrResponse(fmt.Errorf("missing or invalid admin token"), http.StatusUnauthorized))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SetReloadFunc sets the function reloading the tools files when a reload is
+// requested through the admin API.
+func (s *Server) SetReloadFunc(f func(context.Context) error) {
+	s.reload = f
+}
+
+// adminStatus is the response of the admin status endpoint.
+type adminStatus struct {
+	Version       string           `json:"version"`
+	Draining      bool             `json:"draining"`
+	Sources       int              `json:"sources"`
+	Tools         int              `json:"tools"`
+	Toolsets      int              `json:"toolsets"`
+	DisabledTools []string         `json:"disabledTools"`
+	LastReload    *reloadReadiness `json:"lastReload,omitempty"`
+}
+
+// adminSource describes a loaded source. Sensitive fields of its config are
+// redacted.
+type adminSource struct {
+	Name   string          `json:"name"`
+	Kind   string          `json:"kind"`
+	Origin string          `json:"origin,omitempty"`
+	Health sourceReadiness `json:"health"`
+	Pool   *adminPoolStats `json:"pool,omitempty"`
+	Config map[string]any  `json:"config,omitempty"`
+}
+
+// adminPoolStats are the statistics of the connection pool of a source.
+type adminPoolStats struct {
+	MaxOpenConnections int     `json:"maxOpenConnections"`
+	OpenConnections    int     `json:"openConnections"`
+	InUse              int     `json:"inUse"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"waitCount"`
+	WaitDurationMs     float64 `json:"waitDurationMs"`
+}
+
+// adminTool describes a loaded tool.
+type adminTool struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Origin   string `json:"origin,omitempty"`
+	Disabled bool   `json:"disabled"`
+}
+
+// adminToolset describes a loaded toolset.
+type adminToolset struct {
+	Name   string   `json:"name"`
+	Tools  []string `json:"tools"`
+	Origin string   `json:"origin,omitempty"`
+}
+
+// adminDrain is the response of the admin drain endpoint.
+type adminDrain struct {
+	Status string `json:"status"`
+}
+
+// adminStatusHandler reports the version of the server, the number of
+// loaded resources and the outcome of the last reload.
+func adminStatusHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	rm := s.ResourceMgr
+	rm.mu.RLock()
+	res := adminStatus{
+		Version:       s.version,
+		Draining:      s.draining.Load(),
+		Sources:       len(rm.sources),
+		Tools:         len(rm.tools),
+		DisabledTools: slices.Sorted(maps.Keys(rm.disabled)),
+	}
+	for name := range rm.toolsets {
+		// the default toolset is not configured
+		if name != "" {
+			res.Toolsets++
+		}
+	}
+	rm.mu.RUnlock()
+	if last, ok := rm.LastReload(); ok {
+		res.LastReload = &reloadReadiness{Status: statusOK, ReloadStatus: last}
+		if last.Error != "" {
+			res.LastReload.Status = statusUnavailable
+		}
+	}
+	render.JSON(w, r, res)
+}
+
+// adminSourcesHandler describes the loaded sources, checking their health in
+// parallel.
+func adminSourcesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	rm := s.ResourceMgr
+	rm.mu.RLock()
+	current, configs, origins := rm.sources, rm.sourceConfigs, rm.origins
+	rm.mu.RUnlock()
+
+	names := slices.Sorted(maps.Keys(current))
+	res := make([]adminSource, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		src := current[name]
+		res[i] = adminSource{Name: name, Kind: src.SourceKind(), Origin: origins.Sources[name]}
+		if sc, ok := configs[name]; ok {
+			res[i].Config = redactedConfig(sc)
+		}
+		if p, ok := src.(telemetry.Pool); ok {
+			stats := p.DBStats()
+			res[i].Pool = &adminPoolStats{
+				MaxOpenConnections: stats.MaxOpenConnections,
+				OpenConnections:    stats.OpenConnections,
+				InUse:              stats.InUse,
+				Idle:               stats.Idle,
+				WaitCount:          stats.WaitCount,
+				WaitDurationMs:     float64(stats.WaitDuration.Microseconds()) / 1000,
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res[i].Health = s.checkSource(r.Context(), src)
+		}()
+	}
+	wg.Wait()
+	render.JSON(w, r, res)
+}
+
+// adminTools describes the loaded tools, including the disabled ones.
+func (r *ResourceManager) adminTools() []adminTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := make([]adminTool, 0, len(r.tools))
+	for _, name := range slices.Sorted(maps.Keys(r.tools)) {
+		t := adminTool{Name: name, Origin: r.origins.Tools[name], Disabled: r.disabled[name]}
+		if tc, ok := r.toolConfigs[name]; ok {
+			t.Kind = tc.ToolConfigKind()
+			t.Source = toolSourceName(tc)
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+// adminToolsets describes the configured toolsets.
+func (r *ResourceManager) adminToolsets() []adminToolset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := make([]adminToolset, 0, len(r.toolsets))
+	for _, name := range slices.Sorted(maps.Keys(r.toolsets)) {
+		// the default toolset is not configured
+		if name == "" {
+			continue
+		}
+		ts := adminToolset{Name: name, Tools: []string{}, Origin: r.origins.Toolsets[name]}
+		if tc, ok := r.toolsetConfigs[name]; ok {
+			ts.Tools = slices.Sorted(slices.Values(tc.ToolNames))
+		}
+		res = append(res, ts)
+	}
+	return res
+}
+
+// adminToolStateHandler enables or disables a tool.
+func adminToolStateHandler(s *Server, w http.ResponseWriter, r *http.Request, enabled bool) {
+	toolName := chi.URLParam(r, "toolName")
+	if !s.ResourceMgr.SetToolEnabled(toolName, enabled) {
+		err := fmt.Errorf("tool %q does not exist", toolName)
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	state := "enabled"
+	if !enabled {
+		state = "disabled"
+	}
+	s.logger.InfoContext(r.Context(), fmt.Sprintf("Tool %q %s through the admin API.", toolName, state))
+	render.JSON(w, r, adminTool{Name: toolName, Disabled: !enabled})
+}
+
+// adminReloadHandler reloads the tools files, and reports the outcome.
+func adminReloadHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	if s.reload == nil {
+		err := fmt.Errorf("reloading is not supported by this server")
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotImplemented))
+		return
+	}
+	s.logger.InfoContext(r.Context(), "Reloading tools files through the admin API.")
+	// the reload is not interrupted if the client disconnects
+	ctx := util.WithInstrumentation(util.WithLogger(context.WithoutCancel(r.Context()), s.logger), s.instrumentation)
+	err := s.reload(ctx)
+	res := reloadReadiness{Status: statusOK, ReloadStatus: ReloadStatus{Time: time.Now().UTC()}}
+	if last, ok := s.ResourceMgr.LastReload(); ok {
+		res.ReloadStatus = last
+	}
+	if err != nil {
+		res.Status = statusUnavailable
+		res.Error = err.Error()
+		render.Status(r, http.StatusInternalServerError)
+	}
+	render.JSON(w, r, res)
+}
+
+// adminDrainHandler stops the server from accepting tool invocations, and
+// waits for the invocations in flight to complete. The readiness endpoint
+// reports the server as unavailable from then on.
+func adminDrainHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	timeout := drainTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		var err error
+		if timeout, err = time.ParseDuration(v); err != nil {
+			err = fmt.Errorf("invalid timeout %q: %w", v, err)
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+	}
+	if !s.draining.Swap(true) {
+		s.logger.InfoContext(r.Context(), "Draining the server, tool invocations are no longer accepted.")
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	if err := s.ResourceMgr.waitIdle(ctx); err != nil {
+		render.Status(r, http.StatusAccepted)
+		render.JSON(w, r, adminDrain{Status: "draining"})
+		return
+	}
+	render.JSON(w, r, adminDrain{Status: "drained"})
+}
+
+// waitIdle blocks until the requests using the current resources have
+// completed, or ctx is done.
+func (r *ResourceManager) waitIdle(ctx context.Context) error {
+	r.mu.RLock()
+	g := r.current
+	r.mu.RUnlock()
+	return g.wait(ctx)
+}
+
+// redactedConfig returns the fields of a source config, with the values of
+// sensitive fields and the passwords of URLs redacted. It returns nil if the
+// config cannot be represented.
+func redactedConfig(sc sources.SourceConfig) map[string]any {
+	b, err := yaml.Marshal(sc)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	return redactValue("", m).(map[string]any)
+}
+
+func redactValue(name string, v any) any {
+	lower := strings.ToLower(name)
+	for _, f := range sensitiveFields {
+		if strings.Contains(lower, f) {
+			return audit.RedactedValue
+		}
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = redactValue(k, e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = redactValue(name, e)
+		}
+		return v
+	case string:
+		if u, err := url.Parse(v); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				return u.Redacted()
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
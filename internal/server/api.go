@@ -20,10 +20,16 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/timeout"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
@@ -136,12 +142,21 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/invoke")
 	r = r.WithContext(ctx)
 	ctx = util.WithLogger(r.Context(), s.logger)
+	ctx = util.WithInstrumentation(ctx, s.instrumentation)
+	ctx = audit.WithRequestInfo(audit.WithAuditor(ctx, s.auditor), audit.TransportREST, "")
 
 	toolName := chi.URLParam(r, "toolName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
 	span.SetAttributes(attribute.String("tool_name", toolName))
+	auditInv := audit.Begin(ctx, toolName)
+	start := time.Now()
 	var err error
+	// errType categorizes err, and rows and size describe successful results
+	var errType string
+	var rows, size int
 	defer func() {
+		auditInv.End(ctx, err)
+		s.instrumentation.RecordToolInvocation(r.Context(), toolName, time.Since(start), errType, rows, size)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 		}
@@ -159,9 +174,20 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
+	if s.draining.Load() {
+		err = fmt.Errorf("server is draining and no longer accepts tool invocations")
+		errType = telemetry.ErrorTypeUnavailable
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusServiceUnavailable))
+		return
+	}
+
+	// the sources used by the tool are kept open until the invocation completes
+	defer s.ResourceMgr.Acquire()()
 	tool, ok := s.ResourceMgr.GetTool(toolName)
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		errType = telemetry.ErrorTypeNotFound
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
@@ -175,6 +201,7 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	if tool.RequiresClientAuthorization() {
 		if accessToken == "" {
 			err = fmt.Errorf("tool requires client authorization but access token is missing from the request header")
+			errType = telemetry.ErrorTypeUnauthorized
 			s.logger.DebugContext(ctx, err.Error())
 			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 			return
@@ -197,6 +224,11 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		claimsFromAuth[aS.GetName()] = claims
 	}
 
+	auditInv.SetPrincipals(claimsFromAuth)
+	ctx = util.WithHeader(util.WithClaims(ctx, claimsFromAuth), r.Header)
+	// invocations are confirmed by repeating them with a confirmation token
+	ctx = confirmation.WithConfirmer(ctx, s.confirmations.Confirmer(r.Header.Get(confirmation.TokenHeader)))
+
 	// Tool authorization check
 	verifiedAuthServices := make([]string, len(claimsFromAuth))
 	i := 0
@@ -209,6 +241,7 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	isAuthorized := tool.Authorized(verifiedAuthServices)
 	if !isAuthorized {
 		err = fmt.Errorf("tool invocation not authorized. Please make sure your specify correct auth headers")
+		errType = telemetry.ErrorTypeUnauthorized
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 		return
@@ -219,6 +252,7 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	if err = util.DecodeJSON(r.Body, &data); err != nil {
 		render.Status(r, http.StatusBadRequest)
 		err = fmt.Errorf("request body was invalid JSON: %w", err)
+		errType = telemetry.ErrorTypeInvalidRequest
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
@@ -228,21 +262,48 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// If auth error, return 401
 		if errors.Is(err, tools.ErrUnauthorized) {
+			errType = telemetry.ErrorTypeUnauthorized
 			s.logger.DebugContext(ctx, fmt.Sprintf("error parsing authenticated parameters from ID token: %s", err))
 			_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
 			return
 		}
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		errType = telemetry.ErrorTypeInvalidParams
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
+	auditInv.SetParams(params)
+
+	format, err := resultformat.Resolve(r.Header.Get(resultformat.Header), tool)
+	if err != nil {
+		errType = telemetry.ErrorTypeInvalidRequest
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
 
+	// rows are encoded with the columns in the order of the statement
+	ctx, columns := tools.WithColumnOrder(ctx)
 	res, err := tool.Invoke(ctx, params, accessToken)
 
 	// Determine what error to return to the users.
 	if err != nil {
+		var required *confirmation.RequiredError
+		if errors.As(err, &required) {
+			errType = telemetry.ErrorTypeConfirmationRequired
+			s.logger.DebugContext(ctx, "tool invocation requires confirmation")
+			_ = render.Render(w, r, confirmationResponse{Message: required.Message, ConfirmationToken: required.Token})
+			return
+		}
+		var timedOut *timeout.Error
+		if errors.As(err, &timedOut) {
+			errType = telemetry.ErrorTypeTimeout
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusGatewayTimeout))
+			return
+		}
 		errStr := err.Error()
 		var statusCode int
 
@@ -255,6 +316,7 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		}
 
 		if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+			errType = telemetry.ErrorTypeUpstreamAuth
 			if tool.RequiresClientAuthorization() {
 				// Propagate the original 401/403 error.
 				s.logger.DebugContext(ctx, fmt.Sprintf("error invoking tool. Client credentials lack authorization to the source: %v", err))
@@ -267,28 +329,47 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			_ = render.Render(w, r, newErrResponse(internalErr, http.StatusInternalServerError))
 			return
 		}
+		errType = telemetry.InvokeErrorType(err)
 		err = fmt.Errorf("error while invoking tool: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
 
-	resMarshal, err := json.Marshal(res)
+	// Paged results are returned as the rows of the page, with the token for
+	// the next page alongside.
+	var nextPageToken string
+	if p, ok := res.(tools.PagedResult); ok {
+		res = p.Rows
+		nextPageToken = p.NextPageToken
+	}
+
+	// rows are encoded in the requested format, and other results as JSON
+	result, encoded, err := resultformat.Encode(res, format, columns.Columns())
+	if err == nil && !encoded {
+		var resMarshal []byte
+		resMarshal, err = json.Marshal(res)
+		result = string(resMarshal)
+	}
 	if err != nil {
 		err = fmt.Errorf("unable to marshal result: %w", err)
+		errType = telemetry.ErrorTypeInternal
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
 		return
 	}
 
-	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal)})
+	rows, size = tools.ResultRows(res), len(result)
+	auditInv.SetResult(rows, size)
+	_ = render.Render(w, r, &resultResponse{Result: result, NextPageToken: nextPageToken})
 }
 
 var _ render.Renderer = &resultResponse{} // Renderer interface for managing response payloads.
 
 // resultResponse is the response sent back when the tool was invocated successfully.
 type resultResponse struct {
-	Result string `json:"result"` // result of tool invocation
+	Result        string `json:"result"`                  // result of tool invocation
+	NextPageToken string `json:"nextPageToken,omitempty"` // token to retrieve the next page of a truncated result
 }
 
 // Render renders a single payload and respond to the client request.
@@ -297,6 +378,18 @@ func (rr resultResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// confirmationResponse is the response sent back when an invocation must be
+// confirmed, by repeating it with the token in the confirmation header.
+type confirmationResponse struct {
+	Message           string `json:"message"`
+	ConfirmationToken string `json:"confirmationToken"`
+}
+
+func (cr confirmationResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusPreconditionRequired)
+	return nil
+}
+
 var _ render.Renderer = &errResponse{} // Renderer interface for managing response payloads.
 
 // newErrResponse is a helper function initializing an ErrResponse
@@ -20,9 +20,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -295,3 +300,246 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestToolInvokeAudit(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool4}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	var buf bytes.Buffer
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, withAuditor(t, &buf))
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		toolName string
+		body     string
+	}{
+		{toolName: tool2.Name, body: `{"param1": 1, "param2": 2}`},
+		{toolName: tool4.Name, body: `{}`},
+	} {
+		_, _, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), strings.NewReader(tc.body), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+	}
+
+	entries := auditEntries(t, buf.Bytes())
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.Transport != audit.TransportREST || got.Tool != tool2.Name || got.Error != "" {
+		t.Errorf("unexpected audit entry: %+v", got)
+	}
+	wantParams := map[string]any{"param1": float64(1), "param2": audit.RedactedValue}
+	if !reflect.DeepEqual(got.Parameters, wantParams) {
+		t.Errorf("unexpected audit parameters: got %v, want %v", got.Parameters, wantParams)
+	}
+	// the size of the result is the size of the result string of the response
+	if got.ResultRows != 1 || got.ResultBytes != len(`["some_params"]`) {
+		t.Errorf("unexpected audit result size: got %d rows and %d bytes, want 1 row and %d bytes", got.ResultRows, got.ResultBytes, len(`["some_params"]`))
+	}
+	if entries[1].Tool != tool4.Name || entries[1].Error == "" {
+		t.Errorf("expected unauthorized invocation to be recorded with an error: %+v", entries[1])
+	}
+}
+
+func TestToolInvokePagedResult(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool6}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool6.Name), strings.NewReader(`{}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+	want := map[string]any{"result": `["row1","row2"]`, "nextPageToken": "next-page"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected response: got %v, want %v", got, want)
+	}
+}
+
+func TestToolInvokeResultFormat(t *testing.T) {
+	mockTools := []MockTool{tool1, tool7}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool7.Name] = withResultFormat(t, tool7, resultformat.CSV)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name   string
+		header map[string]string
+		want   string
+		status int
+	}{
+		{
+			name:   "format of the tool",
+			want:   "name,id\nAlice,1\nBob,2\n",
+			status: http.StatusOK,
+		},
+		{
+			name:   "requested format",
+			header: map[string]string{"Toolbox-Result-Format": "json-columnar"},
+			want:   `{"columns":["name","id"],"rows":[["Alice",1],["Bob",2]]}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "requested json-rows",
+			header: map[string]string{"Toolbox-Result-Format": "json-rows"},
+			want:   `[{"id":1,"name":"Alice"},{"id":2,"name":"Bob"}]`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "invalid format",
+			header: map[string]string{"Toolbox-Result-Format": "xml"},
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool7.Name), strings.NewReader(`{}`), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.status, body)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("error parsing response body: %s", err)
+			}
+			if got.Result != tc.want {
+				t.Errorf("unexpected result: got %q, want %q", got.Result, tc.want)
+			}
+		})
+	}
+}
+
+func TestToolInvokeConfirmation(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool2.Name] = requireConfirmation(t, tool2)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	path := fmt.Sprintf("/tool/%s/invoke", tool2.Name)
+	resp, body, err := runRequest(ts, http.MethodPost, path, strings.NewReader(`{"param1": 1, "param2": 2}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusPreconditionRequired {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusPreconditionRequired)
+	}
+	var got confirmationResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+	if got.Message != "Invoke some_params with param1=1?" || got.ConfirmationToken == "" {
+		t.Fatalf("unexpected confirmation response: %+v", got)
+	}
+
+	// the token only confirms the same parameters
+	header := map[string]string{"Toolbox-Confirmation-Token": got.ConfirmationToken}
+	resp, _, err = runRequest(ts, http.MethodPost, path, strings.NewReader(`{"param1": 3, "param2": 2}`), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusPreconditionRequired {
+		t.Errorf("unexpected status code for other parameters: got %d, want %d", resp.StatusCode, http.StatusPreconditionRequired)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodPost, path, strings.NewReader(`{"param1": 1, "param2": 2}`), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	var res resultResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatalf("error parsing response body: %s", err)
+	}
+	if res.Result != `["some_params"]` {
+		t.Errorf("unexpected result: got %s", res.Result)
+	}
+}
+
+func TestToolInvokeTimeout(t *testing.T) {
+	mockTools := []MockTool{tool1, tool8}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool8.Name), strings.NewReader(`{}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusGatewayTimeout)
+	}
+	if !strings.Contains(string(body), `tool \"timeout_tool\" timed out after 1s`) {
+		t.Errorf("unexpected response body: %s", body)
+	}
+}
+
+func TestToolInvokeMetrics(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool4, tool6}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	metadata := map[string]telemetry.ToolMetadata{
+		tool6.Name: {Kind: "mock-sql", Source: "my-source"},
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets, withPrometheus(t, metadata))
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		toolName string
+		body     string
+	}{
+		{toolName: tool6.Name, body: `{}`},
+		{toolName: tool2.Name, body: `{"param1": "not an integer"}`},
+		{toolName: tool4.Name, body: `{}`},
+		{toolName: "missing_tool", body: `{}`},
+	} {
+		_, _, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), strings.NewReader(tc.body), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	telemetry.PrometheusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	got := rec.Body.String()
+	for _, want := range []string{
+		`toolbox_name="paged_tool",toolbox_source_name="my-source",toolbox_tool_kind="mock-sql"} 1`,
+		`toolbox_name="paged_tool",toolbox_source_name="my-source",toolbox_tool_kind="mock-sql"} 2`,
+		`toolbox_server_tool_invoke_error_count_total{error_type="invalid_parameters"`,
+		`toolbox_server_tool_invoke_error_count_total{error_type="unauthorized"`,
+		`toolbox_server_tool_invoke_error_count_total{error_type="not_found"`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, got)
+		}
+	}
+}
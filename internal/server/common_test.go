@@ -15,17 +15,25 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/timeout"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -42,9 +50,24 @@ type MockTool struct {
 	manifest                     tools.Manifest
 	unauthorized                 bool
 	requiresClientAuthrorization bool
+	// result is returned by Invoke instead of the tool name if set
+	result any
+	// err is returned by Invoke if set
+	err error
+	// columns is recorded as the order of the columns of result if set
+	columns []string
 }
 
-func (t MockTool) Invoke(context.Context, tools.ParamValues, tools.AccessToken) (any, error) {
+func (t MockTool) Invoke(ctx context.Context, _ tools.ParamValues, _ tools.AccessToken) (any, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if t.columns != nil {
+		tools.SetColumns(ctx, t.columns)
+	}
+	if t.result != nil {
+		return t.result, nil
+	}
 	mock := []any{t.Name}
 	return mock, nil
 }
@@ -142,6 +165,64 @@ var tool5 = MockTool{
 	requiresClientAuthrorization: true,
 }
 
+var tool6 = MockTool{
+	Name:   "paged_tool",
+	Params: []tools.Parameter{},
+	result: tools.PagedResult{Rows: []any{"row1", "row2"}, NextPageToken: "next-page"},
+}
+
+var tool7 = MockTool{
+	Name:   "rows_tool",
+	Params: []tools.Parameter{},
+	result: []any{
+		map[string]any{"id": 1, "name": "Alice"},
+		map[string]any{"id": 2, "name": "Bob"},
+	},
+	columns: []string{"name", "id"},
+}
+
+var tool8 = MockTool{
+	Name:   "timeout_tool",
+	Params: []tools.Parameter{},
+	err:    &timeout.Error{Tool: "timeout_tool", Timeout: time.Second, Err: context.DeadlineExceeded},
+}
+
+// mockToolConfig initializes a MockTool.
+type mockToolConfig struct {
+	tool MockTool
+}
+
+func (c mockToolConfig) ToolConfigKind() string { return "mock" }
+
+func (c mockToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.tool, nil
+}
+
+// requireConfirmation returns tool wrapped to require confirmation.
+func requireConfirmation(t *testing.T, tool MockTool) tools.Tool {
+	tool.manifest = tool.Manifest()
+	cfg := confirmation.ToolConfig{
+		ToolConfig:   mockToolConfig{tool: tool},
+		Name:         tool.Name,
+		Confirmation: confirmation.Config{Message: "Invoke {{ .tool }} with param1={{ .params.param1 }}?"},
+	}
+	confirmed, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return confirmed
+}
+
+// withResultFormat returns tool wrapped to encode its results in format.
+func withResultFormat(t *testing.T, tool MockTool, format resultformat.Format) tools.Tool {
+	tool.manifest = tool.Manifest()
+	formatted, err := resultformat.ToolConfig{ToolConfig: mockToolConfig{tool: tool}, Format: format}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return formatted
+}
+
 // setUpResources setups resources to test against
 func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool, map[string]tools.Toolset) {
 	toolsMap := make(map[string]tools.Tool)
@@ -169,7 +250,7 @@ func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool,
 }
 
 // setUpServer create a new server with tools and toolsets that are given
-func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
+func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset, opts ...func(*Server)) (chi.Router, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -177,7 +258,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		t.Fatalf("unable to initialize logger: %s", err)
 	}
 
-	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox")
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, false, "toolbox")
 	if err != nil {
 		t.Fatalf("unable to setup otel: %s", err)
 	}
@@ -191,13 +272,22 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 
 	resourceManager := NewResourceManager(nil, nil, tools, toolsets)
 
+	confirmations, err := confirmation.NewSigner()
+	if err != nil {
+		t.Fatalf("unable to create confirmation signer: %s", err)
+	}
+
 	server := Server{
 		version:         fakeVersionString,
 		logger:          testLogger,
 		instrumentation: instrumentation,
 		sseManager:      sseManager,
+		confirmations:   confirmations,
 		ResourceMgr:     resourceManager,
 	}
+	for _, o := range opts {
+		o(&server)
+	}
 
 	var r chi.Router
 	switch router {
@@ -211,6 +301,20 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		if err != nil {
 			t.Fatalf("unable to initialize mcp router: %s", err)
 		}
+	case "admin":
+		// the api router is mounted next to the admin router, to observe the
+		// effects of admin requests
+		apiR, err := apiRouter(&server)
+		if err != nil {
+			t.Fatalf("unable to initialize api router: %s", err)
+		}
+		adminR, err := adminRouter(&server)
+		if err != nil {
+			t.Fatalf("unable to initialize admin router: %s", err)
+		}
+		r = chi.NewRouter()
+		r.Mount("/api", apiR)
+		r.Mount("/admin", adminR)
 	default:
 		t.Fatalf("unknown router")
 	}
@@ -227,6 +331,46 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 	return r, shutdown
 }
 
+// withAuditor records tool invocations of the test server to w.
+func withAuditor(t *testing.T, w io.Writer) func(*Server) {
+	a, err := audit.New(context.Background(), audit.Config{Stdout: true, Redact: []string{"param2"}}, w)
+	if err != nil {
+		t.Fatalf("unable to create auditor: %s", err)
+	}
+	return func(s *Server) { s.auditor = a }
+}
+
+// withPrometheus records the metrics of the test server in a meter provider
+// exporting to Prometheus.
+func withPrometheus(t *testing.T, toolMetadata map[string]telemetry.ToolMetadata) func(*Server) {
+	ctx := context.Background()
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, true, "toolbox")
+	if err != nil {
+		t.Fatalf("unable to setup otel: %s", err)
+	}
+	t.Cleanup(func() { _ = otelShutdown(ctx) })
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	instrumentation.SetTools(toolMetadata)
+	return func(s *Server) { s.instrumentation = instrumentation }
+}
+
+// auditEntries decodes the audit entries written by withAuditor.
+func auditEntries(t *testing.T, b []byte) []audit.Entry {
+	var entries []audit.Entry
+	d := json.NewDecoder(bytes.NewReader(b))
+	for d.More() {
+		var e audit.Entry
+		if err := d.Decode(&e); err != nil {
+			t.Fatalf("unable to decode audit entry: %s", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
 func runServer(r chi.Router, tls bool) *httptest.Server {
 	var ts *httptest.Server
 	if tls {
@@ -17,11 +17,19 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/audit"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/cache"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	"github.com/googleapis/genai-toolbox/internal/export"
+	"github.com/googleapis/genai-toolbox/internal/policy"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/timeout"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
@@ -49,6 +57,8 @@ type ServerConfig struct {
 	TelemetryGCP bool
 	// TelemetryOTLP defines OTLP collector url for telemetry exports.
 	TelemetryOTLP string
+	// TelemetryPrometheus defines whether metrics are served at /metrics.
+	TelemetryPrometheus bool
 	// TelemetryServiceName defines the value of service.name resource attribute.
 	TelemetryServiceName string
 	// Stdio indicates if Toolbox is listening via MCP stdio.
@@ -57,6 +67,28 @@ type ServerConfig struct {
 	DisableReload bool
 	// UI indicates if Toolbox UI endpoints (/ui) are available
 	UI bool
+	// Audit defines where tool invocations are recorded.
+	Audit audit.Config
+	// ReadinessSources defines the sources checked by the readiness endpoint.
+	// All sources are checked if empty.
+	ReadinessSources []string
+	// ReadinessTimeout defines how long the readiness endpoint waits for each
+	// source to answer a health check.
+	ReadinessTimeout time.Duration
+	// AdminToken defines the bearer token of the admin API (/admin). The
+	// admin API is disabled if empty.
+	AdminToken string
+	// Origins defines where the sources, tools and toolsets were defined.
+	Origins ConfigOrigins
+}
+
+// ConfigOrigins records where the resources of a configuration were defined,
+// such as the path of a tools file or the name of a prebuilt configuration,
+// by resource name.
+type ConfigOrigins struct {
+	Sources  map[string]string
+	Tools    map[string]string
+	Toolsets map[string]string
 }
 
 type logFormat string
@@ -141,6 +173,11 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 			return fmt.Errorf("invalid 'kind' field for source %q (must be a string)", name)
 		}
 
+		// `dataPolicy` is supported by every kind of source, and applied to
+		// the tools using the source
+		rawPolicy, hasPolicy := v["dataPolicy"]
+		delete(v, "dataPolicy")
+
 		yamlDecoder, err := util.NewStrictDecoder(v)
 		if err != nil {
 			return fmt.Errorf("error creating YAML decoder for source %q: %w", name, err)
@@ -150,6 +187,13 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 		if err != nil {
 			return err
 		}
+		if hasPolicy {
+			policyCfg, err := decodePolicyConfig(ctx, rawPolicy)
+			if err != nil {
+				return fmt.Errorf("unable to parse dataPolicy of source %q: %w", name, err)
+			}
+			sourceConfig = policy.SourceConfig{SourceConfig: sourceConfig, Policy: policyCfg}
+		}
 		(*c)[name] = sourceConfig
 	}
 	return nil
@@ -207,48 +251,224 @@ var _ yaml.InterfaceUnmarshalerContext = &ToolConfigs{}
 func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	*c = make(ToolConfigs)
 	// Parse the 'kind' fields for each source
-	var raw map[string]util.DelayedUnmarshaler
+	var raw map[string]yamlNode
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 
-	for name, u := range raw {
-		var v map[string]any
-		if err := u.Unmarshal(&v); err != nil {
-			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
+	// Resolve `extends` and `parameterSet` references before decoding kinds
+	resolver := newToolResolver(raw, toolDefinitionsFromContext(ctx))
+	for name, n := range raw {
+		v, err := resolver.resolveTool(name)
+		if err != nil {
+			return err
 		}
-
-		// `authRequired` and `useClientOAuth` cannot be specified together
-		if v["authRequired"] != nil && v["useClientOAuth"] == true {
-			return fmt.Errorf("`authRequired` and `useClientOAuth` are mutually exclusive. Choose only one authentication method")
+		toolCfg, err := decodeToolConfig(ctx, name, v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", resolver.pos(n), err)
 		}
+		(*c)[name] = toolCfg
+	}
+	return nil
+}
 
-		// Make `authRequired` an empty list instead of nil for Tool manifest
-		if v["authRequired"] == nil {
-			v["authRequired"] = []string{}
-		}
+// decodeToolConfig decodes the resolved definition v of the tool name.
+func decodeToolConfig(ctx context.Context, name string, v map[string]any) (tools.ToolConfig, error) {
+	// `authRequired` and `useClientOAuth` cannot be specified together
+	if v["authRequired"] != nil && v["useClientOAuth"] == true {
+		return nil, fmt.Errorf("`authRequired` and `useClientOAuth` are mutually exclusive. Choose only one authentication method")
+	}
+
+	// Make `authRequired` an empty list instead of nil for Tool manifest
+	if v["authRequired"] == nil {
+		v["authRequired"] = []string{}
+	}
+
+	kindVal, ok := v["kind"]
+	if !ok {
+		return nil, fmt.Errorf("missing 'kind' field for tool %q", name)
+	}
+	kindStr, ok := kindVal.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid 'kind' field for tool %q (must be a string)", name)
+	}
 
-		kindVal, ok := v["kind"]
+	// `cache` is supported by every kind of tool, so it is decoded here
+	// rather than by the tool config
+	rawCache, hasCache := v["cache"]
+	delete(v, "cache")
+	rawConfirmation, hasConfirmation := v["requireConfirmation"]
+	delete(v, "requireConfirmation")
+	rawPolicy, hasPolicy := v["dataPolicy"]
+	delete(v, "dataPolicy")
+	rawFormat, hasFormat := v["resultFormat"]
+	delete(v, "resultFormat")
+	rawExport, hasExport := v["export"]
+	delete(v, "export")
+
+	// `timeout` is supported by every kind of tool, so it is decoded here
+	// unless the kind has a `timeout` of its own, such as wait tools
+	ownsTimeout, err := tools.OwnsTimeout(ctx, kindStr)
+	if err != nil {
+		return nil, err
+	}
+	rawTimeout, hasTimeout := v["timeout"]
+	if ownsTimeout {
+		hasTimeout = false
+	} else {
+		delete(v, "timeout")
+	}
+
+	toolCfg, err := decodeKindConfig(ctx, kindStr, name, v)
+	if err != nil {
+		return nil, err
+	}
+	// the timeout wraps the tool alone, so that waiting for a confirmation
+	// or writing an export does not count towards it
+	if hasTimeout {
+		timeoutStr, ok := rawTimeout.(string)
 		if !ok {
-			return fmt.Errorf("missing 'kind' field for tool %q", name)
+			return nil, fmt.Errorf("invalid 'timeout' field for tool %q (must be a string)", name)
 		}
-		kindStr, ok := kindVal.(string)
+		d, err := timeout.Parse(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse timeout of tool %q: %w", name, err)
+		}
+		toolCfg = timeout.ToolConfig{ToolConfig: toolCfg, Name: name, Timeout: d}
+	}
+	if hasFormat {
+		formatStr, ok := rawFormat.(string)
 		if !ok {
-			return fmt.Errorf("invalid 'kind' field for tool %q (must be a string)", name)
+			return nil, fmt.Errorf("invalid 'resultFormat' field for tool %q (must be a string)", name)
 		}
-
-		yamlDecoder, err := util.NewStrictDecoder(v)
+		format, err := resultformat.Parse(formatStr)
 		if err != nil {
-			return fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
+			return nil, fmt.Errorf("unable to parse resultFormat of tool %q: %w", name, err)
 		}
-
-		toolCfg, err := tools.DecodeConfig(ctx, kindStr, name, yamlDecoder)
+		toolCfg = resultformat.ToolConfig{ToolConfig: toolCfg, Format: format}
+	}
+	if hasCache {
+		cacheCfg, err := decodeCacheConfig(ctx, rawCache)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("unable to parse cache of tool %q: %w", name, err)
 		}
-		(*c)[name] = toolCfg
+		toolCfg = cache.ToolConfig{ToolConfig: toolCfg, Name: name, Cache: cacheCfg}
 	}
-	return nil
+	if hasConfirmation {
+		confirmationCfg, required, err := decodeConfirmationConfig(ctx, rawConfirmation)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse requireConfirmation of tool %q: %w", name, err)
+		}
+		if required {
+			toolCfg = confirmation.ToolConfig{ToolConfig: toolCfg, Name: name, Confirmation: confirmationCfg}
+		}
+	}
+	// the data policy wraps the cache, which stores unprotected results
+	if hasPolicy {
+		policyCfg, err := decodePolicyConfig(ctx, rawPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse dataPolicy of tool %q: %w", name, err)
+		}
+		toolCfg = policy.ToolConfig{ToolConfig: toolCfg, Name: name, Policy: policyCfg}
+	}
+	// the export wraps the data policy, which masks the exported rows as
+	// they are streamed
+	if hasExport {
+		exportCfg, err := decodeExportConfig(ctx, rawExport)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse export of tool %q: %w", name, err)
+		}
+		toolCfg = export.ToolConfig{ToolConfig: toolCfg, Name: name, Export: exportCfg}
+	}
+	return toolCfg, nil
+}
+
+// decodeKindConfig decodes the config of a tool of the given kind.
+func decodeKindConfig(ctx context.Context, kind, name string, v map[string]any) (tools.ToolConfig, error) {
+	yamlDecoder, err := util.NewStrictDecoder(v)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
+	}
+	return tools.DecodeConfig(ctx, kind, name, yamlDecoder)
+}
+
+// decodePolicyConfig decodes the `dataPolicy` block of a tool or source.
+func decodePolicyConfig(ctx context.Context, raw any) (policy.Config, error) {
+	var cfg policy.Config
+	if raw == nil {
+		return cfg, nil
+	}
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return cfg, err
+	}
+	if err := dec.DecodeContext(ctx, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// decodeConfirmationConfig decodes the `requireConfirmation` option of a
+// tool, which is either a boolean or a block, and reports whether
+// confirmation is required.
+func decodeConfirmationConfig(ctx context.Context, raw any) (confirmation.Config, bool, error) {
+	var cfg confirmation.Config
+	switch raw := raw.(type) {
+	case nil:
+		return cfg, false, nil
+	case bool:
+		return cfg, raw, nil
+	}
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return cfg, false, err
+	}
+	if err := dec.DecodeContext(ctx, &cfg); err != nil {
+		return cfg, false, err
+	}
+	return cfg, true, nil
+}
+
+// decodeCacheConfig decodes the `cache` block of a tool.
+func decodeCacheConfig(ctx context.Context, raw any) (cache.Config, error) {
+	var cfg cache.Config
+	if raw == nil {
+		return cfg, nil
+	}
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return cfg, err
+	}
+	if err := dec.DecodeContext(ctx, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.TTL != "" {
+		if ttl, err := time.ParseDuration(cfg.TTL); err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("invalid ttl %q: must be a positive duration", cfg.TTL)
+		}
+	}
+	return cfg, nil
+}
+
+// decodeExportConfig decodes the `export` block of a tool.
+func decodeExportConfig(ctx context.Context, raw any) (export.Config, error) {
+	var cfg export.Config
+	dec, err := util.NewStrictDecoder(raw)
+	if err != nil {
+		return cfg, err
+	}
+	if err := dec.DecodeContext(ctx, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.Destination == "" {
+		return cfg, fmt.Errorf("missing destination")
+	}
+	if cfg.Retention != "" {
+		if retention, err := time.ParseDuration(cfg.Retention); err != nil || retention <= 0 {
+			return cfg, fmt.Errorf("invalid retention %q: must be a positive duration", cfg.Retention)
+		}
+	}
+	return cfg, nil
 }
 
 // ToolConfigs is a type used to allow unmarshal of the toolset configs
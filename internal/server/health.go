@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+
+	// defaultReadinessTimeout bounds the health check of each source if no
+	// timeout is configured.
+	defaultReadinessTimeout = 5 * time.Second
+)
+
+// sourceReadiness is the health of a single source.
+type sourceReadiness struct {
+	Kind      string  `json:"kind,omitempty"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// reloadReadiness is the outcome of the last reload.
+type reloadReadiness struct {
+	Status string `json:"status"`
+	ReloadStatus
+}
+
+// readiness is the response of the readiness endpoint.
+type readiness struct {
+	Status   string                     `json:"status"`
+	Sources  map[string]sourceReadiness `json:"sources"`
+	Reload   *reloadReadiness           `json:"reload,omitempty"`
+	Draining bool                       `json:"draining,omitempty"`
+}
+
+// healthzHandler reports that the process is up.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]string{"status": statusOK})
+}
+
+// readyzHandler reports whether the checked sources are healthy, the last
+// reload succeeded and the server is not draining, with the status of each
+// source.
+func readyzHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	res := s.checkReadiness(r.Context())
+	if res.Status != statusOK {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, res)
+}
+
+// checkReadiness checks the health of the configured sources, or of every
+// source if none is configured, in parallel.
+func (s *Server) checkReadiness(ctx context.Context) readiness {
+	current := s.ResourceMgr.GetSourcesMap()
+	names := s.readinessSources
+	if len(names) == 0 {
+		for name := range current {
+			names = append(names, name)
+		}
+	}
+	res := readiness{Status: statusOK, Sources: make(map[string]sourceReadiness, len(names))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		src, ok := current[name]
+		if !ok {
+			mu.Lock()
+			res.Sources[name] = sourceReadiness{Status: statusUnavailable, Error: fmt.Sprintf("source %q does not exist", name)}
+			res.Status = statusUnavailable
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sr := s.checkSource(ctx, src)
+			mu.Lock()
+			defer mu.Unlock()
+			res.Sources[name] = sr
+			if sr.Status != statusOK {
+				res.Status = statusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	if last, ok := s.ResourceMgr.LastReload(); ok {
+		res.Reload = &reloadReadiness{Status: statusOK, ReloadStatus: last}
+		if last.Error != "" {
+			res.Reload.Status = statusUnavailable
+			res.Status = statusUnavailable
+		}
+	}
+	if s.draining.Load() {
+		res.Draining = true
+		res.Status = statusUnavailable
+	}
+	return res
+}
+
+// checkSource checks the health of a source within the readiness timeout.
+func (s *Server) checkSource(ctx context.Context, src sources.Source) sourceReadiness {
+	timeout := s.readinessTimeout
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	err := sources.Health(ctx, src)
+	sr := sourceReadiness{
+		Kind:      src.SourceKind(),
+		Status:    statusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		sr.Status = statusUnavailable
+		sr.Error = err.Error()
+	}
+	return sr
+}
+
+// healthStatus reports the readiness checked by checkReadiness to the health
+// gauges.
+func (s *Server) healthStatus(ctx context.Context) telemetry.HealthStatus {
+	res := s.checkReadiness(ctx)
+	status := telemetry.HealthStatus{
+		Ready:   res.Status == statusOK,
+		Sources: make(map[string]telemetry.SourceHealth, len(res.Sources)),
+	}
+	for name, sr := range res.Sources {
+		status.Sources[name] = telemetry.SourceHealth{Kind: sr.Kind, Healthy: sr.Status == statusOK}
+	}
+	return status
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// healthSource is a source whose health check returns err, or blocks until
+// its context is done if hang is set.
+type healthSource struct {
+	err  error
+	hang bool
+}
+
+func (s healthSource) SourceKind() string { return "fake" }
+
+func (s healthSource) Health(ctx context.Context) error {
+	if s.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.err
+}
+
+func TestHealthz(t *testing.T) {
+	rec := httptest.NewRecorder()
+	healthzHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"healthy":   healthSource{},
+		"unhealthy": healthSource{err: errors.New("connection refused")},
+		"hanging":   healthSource{hang: true},
+	}
+	tcs := []struct {
+		desc       string
+		sources    []string
+		reloadErr  error
+		wantCode   int
+		wantStatus map[string]string
+	}{
+		{
+			desc:       "all sources",
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: map[string]string{"healthy": "ok", "unhealthy": "unavailable", "hanging": "unavailable"},
+		},
+		{
+			desc:       "healthy subset",
+			sources:    []string{"healthy"},
+			wantCode:   http.StatusOK,
+			wantStatus: map[string]string{"healthy": "ok"},
+		},
+		{
+			desc:       "missing source",
+			sources:    []string{"healthy", "missing"},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: map[string]string{"healthy": "ok", "missing": "unavailable"},
+		},
+		{
+			desc:       "failed reload",
+			sources:    []string{"healthy"},
+			reloadErr:  errors.New("invalid tools file"),
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: map[string]string{"healthy": "ok"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := &Server{
+				ResourceMgr:      NewResourceManager(srcs, nil, nil, nil),
+				readinessSources: tc.sources,
+				readinessTimeout: 10 * time.Millisecond,
+			}
+			if tc.reloadErr != nil {
+				s.ResourceMgr.RecordReload(tc.reloadErr)
+			}
+			rec := httptest.NewRecorder()
+			readyzHandler(s, rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tc.wantCode {
+				t.Fatalf("unexpected status code: got %d, want %d", rec.Code, tc.wantCode)
+			}
+			var got readiness
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			if len(got.Sources) != len(tc.wantStatus) {
+				t.Fatalf("unexpected sources: got %v, want %v", got.Sources, tc.wantStatus)
+			}
+			for name, want := range tc.wantStatus {
+				if got.Sources[name].Status != want {
+					t.Errorf("unexpected status of source %q: got %+v, want %q", name, got.Sources[name], want)
+				}
+			}
+			if tc.reloadErr != nil && (got.Reload == nil || got.Reload.Error != tc.reloadErr.Error()) {
+				t.Errorf("expected failed reload to be reported, got %+v", got.Reload)
+			}
+		})
+	}
+}
@@ -31,6 +31,8 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
@@ -112,10 +114,14 @@ func (m *sseManager) cleanupRoutine(ctx context.Context) {
 }
 
 type stdioSession struct {
-	protocol string
-	server   *Server
-	reader   *bufio.Reader
-	writer   io.Writer
+	// mu guards protocol and writes to writer, as tool calls are processed
+	// concurrently
+	mu           sync.Mutex
+	protocol     string
+	capabilities mcputil.ClientCapabilities
+	server       *Server
+	reader       *bufio.Reader
+	writer       io.Writer
 }
 
 func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession {
@@ -133,6 +139,15 @@ func (s *stdioSession) Start(ctx context.Context) error {
 
 // readInputStream reads requests/notifications from MCP clients through stdin
 func (s *stdioSession) readInputStream(ctx context.Context) error {
+	// Tool calls are processed concurrently, so that the responses of the
+	// client to requests of the server, such as elicitations, can be read
+	// while they wait.
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	closed := make(chan struct{})
+	defer close(closed)
+	requester := s.server.clientRequests.requester(s.send, closed)
+
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -144,24 +159,51 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 			}
 			return err
 		}
-		v, res, err := processMcpMessage(ctx, []byte(line), s.server, s.protocol, "", nil)
-		if err != nil {
-			// errors during the processing of message will generate a valid MCP Error response.
-			// server can continue to run.
-			s.server.logger.ErrorContext(ctx, err.Error())
-		}
-		if v != "" {
-			s.protocol = v
+		msgCtx := audit.WithRequestInfo(ctx, audit.TransportStdio, "")
+		msgCtx = mcputil.WithClientRequester(msgCtx, requester)
+
+		var baseMessage jsonrpc.BaseMessage
+		if json.Unmarshal([]byte(line), &baseMessage) == nil && baseMessage.Method == mcputil.TOOLS_CALL {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.process(msgCtx, line); err != nil {
+					s.server.logger.ErrorContext(ctx, err.Error())
+				}
+			}()
+			continue
 		}
-		// no responses for notifications
-		if res != nil {
-			if err = s.write(ctx, res); err != nil {
-				return err
-			}
+		if err := s.process(msgCtx, line); err != nil {
+			return err
 		}
 	}
 }
 
+// process processes a message, and writes the response if there is one.
+func (s *stdioSession) process(ctx context.Context, line string) error {
+	s.mu.Lock()
+	protocol, capabilities := s.protocol, s.capabilities
+	s.mu.Unlock()
+	ctx = mcputil.WithClientCapabilities(ctx, capabilities)
+	v, res, err := processMcpMessage(ctx, []byte(line), s.server, protocol, "", nil)
+	if err != nil {
+		// errors during the processing of message will generate a valid MCP Error response.
+		// server can continue to run.
+		s.server.logger.ErrorContext(ctx, err.Error())
+	}
+	if v != "" {
+		s.mu.Lock()
+		s.protocol = v
+		s.capabilities = initializeCapabilities([]byte(line))
+		s.mu.Unlock()
+	}
+	// no responses for notifications
+	if res == nil {
+		return nil
+	}
+	return s.write(ctx, res)
+}
+
 // readLine process each line within the input stream.
 func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 	readChan := make(chan string, 1)
@@ -208,11 +250,194 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 // write writes to stdout with response to client
 func (s *stdioSession) write(ctx context.Context, response any) error {
 	res, _ := json.Marshal(response)
+	return s.send(ctx, res)
+}
 
-	_, err := fmt.Fprintf(s.writer, "%s\n", res)
+// send writes a message to stdout.
+func (s *stdioSession) send(ctx context.Context, msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintf(s.writer, "%s\n", msg)
 	return err
 }
 
+// clientRequestTimeout bounds how long the server waits for the response of
+// the client to a request, such as the user answering an elicitation.
+const clientRequestTimeout = 10 * time.Minute
+
+// clientSessionTimeout is how long the capabilities of an idle client are
+// kept.
+const clientSessionTimeout = 10 * time.Minute
+
+// clientCapabilities records the capabilities declared by MCP clients of the
+// HTTP transports in their initialize request, by session, so that the server
+// only sends them requests they support.
+type clientCapabilities struct {
+	mu       sync.Mutex
+	sessions map[string]*clientSession
+}
+
+type clientSession struct {
+	capabilities mcputil.ClientCapabilities
+	lastActive   time.Time
+}
+
+// set records the capabilities of a session, forgetting idle sessions.
+func (c *clientCapabilities) set(id string, capabilities mcputil.ClientCapabilities) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sessions == nil {
+		c.sessions = make(map[string]*clientSession)
+	}
+	now := time.Now()
+	for id, sess := range c.sessions {
+		if now.Sub(sess.lastActive) > clientSessionTimeout {
+			delete(c.sessions, id)
+		}
+	}
+	c.sessions[id] = &clientSession{capabilities: capabilities, lastActive: now}
+}
+
+// get returns the capabilities of a session, which are empty if the session
+// is unknown.
+func (c *clientCapabilities) get(id string) mcputil.ClientCapabilities {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sess, ok := c.sessions[id]
+	if !ok {
+		return mcputil.ClientCapabilities{}
+	}
+	sess.lastActive = time.Now()
+	return sess.capabilities
+}
+
+// initializeCapabilities returns the capabilities declared by an initialize
+// request.
+func initializeCapabilities(body []byte) mcputil.ClientCapabilities {
+	var req mcputil.InitializeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return mcputil.ClientCapabilities{}
+	}
+	return req.Params.Capabilities
+}
+
+// clientResponse is the response of an MCP client to a request of the server.
+type clientResponse struct {
+	Id     jsonrpc.RequestId `json:"id"`
+	Result json.RawMessage   `json:"result"`
+	Error  *jsonrpc.Error    `json:"error"`
+}
+
+// clientRequests tracks the requests sent to MCP clients until their
+// responses are received. Responses may be received through any transport,
+// as clients of the streamable HTTP transport post them as new requests.
+type clientRequests struct {
+	mu      sync.Mutex
+	pending map[string]chan clientResponse
+}
+
+// requester returns a ClientRequester writing requests with send. Requests
+// fail once closed is closed.
+func (c *clientRequests) requester(send func(context.Context, []byte) error, closed <-chan struct{}) mcputil.ClientRequester {
+	return func(ctx context.Context, method string, params any) (json.RawMessage, error) {
+		ctx, cancel := context.WithTimeout(ctx, clientRequestTimeout)
+		defer cancel()
+
+		id := uuid.New().String()
+		ch := make(chan clientResponse, 1)
+		c.mu.Lock()
+		if c.pending == nil {
+			c.pending = make(map[string]chan clientResponse)
+		}
+		c.pending[id] = ch
+		c.mu.Unlock()
+		defer func() {
+			c.mu.Lock()
+			delete(c.pending, id)
+			c.mu.Unlock()
+		}()
+
+		req := jsonrpc.JSONRPCRequest{
+			Jsonrpc: jsonrpc.JSONRPC_VERSION,
+			Id:      id,
+			Request: jsonrpc.Request{Method: method},
+			Params:  params,
+		}
+		msg, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := send(ctx, msg); err != nil {
+			return nil, fmt.Errorf("unable to send %s request: %w", method, err)
+		}
+		select {
+		case res := <-ch:
+			if res.Error != nil {
+				return nil, fmt.Errorf("client returned error %d: %s", res.Error.Code, res.Error.Message)
+			}
+			return res.Result, nil
+		case <-closed:
+			return nil, fmt.Errorf("connection to the client was closed")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// deliver delivers res to the pending request with its id, and reports
+// whether there was one.
+func (c *clientRequests) deliver(res clientResponse) bool {
+	id, ok := res.Id.(string)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	ch, ok := c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+	if ok {
+		ch <- res
+	}
+	return ok
+}
+
+// eventStream upgrades the response to a POST request of the streamable HTTP
+// transport to an SSE stream, once the server sends a request to the client
+// before responding.
+type eventStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	started bool
+}
+
+// send writes msg as an event, starting the stream if needed.
+func (e *eventStream) send(ctx context.Context, msg []byte) error {
+	flusher, ok := e.w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("unable to retrieve flusher for sse")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.started {
+		e.w.Header().Set("Content-Type", "text/event-stream")
+		e.w.Header().Set("Cache-Control", "no-cache")
+		e.w.WriteHeader(http.StatusOK)
+		e.started = true
+	}
+	if _, err := fmt.Fprintf(e.w, "event: message\ndata: %s\n\n", msg); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// isStarted reports whether the stream has started.
+func (e *eventStream) isStarted() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.started
+}
+
 // mcpRouter creates a router that represents the routes under /mcp
 func mcpRouter(s *Server) (chi.Router, error) {
 	r := chi.NewRouter()
@@ -406,6 +631,37 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	transport := audit.TransportMCPHTTP
+	if session != nil {
+		transport = audit.TransportSSE
+	}
+	ctx = audit.WithRequestInfo(ctx, transport, toolsetName)
+	if session != nil {
+		ctx = mcputil.WithClientCapabilities(ctx, s.capabilities.get(sessionId))
+	} else if headerSessionId != "" {
+		ctx = mcputil.WithClientCapabilities(ctx, s.capabilities.get(headerSessionId))
+	}
+
+	// Requests to the client are sent through the SSE session, or through
+	// the response if the client accepts a stream.
+	var stream *eventStream
+	if session != nil {
+		send := func(ctx context.Context, msg []byte) error {
+			select {
+			case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", msg):
+				return nil
+			case <-session.done:
+				return fmt.Errorf("sse session is closed")
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		ctx = mcputil.WithClientRequester(ctx, s.clientRequests.requester(send, session.done))
+	} else if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		stream = &eventStream{w: w}
+		ctx = mcputil.WithClientRequester(ctx, s.clientRequests.requester(stream.send, nil))
+	}
+
 	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, r.Header)
 	if err != nil {
 		s.logger.DebugContext(ctx, fmt.Errorf("error processing message: %w", err).Error())
@@ -419,10 +675,27 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// for v20250326, add the `Mcp-Session-Id` header
-	if v == v20250326.PROTOCOL_VERSION {
-		sessionId = uuid.New().String()
-		w.Header().Set("Mcp-Session-Id", sessionId)
+	// once streaming, the response is the last event of the stream
+	if stream != nil && stream.isStarted() {
+		eventData, _ := json.Marshal(res)
+		if sendErr := stream.send(ctx, eventData); sendErr != nil {
+			s.logger.DebugContext(ctx, fmt.Sprintf("unable to send response: %s", sendErr))
+		}
+		return
+	}
+
+	// record the capabilities declared by the client. For v20250326, and for
+	// clients declaring elicitation, add the `Mcp-Session-Id` header, which
+	// identifies the client in its next requests.
+	if v != "" {
+		capabilities := initializeCapabilities(body)
+		if session == nil && (v == v20250326.PROTOCOL_VERSION || capabilities.Elicitation != nil) {
+			sessionId = uuid.New().String()
+			w.Header().Set("Mcp-Session-Id", sessionId)
+		}
+		if sessionId != "" {
+			s.capabilities.set(sessionId, capabilities)
+		}
 	}
 
 	if session != nil {
@@ -464,6 +737,8 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 	if err != nil {
 		return "", jsonrpc.NewError("", jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
 	}
+	ctx = audit.WithAuditor(ctx, s.auditor)
+	ctx = util.WithInstrumentation(ctx, s.instrumentation)
 
 	// Generic baseMessage could either be a JSONRPCNotification or JSONRPCRequest
 	var baseMessage jsonrpc.BaseMessage
@@ -482,6 +757,18 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 		return "", jsonrpc.NewError(id, jsonrpc.PARSE_ERROR, err.Error(), nil), err
 	}
 
+	// Responses of the client to requests of the server, such as
+	// elicitations, are delivered to the waiting request
+	if baseMessage.Method == "" && baseMessage.Id != nil {
+		var res clientResponse
+		if json.Unmarshal(body, &res) == nil && (res.Result != nil || res.Error != nil) {
+			if !s.clientRequests.deliver(res) {
+				logger.DebugContext(ctx, fmt.Sprintf("no pending request with id %v", res.Id))
+			}
+			return "", nil, nil
+		}
+	}
+
 	// Check if method is present
 	if baseMessage.Method == "" {
 		err = fmt.Errorf("method not found")
@@ -509,11 +796,21 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 		}
 		return v, res, err
 	default:
+		if baseMessage.Method == mcputil.TOOLS_CALL && s.draining.Load() {
+			err = fmt.Errorf("server is draining and no longer accepts tool invocations")
+			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		// the sources used by the request are kept open until it completes
+		defer s.ResourceMgr.Acquire()()
 		toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
 		if !ok {
 			err = fmt.Errorf("toolset does not exist")
 			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
+		// invocations requiring confirmation fail with a token confirming
+		// them over the REST API, unless the client confirms them through
+		// elicitation
+		ctx = confirmation.WithConfirmer(ctx, s.confirmations.Confirmer(""))
 		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), s.ResourceMgr.GetAuthServiceMap(), body, header)
 		return "", res, err
 	}
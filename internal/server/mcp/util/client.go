@@ -0,0 +1,58 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ELICITATION_CREATE is the method requesting information from the user
+// through the client.
+const ELICITATION_CREATE = "elicitation/create"
+
+// ClientRequester sends a request to the MCP client, and returns the result
+// of its response.
+type ClientRequester func(ctx context.Context, method string, params any) (json.RawMessage, error)
+
+type clientRequesterKey struct{}
+
+// WithClientRequester returns a context in which requests to the client are
+// sent with r.
+func WithClientRequester(ctx context.Context, r ClientRequester) context.Context {
+	return context.WithValue(ctx, clientRequesterKey{}, r)
+}
+
+// ClientRequesterFromContext returns the ClientRequester of ctx, or nil if
+// the transport cannot send requests to the client.
+func ClientRequesterFromContext(ctx context.Context) ClientRequester {
+	r, _ := ctx.Value(clientRequesterKey{}).(ClientRequester)
+	return r
+}
+
+type clientCapabilitiesKey struct{}
+
+// WithClientCapabilities returns a context recording the capabilities the
+// client declared in its initialize request.
+func WithClientCapabilities(ctx context.Context, c ClientCapabilities) context.Context {
+	return context.WithValue(ctx, clientCapabilitiesKey{}, c)
+}
+
+// ClientCapabilitiesFromContext returns the capabilities declared by the
+// client, which are empty if it did not declare any.
+func ClientCapabilitiesFromContext(ctx context.Context) ClientCapabilities {
+	c, _ := ctx.Value(clientCapabilitiesKey{}).(ClientCapabilities)
+	return c
+}
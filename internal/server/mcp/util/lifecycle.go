@@ -23,6 +23,8 @@ const (
 	SERVER_NAME = "Toolbox"
 	// methods that are supported
 	INITIALIZE = "initialize"
+	// TOOLS_CALL is the method invoking a tool, in every supported version.
+	TOOLS_CALL = "tools/call"
 )
 
 /* Initialization */
@@ -82,6 +84,8 @@ type ClientCapabilities struct {
 	Roots *ListChanged `json:"roots,omitempty"`
 	// Present if the client supports sampling from an LLM.
 	Sampling struct{} `json:"sampling,omitempty"`
+	// Present if the client supports elicitation from the user.
+	Elicitation *struct{} `json:"elicitation,omitempty"`
 }
 
 // ServerCapabilities represents capabilities that a server may support. Known
@@ -77,6 +77,7 @@ type CallToolRequest struct {
 	Params struct {
 		Name      string         `json:"name"`
 		Arguments map[string]any `json:"arguments,omitempty"`
+		Meta      map[string]any `json:"_meta,omitempty"`
 	} `json:"params,omitempty"`
 }
 
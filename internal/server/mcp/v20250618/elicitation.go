@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20250618
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+)
+
+// elicitationConfirmer returns a Confirmer asking the user to confirm
+// invocations with an elicitation/create request.
+func elicitationConfirmer(requester mcputil.ClientRequester) confirmation.Confirmer {
+	return confirmation.ConfirmerFunc(func(ctx context.Context, req confirmation.Request) error {
+		params := ElicitRequestParams{
+			Message: req.Message,
+			RequestedSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"confirm": map[string]any{
+						"type":        "boolean",
+						"title":       "Confirm",
+						"description": fmt.Sprintf("Invoke tool %q", req.Tool),
+						"default":     false,
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		}
+		raw, err := requester(ctx, mcputil.ELICITATION_CREATE, params)
+		if err != nil {
+			return fmt.Errorf("unable to request confirmation from the client: %w", err)
+		}
+		var res ElicitResult
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return fmt.Errorf("invalid elicitation result: %w", err)
+		}
+		if res.Action != "accept" {
+			return confirmation.ErrDeclined
+		}
+		if confirmed, _ := res.Content["confirm"].(bool); !confirmed {
+			return confirmation.ErrDeclined
+		}
+		return nil
+	})
+}
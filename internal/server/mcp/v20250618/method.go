@@ -22,9 +22,16 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/audit"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
+	"github.com/googleapis/genai-toolbox/internal/export"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
@@ -71,7 +78,7 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 }
 
 // toolsCallHandler generate a response for tools call.
-func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[string]tools.Tool, authServices map[string]auth.AuthService, body []byte, header http.Header) (any, error) {
+func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[string]tools.Tool, authServices map[string]auth.AuthService, body []byte, header http.Header) (res any, err error) {
 	// retrieve logger from context
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -87,9 +94,27 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	toolName := req.Params.Name
 	toolArgument := req.Params.Arguments
 	logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
+	auditInv := audit.Begin(ctx, toolName)
+	start := time.Now()
+	// invocation errors are returned as results, so they are recorded separately
+	var invokeErr error
+	// errType categorizes the error, and rows and size describe successful results
+	var errType string
+	var rows, size int
+	defer func() {
+		if instrumentation, iErr := util.InstrumentationFromContext(ctx); iErr == nil {
+			instrumentation.RecordToolInvocation(ctx, toolName, time.Since(start), errType, rows, size)
+		}
+		if invokeErr != nil {
+			auditInv.End(ctx, invokeErr)
+			return
+		}
+		auditInv.End(ctx, err)
+	}()
 	tool, ok := toolsMap[toolName]
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		errType = telemetry.ErrorTypeNotFound
 		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
 	}
 
@@ -99,6 +124,7 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	// Check if this specific tool requires the standard authorization header
 	if tool.RequiresClientAuthorization() {
 		if accessToken == "" {
+			errType = telemetry.ErrorTypeUnauthorized
 			return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, "missing access token in the 'Authorization' header", nil), tools.ErrUnauthorized
 		}
 	}
@@ -107,12 +133,14 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	aMarshal, err := json.Marshal(toolArgument)
 	if err != nil {
 		err = fmt.Errorf("unable to marshal tools argument: %w", err)
+		errType = telemetry.ErrorTypeInvalidRequest
 		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
 	}
 
 	var data map[string]any
 	if err = util.DecodeJSON(bytes.NewBuffer(aMarshal), &data); err != nil {
 		err = fmt.Errorf("unable to decode tools argument: %w", err)
+		errType = telemetry.ErrorTypeInvalidRequest
 		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
 	}
 
@@ -136,6 +164,15 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 		}
 	}
 
+	auditInv.SetPrincipals(claimsFromAuth)
+	ctx = util.WithHeader(util.WithClaims(ctx, claimsFromAuth), header)
+	// invocations are confirmed by the user through elicitation, if the
+	// client supports it and the transport can send requests to the client
+	requester := mcputil.ClientRequesterFromContext(ctx)
+	if requester != nil && mcputil.ClientCapabilitiesFromContext(ctx).Elicitation != nil {
+		ctx = confirmation.WithConfirmer(ctx, elicitationConfirmer(requester))
+	}
+
 	// Tool authorization check
 	verifiedAuthServices := make([]string, len(claimsFromAuth))
 	i := 0
@@ -148,27 +185,45 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 	isAuthorized := tool.Authorized(verifiedAuthServices)
 	if !isAuthorized {
 		err = fmt.Errorf("unauthorized Tool call: Please make sure your specify correct auth headers: %w", tools.ErrUnauthorized)
+		errType = telemetry.ErrorTypeUnauthorized
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 	logger.DebugContext(ctx, "tool invocation authorized")
 
 	params, err := tool.ParseParams(data, claimsFromAuth)
 	if err != nil {
+		errType = telemetry.ErrorTypeInvalidParams
+		if errors.Is(err, tools.ErrUnauthorized) {
+			errType = telemetry.ErrorTypeUnauthorized
+		}
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
+	auditInv.SetParams(params)
+
+	requestedFormat, _ := req.Params.Meta[resultformat.MetaKey].(string)
+	format, err := resultformat.Resolve(requestedFormat, tool)
+	if err != nil {
+		errType = telemetry.ErrorTypeInvalidParams
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
 
 	// run tool invocation and generate response.
+	// rows are encoded with the columns in the order of the statement
+	ctx, columns := tools.WithColumnOrder(ctx)
 	results, err := tool.Invoke(ctx, params, accessToken)
 	if err != nil {
+		invokeErr = err
 		errStr := err.Error()
 		// Missing authService tokens.
 		if errors.Is(err, tools.ErrUnauthorized) {
+			errType = telemetry.ErrorTypeUnauthorized
 			return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
 		// Upstream auth error
 		if strings.Contains(errStr, "Error 401") || strings.Contains(errStr, "Error 403") {
+			errType = telemetry.ErrorTypeUpstreamAuth
 			if tool.RequiresClientAuthorization() {
 				// Error with client credentials should pass down to the client
 				return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
@@ -176,6 +231,7 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 			// Auth error with ADC should raise internal 500 error
 			return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
 		}
+		errType = telemetry.InvokeErrorType(err)
 		text := TextContent{
 			Type: "text",
 			Text: err.Error(),
@@ -183,28 +239,67 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[st
 		return jsonrpc.JSONRPCResponse{
 			Jsonrpc: jsonrpc.JSONRPC_VERSION,
 			Id:      id,
-			Result:  CallToolResult{Content: []TextContent{text}, IsError: true},
+			Result:  CallToolResult{Content: []any{text}, IsError: true},
 		}, nil
 	}
 
-	content := make([]TextContent, 0)
+	rows = tools.ResultRows(results)
+	content := make([]any, 0)
 
-	sliceRes, ok := results.([]any)
-	if !ok {
-		sliceRes = []any{results}
+	// exported rows are returned as a link to the file, followed by its
+	// schema and row count
+	if e, ok := results.(export.Result); ok {
+		content = append(content, ResourceLink{
+			Type:        "resource_link",
+			URI:         e.URI,
+			Name:        e.Name,
+			Description: fmt.Sprintf("%d rows exported by tool %q", e.RowCount, toolName),
+			MimeType:    e.MimeType,
+			Size:        e.Size,
+		})
 	}
 
-	for _, d := range sliceRes {
-		text := TextContent{Type: "text"}
-		dM, err := json.Marshal(d)
-		if err != nil {
-			text.Text = fmt.Sprintf("fail to marshal: %s, result: %s", err, d)
-		} else {
-			text.Text = string(dM)
+	// Paged results are returned as the rows of the page, followed by the
+	// token for the next page.
+	var nextPageToken string
+	if p, ok := results.(tools.PagedResult); ok {
+		results = p.Rows
+		nextPageToken = p.NextPageToken
+	}
+
+	// rows encoded in a format other than json-rows are returned as a single
+	// text content
+	if encoded, ok, err := resultformat.Encode(results, format, columns.Columns()); err != nil {
+		content = append(content, TextContent{Type: "text", Text: fmt.Sprintf("fail to marshal: %s, result: %s", err, results)})
+	} else if ok {
+		content = append(content, TextContent{Type: "text", Text: encoded})
+		size = len(encoded)
+	} else {
+		sliceRes, ok := results.([]any)
+		if !ok {
+			sliceRes = []any{results}
 		}
-		content = append(content, text)
+
+		for _, d := range sliceRes {
+			text := TextContent{Type: "text"}
+			dM, err := json.Marshal(d)
+			if err != nil {
+				text.Text = fmt.Sprintf("fail to marshal: %s, result: %s", err, d)
+			} else {
+				text.Text = string(dM)
+				size += len(dM)
+			}
+			content = append(content, text)
+		}
+	}
+	if nextPageToken != "" {
+		// more rows are available, let the client know how to retrieve them
+		dM, _ := json.Marshal(map[string]any{"truncated": true, "nextPageToken": nextPageToken})
+		content = append(content, TextContent{Type: "text", Text: string(dM)})
 	}
 
+	// the size of the result is the size of the rows as encoded above
+	auditInv.SetResult(rows, size)
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
 		Id:      id,
@@ -77,6 +77,7 @@ type CallToolRequest struct {
 	Params struct {
 		Name      string         `json:"name"`
 		Arguments map[string]any `json:"arguments,omitempty"`
+		Meta      map[string]any `json:"_meta,omitempty"`
 	} `json:"params,omitempty"`
 }
 
@@ -117,6 +118,23 @@ type TextContent struct {
 	Text string `json:"text"`
 }
 
+// ResourceLink is a link to a resource, such as a file the rows of a tool
+// were exported to, returned in the result of a tool call.
+type ResourceLink struct {
+	Annotated
+	Type string `json:"type"`
+	// The URI of the resource.
+	URI string `json:"uri"`
+	// The name of the resource.
+	Name string `json:"name"`
+	// A description of what the resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of the resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+	// The size of the resource in bytes, if known.
+	Size int64 `json:"size,omitempty"`
+}
+
 // The server's response to a tool call.
 //
 // Any errors that originate from the tool SHOULD be reported inside the result
@@ -129,9 +147,10 @@ type TextContent struct {
 // should be reported as an MCP error response.
 type CallToolResult struct {
 	jsonrpc.Result
-	// Could be either a TextContent, ImageContent, or EmbeddedResources
-	// For Toolbox, we will only be sending TextContent
-	Content []TextContent `json:"content"`
+	// Could be either a TextContent, ImageContent, ResourceLink, or
+	// EmbeddedResources. For Toolbox, we will only be sending TextContent and
+	// ResourceLink
+	Content []any `json:"content"`
 	// Whether the tool call ended in an error.
 	// If not set, this is assumed to be false (the call was successful).
 	//
@@ -179,3 +198,25 @@ type ToolAnnotations struct {
 	// Default: true
 	OpenWorldHint bool `json:"openWorldHint,omitempty"`
 }
+
+/* Elicitation */
+
+// ElicitRequestParams are the params of an elicitation/create request, sent
+// from the server to request information from the user through the client.
+type ElicitRequestParams struct {
+	// The message to present to the user.
+	Message string `json:"message"`
+	// A restricted subset of JSON Schema: an object with properties of
+	// primitive types only.
+	RequestedSchema map[string]any `json:"requestedSchema"`
+}
+
+// ElicitResult is the client's response to an elicitation/create request.
+type ElicitResult struct {
+	jsonrpc.Result
+	// The user action in response to the elicitation: "accept", "decline"
+	// or "cancel".
+	Action string `json:"action"`
+	// The submitted form data, only present when action is "accept".
+	Content map[string]any `json:"content,omitempty"`
+}
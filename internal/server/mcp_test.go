@@ -27,7 +27,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/googleapis/genai-toolbox/internal/audit"
+	"github.com/googleapis/genai-toolbox/internal/export"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/resultformat"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -742,6 +745,428 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 }
 
+func TestMcpToolsCallAudit(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	var buf bytes.Buffer
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets, withAuditor(t, &buf))
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	body := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call-tool2",
+		Request: jsonrpc.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{
+			"name":      "some_params",
+			"arguments": map[string]any{"param1": 1, "param2": 2},
+		},
+	}
+	reqMarshal, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+	_, _, err = runRequest(ts, http.MethodPost, "/tool2_only", bytes.NewBuffer(reqMarshal), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+
+	entries := auditEntries(t, buf.Bytes())
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.Transport != audit.TransportMCPHTTP || got.Toolset != "tool2_only" || got.Tool != "some_params" {
+		t.Errorf("unexpected audit entry: %+v", got)
+	}
+	wantParams := map[string]any{"param1": float64(1), "param2": audit.RedactedValue}
+	if !reflect.DeepEqual(got.Parameters, wantParams) {
+		t.Errorf("unexpected audit parameters: got %v, want %v", got.Parameters, wantParams)
+	}
+}
+
+func TestMcpToolsCallPagedResult(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool6}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	body := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call-paged-tool",
+		Request: jsonrpc.Request{
+			Method: "tools/call",
+		},
+		Params: map[string]any{
+			"name":      tool6.Name,
+			"arguments": map[string]any{},
+		},
+	}
+	reqMarshal, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+	_, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(respBody, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	want := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "tools-call-paged-tool",
+		"result": map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": `"row1"`},
+				map[string]any{"type": "text", "text": `"row2"`},
+				map[string]any{"type": "text", "text": `{"nextPageToken":"next-page","truncated":true}`},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected response: got %v, want %v", got, want)
+	}
+}
+
+// readEvent reads the data of the next event of an SSE stream.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]any {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read event: %s", err)
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		var event map[string]any
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("unable to decode event %q: %s", data, err)
+		}
+		return event
+	}
+}
+
+func TestMcpToolsCallResultFormat(t *testing.T) {
+	mockTools := []MockTool{tool1, tool7}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool7.Name] = withResultFormat(t, tool7, resultformat.Markdown)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		name   string
+		params map[string]any
+		want   string
+	}{
+		{
+			name:   "format of the tool",
+			params: map[string]any{"name": tool7.Name, "arguments": map[string]any{}},
+			want:   "| name | id |\n| --- | --- |\n| Alice | 1 |\n| Bob | 2 |\n",
+		},
+		{
+			name: "requested format",
+			params: map[string]any{
+				"name":      tool7.Name,
+				"arguments": map[string]any{},
+				"_meta":     map[string]any{"toolbox/resultFormat": "csv"},
+			},
+			want: "name,id\nAlice,1\nBob,2\n",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			body := jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call-formatted",
+				Request: jsonrpc.Request{Method: "tools/call"},
+				Params:  tc.params,
+			}
+			reqMarshal, err := json.Marshal(body)
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+			_, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(respBody, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			want := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call-formatted",
+				"result": map[string]any{
+					"content": []any{map[string]any{"type": "text", "text": tc.want}},
+				},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected response: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMcpToolsCallExport(t *testing.T) {
+	exportTool := MockTool{
+		Name: "export_tool",
+		result: export.Result{
+			URI:      "gs://my-bucket/toolbox-export-export_tool.csv",
+			Name:     "toolbox-export-export_tool.csv",
+			Format:   export.CSV,
+			MimeType: "text/csv",
+			Schema:   []export.Column{{Name: "id", Type: "int64"}},
+			RowCount: 2,
+			Size:     8,
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, exportTool})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	body := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call-export",
+		Request: jsonrpc.Request{Method: "tools/call"},
+		Params:  map[string]any{"name": exportTool.Name, "arguments": map[string]any{}},
+	}
+	reqMarshal, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+	_, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var got struct {
+		Result struct {
+			Content []map[string]any `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if len(got.Result.Content) != 2 {
+		t.Fatalf("unexpected content: %v", got.Result.Content)
+	}
+	wantLink := map[string]any{
+		"type":        "resource_link",
+		"uri":         "gs://my-bucket/toolbox-export-export_tool.csv",
+		"name":        "toolbox-export-export_tool.csv",
+		"description": `2 rows exported by tool "export_tool"`,
+		"mimeType":    "text/csv",
+		"size":        float64(8),
+	}
+	if !reflect.DeepEqual(got.Result.Content[0], wantLink) {
+		t.Errorf("unexpected resource link: got %v, want %v", got.Result.Content[0], wantLink)
+	}
+	text, _ := got.Result.Content[1]["text"].(string)
+	if !strings.Contains(text, `"schema":[{"name":"id","type":"int64"}]`) || !strings.Contains(text, `"rowCount":2`) {
+		t.Errorf("unexpected text content: %q", text)
+	}
+}
+
+func TestMcpToolsCallConfirmation(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	toolsMap[tool2.Name] = requireConfirmation(t, tool2)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	callBody, err := json.Marshal(jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-call-confirm",
+		Request: jsonrpc.Request{Method: "tools/call"},
+		Params: map[string]any{
+			"name":      tool2.Name,
+			"arguments": map[string]any{"param1": 1, "param2": 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+
+	// the client declares elicitation, and identifies itself by the session
+	// of its initialize request
+	initBody, err := json.Marshal(jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "initialize",
+		Request: jsonrpc.Request{Method: "initialize"},
+		Params: map[string]any{
+			"protocolVersion": protocolVersion20250618,
+			"capabilities":    map[string]any{"elicitation": map[string]any{}},
+			"clientInfo":      map[string]any{"name": "test", "version": "1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	initResp, _, err := runRequest(ts, http.MethodPost, "/", bytes.NewReader(initBody), map[string]string{"MCP-Protocol-Version": protocolVersion20250618})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	sessionId := initResp.Header.Get("Mcp-Session-Id")
+	if sessionId == "" {
+		t.Fatalf("expected a session for a client declaring elicitation")
+	}
+
+	// call invokes the tool, answering the elicitation with result, and
+	// returns the result of the invocation.
+	call := func(t *testing.T, result map[string]any) map[string]any {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/", bytes.NewReader(callBody))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set("MCP-Protocol-Version", protocolVersion20250618)
+		req.Header.Set("Mcp-Session-Id", sessionId)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("unexpected content type: %q", ct)
+		}
+		stream := bufio.NewReader(resp.Body)
+
+		elicitation := readEvent(t, stream)
+		if elicitation["method"] != "elicitation/create" {
+			t.Fatalf("expected elicitation request, got %v", elicitation)
+		}
+		params, _ := elicitation["params"].(map[string]any)
+		if params["message"] != "Invoke some_params with param1=1?" {
+			t.Errorf("unexpected elicitation message: %v", params["message"])
+		}
+
+		answer, err := json.Marshal(map[string]any{"jsonrpc": jsonrpcVersion, "id": elicitation["id"], "result": result})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of answer")
+		}
+		header := map[string]string{"MCP-Protocol-Version": protocolVersion20250618}
+		answerResp, _, err := runRequest(ts, http.MethodPost, "/", bytes.NewReader(answer), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if answerResp.StatusCode != http.StatusAccepted {
+			t.Errorf("unexpected status code for answer: got %d, want %d", answerResp.StatusCode, http.StatusAccepted)
+		}
+
+		res := readEvent(t, stream)
+		if res["id"] != "tools-call-confirm" {
+			t.Fatalf("unexpected response: %v", res)
+		}
+		result, _ = res["result"].(map[string]any)
+		return result
+	}
+
+	t.Run("accept", func(t *testing.T) {
+		got := call(t, map[string]any{"action": "accept", "content": map[string]any{"confirm": true}})
+		want := map[string]any{"content": []any{map[string]any{"type": "text", "text": `"some_params"`}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected result: got %v, want %v", got, want)
+		}
+	})
+	for _, answer := range []map[string]any{
+		{"action": "decline"},
+		{"action": "accept", "content": map[string]any{"confirm": false}},
+	} {
+		t.Run(fmt.Sprintf("decline %v", answer), func(t *testing.T) {
+			got := call(t, answer)
+			if got["isError"] != true || !strings.Contains(fmt.Sprint(got["content"]), "declined") {
+				t.Errorf("expected declined error, got %v", got)
+			}
+		})
+	}
+
+	// clients that cannot answer an elicitation fail at once, with a token
+	// confirming the invocation over the REST API
+	for _, tc := range []struct {
+		desc   string
+		header map[string]string
+	}{
+		{
+			desc:   "no stream",
+			header: map[string]string{"MCP-Protocol-Version": protocolVersion20250618, "Mcp-Session-Id": sessionId},
+		},
+		{
+			desc:   "no elicitation capability",
+			header: map[string]string{"MCP-Protocol-Version": protocolVersion20250618, "Accept": "application/json, text/event-stream"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewReader(callBody), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body %q: %s", body, err)
+			}
+			result, _ := got["result"].(map[string]any)
+			content := fmt.Sprint(result["content"])
+			if result["isError"] != true || !strings.Contains(content, "confirmation required") || !strings.Contains(content, "Toolbox-Confirmation-Token") {
+				t.Errorf("expected confirmation required error, got %v", got)
+			}
+		})
+	}
+}
+
+func TestMcpToolsCallTimeout(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool8})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for _, version := range []string{"2024-11-05", "2025-03-26", protocolVersion20250618} {
+		t.Run(version, func(t *testing.T) {
+			body, err := json.Marshal(jsonrpc.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call-timeout",
+				Request: jsonrpc.Request{Method: "tools/call"},
+				Params:  map[string]any{"name": tool8.Name, "arguments": map[string]any{}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			header := map[string]string{"MCP-Protocol-Version": version}
+			_, respBody, err := runRequest(ts, http.MethodPost, "/", bytes.NewReader(body), header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(respBody, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			result, _ := got["result"].(map[string]any)
+			if result["isError"] != true || !strings.Contains(fmt.Sprint(result["content"]), `tool "timeout_tool" timed out after 1s`) {
+				t.Errorf("expected timeout error, got %v", got)
+			}
+		})
+	}
+}
+
 func TestInvalidProtocolVersionHeader(t *testing.T) {
 	toolsMap, toolsets := map[string]tools.Tool{}, map[string]tools.Toolset{}
 	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
@@ -937,7 +1362,7 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unable to initialize logger: %s", err)
 	}
 
-	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, "toolbox")
+	otelShutdown, err := telemetry.SetupOTel(ctx, fakeVersionString, "", false, false, "toolbox")
 	if err != nil {
 		t.Fatalf("unable to setup otel: %s", err)
 	}
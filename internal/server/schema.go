@@ -0,0 +1,40 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/schema"
+)
+
+// toolsFileSchema generates the JSON Schema of the tools file once, since the
+// registered kinds do not change after startup.
+var toolsFileSchema = sync.OnceValues(func() (schema.Schema, error) {
+	return schema.Generate(context.Background())
+})
+
+// schemaHandler serves the JSON Schema of the tools file.
+func schemaHandler(w http.ResponseWriter, r *http.Request) {
+	s, err := toolsFileSchema()
+	if err != nil {
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	render.JSON(w, r, s)
+}
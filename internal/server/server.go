@@ -16,19 +16,27 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"reflect"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v2"
+	"github.com/googleapis/genai-toolbox/internal/audit"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/cache"
+	"github.com/googleapis/genai-toolbox/internal/confirmation"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/policy"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -46,7 +54,47 @@ type Server struct {
 	logger          log.Logger
 	instrumentation *telemetry.Instrumentation
 	sseManager      *sseManager
+	clientRequests  clientRequests
+	capabilities    clientCapabilities
+	confirmations   *confirmation.Signer
+	auditor         *audit.Auditor
 	ResourceMgr     *ResourceManager
+
+	readinessSources []string
+	readinessTimeout time.Duration
+
+	// adminToken authenticates requests to the admin API, which is disabled
+	// if it is empty.
+	adminToken string
+	// reload reloads the tools files, when requested through the admin API.
+	reload func(context.Context) error
+	// draining is set once the server stops accepting tool invocations.
+	draining atomic.Bool
+}
+
+// drainTimeout bounds how long sources replaced by a reload wait for the
+// requests using them to complete before they are closed.
+const drainTimeout = 30 * time.Second
+
+// generation tracks the in-flight requests using a set of resources.
+type generation struct {
+	wg sync.WaitGroup
+}
+
+// wait blocks until every request of the generation has completed, or ctx is
+// done.
+func (g *generation) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // ResourceManager contains available resources for the server. Should be initialized with NewResourceManager().
@@ -56,6 +104,30 @@ type ResourceManager struct {
 	authServices map[string]auth.AuthService
 	tools        map[string]tools.Tool
 	toolsets     map[string]tools.Toolset
+	// sourceConfigs are the configs sources were initialized from, used to
+	// reuse unchanged sources on reload.
+	sourceConfigs SourceConfigs
+	// toolConfigs, toolsetConfigs and origins describe the configuration of
+	// the resources, for the admin API.
+	toolConfigs    ToolConfigs
+	toolsetConfigs ToolsetConfigs
+	origins        ConfigOrigins
+	// disabled holds the names of the tools disabled at runtime. Disabled
+	// tools stay disabled across reloads.
+	disabled map[string]bool
+	current  *generation
+
+	// reloadMu serializes reloads, and closing tracks sources being closed
+	// after a reload.
+	reloadMu   sync.Mutex
+	closing    sync.WaitGroup
+	lastReload *ReloadStatus
+}
+
+// ReloadStatus is the outcome of a reload of the tools file.
+type ReloadStatus struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error,omitempty"`
 }
 
 func NewResourceManager(
@@ -69,6 +141,8 @@ func NewResourceManager(
 		authServices: authServicesMap,
 		tools:        toolsMap,
 		toolsets:     toolsetsMap,
+		disabled:     make(map[string]bool),
+		current:      &generation{},
 	}
 
 	return resourceMgr
@@ -88,18 +162,65 @@ func (r *ResourceManager) GetAuthService(authServiceName string) (auth.AuthServi
 	return authService, ok
 }
 
+// GetTool returns the tool with the given name, unless it is disabled.
 func (r *ResourceManager) GetTool(toolName string) (tools.Tool, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if r.disabled[toolName] {
+		return nil, false
+	}
 	tool, ok := r.tools[toolName]
 	return tool, ok
 }
 
+// GetToolset returns the toolset with the given name, without its disabled
+// tools.
 func (r *ResourceManager) GetToolset(toolsetName string) (tools.Toolset, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	toolset, ok := r.toolsets[toolsetName]
-	return toolset, ok
+	if !ok || len(r.disabled) == 0 {
+		return toolset, ok
+	}
+	filtered := toolset
+	filtered.Manifest.ToolsManifest = make(map[string]tools.Manifest, len(toolset.Manifest.ToolsManifest))
+	for name, m := range toolset.Manifest.ToolsManifest {
+		if !r.disabled[name] {
+			filtered.Manifest.ToolsManifest[name] = m
+		}
+	}
+	filtered.McpManifest = nil
+	for _, m := range toolset.McpManifest {
+		if !r.disabled[m.Name] {
+			filtered.McpManifest = append(filtered.McpManifest, m)
+		}
+	}
+	return filtered, true
+}
+
+// SetToolEnabled enables or disables the tool with the given name at runtime.
+// It returns false if no such tool exists, unless an already disabled tool is
+// enabled.
+func (r *ResourceManager) SetToolEnabled(toolName string, enabled bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[toolName]; !ok && !(enabled && r.disabled[toolName]) {
+		return false
+	}
+	if enabled {
+		delete(r.disabled, toolName)
+	} else {
+		r.disabled[toolName] = true
+	}
+	return true
+}
+
+// IsToolDisabled reports whether the tool with the given name was disabled
+// at runtime.
+func (r *ResourceManager) IsToolDisabled(toolName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.disabled[toolName]
 }
 
 func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset) {
@@ -109,6 +230,158 @@ func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, aut
 	r.authServices = authServicesMap
 	r.tools = toolsMap
 	r.toolsets = toolsetsMap
+	// the configs of the new sources are unknown, so none can be reused
+	r.sourceConfigs = nil
+	r.toolConfigs, r.toolsetConfigs, r.origins = nil, nil, ConfigOrigins{}
+}
+
+// setConfigs records the configuration the current resources were
+// initialized from.
+func (r *ResourceManager) setConfigs(cfg ServerConfig) {
+	r.sourceConfigs = cfg.SourceConfigs
+	r.toolConfigs = cfg.ToolConfigs
+	r.toolsetConfigs = cfg.ToolsetConfigs
+	r.origins = cfg.Origins
+}
+
+// RecordReload records the outcome of a reload. Reload records its own
+// outcome; RecordReload is used for reloads failing before that, such as
+// when the tools file cannot be parsed.
+func (r *ResourceManager) RecordReload(err error) {
+	status := &ReloadStatus{Time: time.Now().UTC()}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastReload = status
+}
+
+// LastReload returns the outcome of the last reload, and false if there has
+// been none.
+func (r *ResourceManager) LastReload() (ReloadStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.lastReload == nil {
+		return ReloadStatus{}, false
+	}
+	return *r.lastReload, true
+}
+
+// GetSourcesMap returns the current sources.
+func (r *ResourceManager) GetSourcesMap() map[string]sources.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sources
+}
+
+// Acquire marks the start of a request using the current resources, and
+// returns a function marking its end. Sources replaced by Reload are closed
+// once the requests that acquired them have ended.
+func (r *ResourceManager) Acquire() (release func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	g := r.current
+	g.wg.Add(1)
+	return g.wg.Done
+}
+
+// Reload initializes the resources of cfg and replaces the current resources
+// with them. Sources whose configs are unchanged are reused rather than
+// reinitialized. Sources that were removed or changed are closed in the
+// background once the requests using them have completed.
+func (r *ResourceManager) Reload(ctx context.Context, cfg ServerConfig) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	r.mu.RLock()
+	oldSources, oldConfigs := r.sources, r.sourceConfigs
+	r.mu.RUnlock()
+
+	reuse := make(map[string]sources.Source)
+	for name, sc := range cfg.SourceConfigs {
+		old, ok := oldConfigs[name]
+		if !ok || !reflect.DeepEqual(old, sc) {
+			continue
+		}
+		if src, ok := oldSources[name]; ok {
+			reuse[name] = src
+		}
+	}
+
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := initializeConfigs(ctx, cfg, reuse)
+	r.RecordReload(err)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.sources = sourcesMap
+	r.authServices = authServicesMap
+	r.tools = toolsMap
+	r.toolsets = toolsetsMap
+	r.setConfigs(cfg)
+	prev := r.current
+	r.current = &generation{}
+	r.mu.Unlock()
+
+	stale := make(map[string]sources.Source)
+	for name, src := range oldSources {
+		if _, ok := reuse[name]; !ok {
+			stale[name] = src
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	r.closing.Add(1)
+	go func() {
+		defer r.closing.Done()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+		defer cancel()
+		l, err := util.LoggerFromContext(ctx)
+		if err != nil {
+			panic(err)
+		}
+		if err := prev.wait(ctx); err != nil {
+			l.WarnContext(ctx, fmt.Sprintf("closing %d replaced sources with requests still in flight", len(stale)))
+		}
+		if err := closeSources(ctx, stale); err != nil {
+			l.WarnContext(ctx, err.Error())
+		}
+	}()
+	return nil
+}
+
+// Close closes every source once the requests using them have completed or
+// ctx is done, including sources replaced by earlier reloads.
+func (r *ResourceManager) Close(ctx context.Context) error {
+	r.mu.RLock()
+	current, g := r.sources, r.current
+	r.mu.RUnlock()
+	// requests still in flight once ctx is done are cut off
+	_ = g.wait(ctx)
+	closed := make(chan struct{})
+	go func() {
+		r.closing.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+	}
+	return closeSources(ctx, current)
+}
+
+// closeSources closes the given sources and joins their errors.
+func closeSources(ctx context.Context, sourcesMap map[string]sources.Source) error {
+	var errs error
+	for name, src := range sourcesMap {
+		if err := sources.Close(ctx, src); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("unable to close source %q: %w", name, err))
+		}
+	}
+	return errs
 }
 
 func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
@@ -117,10 +390,20 @@ func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
 	return r.authServices
 }
 
+// GetToolsMap returns the current tools, without the disabled tools.
 func (r *ResourceManager) GetToolsMap() map[string]tools.Tool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.tools
+	if len(r.disabled) == 0 {
+		return r.tools
+	}
+	toolsMap := make(map[string]tools.Tool, len(r.tools))
+	for name, t := range r.tools {
+		if !r.disabled[name] {
+			toolsMap[name] = t
+		}
+	}
+	return toolsMap
 }
 
 func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
@@ -129,6 +412,19 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]tools.Tool,
 	map[string]tools.Toolset,
 	error,
+) {
+	return initializeConfigs(ctx, cfg, nil)
+}
+
+// initializeConfigs initializes the resources of cfg, using the sources in
+// reuse instead of initializing their configs. If an error is returned, the
+// sources it initialized are closed.
+func initializeConfigs(ctx context.Context, cfg ServerConfig, reuse map[string]sources.Source) (
+	_ map[string]sources.Source,
+	_ map[string]auth.AuthService,
+	_ map[string]tools.Tool,
+	_ map[string]tools.Toolset,
+	err error,
 ) {
 	ctx = util.WithUserAgent(ctx, cfg.Version)
 	instrumentation, err := util.InstrumentationFromContext(ctx)
@@ -143,25 +439,26 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 
 	// initialize and validate the sources from configs
 	sourcesMap := make(map[string]sources.Source)
-	for name, sc := range cfg.SourceConfigs {
-		s, err := func() (sources.Source, error) {
-			childCtx, span := instrumentation.Tracer.Start(
-				ctx,
-				"toolbox/server/source/init",
-				trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
-				trace.WithAttributes(attribute.String("source_name", name)),
-			)
-			defer span.End()
-			s, err := sc.Initialize(childCtx, instrumentation.Tracer)
-			if err != nil {
-				return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
+	initialized := make(map[string]sources.Source)
+	defer func() {
+		if err != nil {
+			if cErr := closeSources(ctx, initialized); cErr != nil {
+				l.WarnContext(ctx, cErr.Error())
 			}
-			return s, nil
-		}()
+		}
+	}()
+	for name, sc := range cfg.SourceConfigs {
+		if s, ok := reuse[name]; ok {
+			l.DebugContext(ctx, fmt.Sprintf("reusing unchanged source %q", name))
+			sourcesMap[name] = s
+			continue
+		}
+		s, err := initializeSource(ctx, instrumentation, name, sc)
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
 		sourcesMap[name] = s
+		initialized[name] = s
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources.", len(sourcesMap)))
 
@@ -191,25 +488,14 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 
 	// initialize and validate the tools from configs
 	toolsMap := make(map[string]tools.Tool)
-	for name, tc := range cfg.ToolConfigs {
-		t, err := func() (tools.Tool, error) {
-			_, span := instrumentation.Tracer.Start(
-				ctx,
-				"toolbox/server/tool/init",
-				trace.WithAttributes(attribute.String("tool_kind", tc.ToolConfigKind())),
-				trace.WithAttributes(attribute.String("tool_name", name)),
-			)
-			defer span.End()
-			t, err := tc.Initialize(sourcesMap)
-			if err != nil {
-				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
-			}
-			return t, nil
-		}()
-		if err != nil {
+	for name := range cfg.ToolConfigs {
+		if _, err := InitializeTool(ctx, name, cfg.ToolConfigs, cfg.SourceConfigs, sourcesMap, toolsMap); err != nil {
 			return nil, nil, nil, nil, err
 		}
-		toolsMap[name] = t
+	}
+	toolMetadata := make(map[string]telemetry.ToolMetadata)
+	for name, tc := range cfg.ToolConfigs {
+		toolMetadata[name] = telemetry.ToolMetadata{Kind: tc.ToolConfigKind(), Source: toolSourceName(tc)}
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
 
@@ -246,9 +532,160 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(toolsetsMap)))
 
+	pools := make(map[string]telemetry.Pool)
+	for name, src := range sourcesMap {
+		if p, ok := src.(telemetry.Pool); ok {
+			pools[name] = p
+		}
+	}
+	instrumentation.SetTools(toolMetadata)
+	instrumentation.SetPools(pools)
+
 	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
 }
 
+// initializeSource initializes the source name from its config.
+func initializeSource(ctx context.Context, instrumentation *telemetry.Instrumentation, name string, sc sources.SourceConfig) (sources.Source, error) {
+	childCtx, span := instrumentation.Tracer.Start(
+		ctx,
+		"toolbox/server/source/init",
+		trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
+		trace.WithAttributes(attribute.String("source_name", name)),
+	)
+	defer span.End()
+	s, err := sc.Initialize(childCtx, instrumentation.Tracer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
+	}
+	return s, nil
+}
+
+// InitializeTool initializes the tool name of toolConfigs into toolsMap,
+// initializing composed tools after the tools they invoke. The data policy of
+// the source of a tool applies to the tool. The sources used by the tools that
+// are missing from srcs are initialized from sourceConfigs and added to srcs.
+func InitializeTool(ctx context.Context, name string, toolConfigs ToolConfigs, sourceConfigs SourceConfigs, srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	instrumentation, err := util.InstrumentationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := toolConfigs[name]; !ok {
+		return nil, fmt.Errorf("tool %q does not exist", name)
+	}
+
+	initializing := make(map[string]bool)
+	var initTool func(name string, tc tools.ToolConfig) error
+	initTool = func(name string, tc tools.ToolConfig) error {
+		if _, ok := toolsMap[name]; ok {
+			return nil
+		}
+		if initializing[name] {
+			return fmt.Errorf("unable to initialize tool %q: it invokes itself", name)
+		}
+		initializing[name] = true
+		defer delete(initializing, name)
+
+		for _, srcName := range ToolSourceNames(tc) {
+			sc, ok := sourceConfigs[srcName]
+			if _, initialized := srcs[srcName]; initialized || !ok {
+				// missing sources are reported by the tool
+				continue
+			}
+			s, err := initializeSource(ctx, instrumentation, srcName, sc)
+			if err != nil {
+				return err
+			}
+			srcs[srcName] = s
+		}
+
+		// the data policy of the source of the tool applies to the tool
+		if sc, ok := sourceConfigs[toolSourceName(tc)].(policy.SourceConfig); ok {
+			tc = policy.ToolConfig{ToolConfig: tc, Name: name, Policy: sc.Policy}
+		}
+
+		composed, isComposed := tc.(tools.ComposedToolConfig)
+		if isComposed {
+			for _, dep := range composed.ToolNames() {
+				depConfig, ok := toolConfigs[dep]
+				if !ok {
+					return fmt.Errorf("unable to initialize tool %q: invoked tool %q does not exist", name, dep)
+				}
+				if err := initTool(dep, depConfig); err != nil {
+					return err
+				}
+			}
+		}
+		_, span := instrumentation.Tracer.Start(
+			ctx,
+			"toolbox/server/tool/init",
+			trace.WithAttributes(attribute.String("tool_kind", tc.ToolConfigKind())),
+			trace.WithAttributes(attribute.String("tool_name", name)),
+		)
+		defer span.End()
+		var t tools.Tool
+		var err error
+		if isComposed {
+			t, err = composed.InitializeWithTools(srcs, toolsMap)
+		} else {
+			t, err = tc.Initialize(srcs)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to initialize tool %q: %w", name, err)
+		}
+		toolsMap[name] = t
+		return nil
+	}
+	if err := initTool(name, toolConfigs[name]); err != nil {
+		return nil, err
+	}
+	return toolsMap[name], nil
+}
+
+// ToolSourceNames returns the names of the sources used by a tool: the source
+// it invokes, and the source its cache stores results in.
+func ToolSourceNames(tc tools.ToolConfig) []string {
+	var names []string
+	if name := toolSourceName(tc); name != "" {
+		names = append(names, name)
+	}
+	for tc != nil {
+		if c, ok := tc.(cache.ToolConfig); ok && c.Cache.Source != "" {
+			names = append(names, c.Cache.Source)
+		}
+		u, ok := tc.(interface{ Unwrap() tools.ToolConfig })
+		if !ok {
+			break
+		}
+		tc = u.Unwrap()
+	}
+	return names
+}
+
+// toolSourceName returns the name of the source used by a tool, or "" if the
+// tool does not use one. Tool configs name their source in a Source field,
+// which may be promoted from an embedded tool config.
+func toolSourceName(tc tools.ToolConfig) string {
+	v := reflect.ValueOf(tc)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("Source"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	if f := v.FieldByName("ToolConfig"); f.IsValid() && f.CanInterface() {
+		if embedded, ok := f.Interface().(tools.ToolConfig); ok {
+			return toolSourceName(embedded)
+		}
+	}
+	return ""
+}
+
 // NewServer returns a Server object based on provided Config.
 func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	instrumentation, err := util.InstrumentationFromContext(ctx)
@@ -308,7 +745,23 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	sseManager := newSseManager(ctx)
 
+	// if using stdio, entries for stdout are written to stderr instead
+	auditOut := io.Writer(os.Stdout)
+	if cfg.Stdio {
+		auditOut = os.Stderr
+	}
+	auditor, err := audit.New(ctx, cfg.Audit, auditOut)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize audit log: %w", err)
+	}
+
 	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	resourceManager.setConfigs(cfg)
+
+	confirmations, err := confirmation.NewSigner()
+	if err != nil {
+		return nil, err
+	}
 
 	s := &Server{
 		version:         cfg.Version,
@@ -317,8 +770,15 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		logger:          l,
 		instrumentation: instrumentation,
 		sseManager:      sseManager,
+		confirmations:   confirmations,
+		auditor:         auditor,
 		ResourceMgr:     resourceManager,
+
+		readinessSources: cfg.ReadinessSources,
+		readinessTimeout: cfg.ReadinessTimeout,
+		adminToken:       cfg.AdminToken,
 	}
+	instrumentation.SetHealthCheck(s.healthStatus)
 	// control plane
 	apiR, err := apiRouter(s)
 	if err != nil {
@@ -330,6 +790,13 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		return nil, err
 	}
 	r.Mount("/mcp", mcpR)
+	if cfg.AdminToken != "" {
+		adminR, err := adminRouter(s)
+		if err != nil {
+			return nil, err
+		}
+		r.Mount("/admin", adminR)
+	}
 	if cfg.UI {
 		webR, err := webRouter()
 		if err != nil {
@@ -337,10 +804,22 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		}
 		r.Mount("/ui", webR)
 	}
+	if cfg.TelemetryPrometheus {
+		h := telemetry.PrometheusHandler()
+		if h == nil {
+			return nil, fmt.Errorf("unable to serve metrics: prometheus exporter is not set up")
+		}
+		r.Handle("/metrics", h)
+	}
 	// default endpoint for validating server is running
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
 	})
+	// liveness and readiness probes
+	r.Get("/healthz", healthzHandler)
+	r.Get("/readyz", func(w http.ResponseWriter, r *http.Request) { readyzHandler(s, w, r) })
+	// JSON Schema of the tools file, for editors
+	r.Get("/schema", schemaHandler)
 
 	return s, nil
 }
@@ -378,5 +857,12 @@ func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writ
 // connections. It uses http.Server.Shutdown() and has the same functionality.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
-	return s.srv.Shutdown(ctx)
+	err := s.srv.Shutdown(ctx)
+	if auditErr := s.auditor.Close(ctx); auditErr != nil {
+		s.logger.WarnContext(ctx, fmt.Sprintf("unable to close audit log: %s", auditErr))
+	}
+	if closeErr := s.ResourceMgr.Close(ctx); closeErr != nil {
+		s.logger.WarnContext(ctx, closeErr.Error())
+	}
+	return err
 }
@@ -21,7 +21,9 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/auth"
@@ -32,7 +34,9 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestServe(t *testing.T) {
@@ -46,7 +50,7 @@ func TestServe(t *testing.T) {
 		Port:    port,
 	}
 
-	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, "toolbox")
+	otelShutdown, err := telemetry.SetupOTel(ctx, "0.0.0", "", false, false, "toolbox")
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
@@ -172,3 +176,114 @@ func TestUpdateServer(t *testing.T) {
 		t.Errorf("error updating server, toolset (-want +got):\n%s", diff)
 	}
 }
+
+// fakeSourceConfig initializes fakeSources, counting how many are open.
+type fakeSourceConfig struct {
+	Host string
+	open *atomic.Int32
+}
+
+func (c fakeSourceConfig) SourceConfigKind() string { return "fake" }
+
+func (c fakeSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	c.open.Add(1)
+	return &fakeSource{open: c.open}, nil
+}
+
+type fakeSource struct {
+	open   *atomic.Int32
+	closed atomic.Bool
+}
+
+func (s *fakeSource) SourceKind() string { return "fake" }
+
+func (s *fakeSource) Close(context.Context) error {
+	if s.closed.Swap(true) {
+		return fmt.Errorf("source closed twice")
+	}
+	s.open.Add(-1)
+	return nil
+}
+
+func TestReload(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	var open atomic.Int32
+	config := func(sourceConfigs server.SourceConfigs, toolConfigs server.ToolConfigs) server.ServerConfig {
+		return server.ServerConfig{Version: "0.0.0", SourceConfigs: sourceConfigs, ToolConfigs: toolConfigs}
+	}
+	r := server.NewResourceManager(nil, nil, nil, nil)
+	err = r.Reload(ctx, config(server.SourceConfigs{
+		"unchanged": fakeSourceConfig{Host: "a", open: &open},
+		"changed":   fakeSourceConfig{Host: "b", open: &open},
+	}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := open.Load(); got != 2 {
+		t.Fatalf("expected 2 open sources, got %d", got)
+	}
+	unchanged, _ := r.GetSource("unchanged")
+	changed, _ := r.GetSource("changed")
+
+	// an in-flight request keeps replaced sources open
+	release := r.Acquire()
+	err = r.Reload(ctx, config(server.SourceConfigs{
+		"unchanged": fakeSourceConfig{Host: "a", open: &open},
+		"changed":   fakeSourceConfig{Host: "c", open: &open},
+	}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, _ := r.GetSource("unchanged"); got != unchanged {
+		t.Errorf("expected unchanged source to be reused")
+	}
+	if got, _ := r.GetSource("changed"); got == changed {
+		t.Errorf("expected changed source to be reinitialized")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if changed.(*fakeSource).closed.Load() {
+		t.Fatalf("expected replaced source to stay open while a request is in flight")
+	}
+	release()
+	deadline := time.Now().Add(time.Second)
+	for !changed.(*fakeSource).closed.Load() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected replaced source to be closed once the request completed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// sources initialized by a failed reload are closed, and the current
+	// resources are kept
+	err = r.Reload(ctx, config(server.SourceConfigs{
+		"unchanged": fakeSourceConfig{Host: "a", open: &open},
+		"new":       fakeSourceConfig{Host: "d", open: &open},
+	}, server.ToolConfigs{
+		"tool": postgressql.Config{Name: "tool", Kind: "postgres-sql", Source: "missing"},
+	}))
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if got := open.Load(); got != 2 {
+		t.Errorf("expected 2 open sources after a failed reload, got %d", got)
+	}
+	if _, ok := r.GetSource("new"); ok {
+		t.Errorf("expected failed reload not to be applied")
+	}
+
+	if err := r.Close(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := open.Load(); got != 0 {
+		t.Errorf("expected all sources to be closed, got %d open", got)
+	}
+}
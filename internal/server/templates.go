@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+const (
+	// extendsKey names the tool or template a tool or template extends.
+	extendsKey = "extends"
+	// parameterSetKey references a parameter set from a list of parameters.
+	parameterSetKey = "parameterSet"
+)
+
+// yamlNode is a YAML value whose decoding is delayed, and which keeps its
+// position in the tools file for error messages.
+type yamlNode struct {
+	node ast.Node
+}
+
+// validate interface
+var _ yaml.NodeUnmarshalerContext = &yamlNode{}
+
+func (n *yamlNode) UnmarshalYAML(ctx context.Context, node ast.Node) error {
+	n.node = node
+	return nil
+}
+
+// line returns the line of the value in the tools file.
+func (n yamlNode) line() int {
+	if n.node == nil || n.node.GetToken() == nil {
+		return 0
+	}
+	return n.node.GetToken().Position.Line
+}
+
+func (n yamlNode) decode(v any) error {
+	return yaml.NodeToValue(n.node, v)
+}
+
+// ToolTemplates are named partial tool definitions, which tools extend with
+// `extends: <template>`.
+type ToolTemplates map[string]yamlNode
+
+// ParameterSets are named lists of parameters, which lists of parameters
+// reference with `- parameterSet: <name>`.
+type ParameterSets map[string]yamlNode
+
+// ToolDefinitions are the reusable definitions of a tools file. They are
+// resolved while parsing the tools of the same file.
+type ToolDefinitions struct {
+	Templates     ToolTemplates `yaml:"templates"`
+	ParameterSets ParameterSets `yaml:"parameterSets"`
+	// File is the name of the tools file, which prefixes the positions of
+	// errors. It is not decoded.
+	File string `yaml:"-"`
+}
+
+type toolDefinitionsKey struct{}
+
+// WithToolDefinitions adds the reusable definitions of a tools file into the
+// context, to resolve them while parsing its ToolConfigs.
+func WithToolDefinitions(ctx context.Context, defs ToolDefinitions) context.Context {
+	return context.WithValue(ctx, toolDefinitionsKey{}, defs)
+}
+
+func toolDefinitionsFromContext(ctx context.Context) ToolDefinitions {
+	defs, _ := ctx.Value(toolDefinitionsKey{}).(ToolDefinitions)
+	return defs
+}
+
+// toolResolver resolves the `extends` and `parameterSet` references of the
+// tools of a tools file.
+type toolResolver struct {
+	tools    map[string]yamlNode
+	defs     ToolDefinitions
+	resolved map[string]map[string]any
+	params   map[string][]any
+	// stack holds the definitions being resolved, to detect cycles.
+	stack []string
+}
+
+func newToolResolver(tools map[string]yamlNode, defs ToolDefinitions) *toolResolver {
+	return &toolResolver{
+		tools:    tools,
+		defs:     defs,
+		resolved: make(map[string]map[string]any),
+		params:   make(map[string][]any),
+	}
+}
+
+// pos returns the position of n in the tools file, for error messages.
+func (r *toolResolver) pos(n yamlNode) string {
+	if r.defs.File == "" {
+		return fmt.Sprintf("line %d", n.line())
+	}
+	return fmt.Sprintf("%s:%d", r.defs.File, n.line())
+}
+
+// resolveTool returns the definition of the tool name, merged with the
+// definitions it extends and with its parameter sets expanded. The returned
+// map may be modified by the caller.
+func (r *toolResolver) resolveTool(name string) (map[string]any, error) {
+	v, err := r.resolve("tool", name, r.tools[name])
+	if err != nil {
+		return nil, err
+	}
+	return maps.Clone(v), nil
+}
+
+// resolve returns the definition of the tool or template name. The fields of
+// a definition replace the fields of the same name of the definition it
+// extends. Templates are looked up before tools.
+func (r *toolResolver) resolve(resource, name string, n yamlNode) (map[string]any, error) {
+	key := fmt.Sprintf("%s %q", resource, name)
+	if v, ok := r.resolved[key]; ok {
+		return v, nil
+	}
+	if i := slices.Index(r.stack, key); i >= 0 {
+		return nil, fmt.Errorf("%s: %s extends itself: %s", r.pos(n), key, strings.Join(slices.Concat(r.stack[i:], []string{key}), " -> "))
+	}
+	r.stack = append(r.stack, key)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	var v map[string]any
+	if err := n.decode(&v); err != nil {
+		return nil, fmt.Errorf("%s: unable to unmarshal %s: %w", r.pos(n), key, err)
+	}
+	if v == nil {
+		v = make(map[string]any)
+	}
+	if err := r.expandParameterSets(key, n, v); err != nil {
+		return nil, err
+	}
+
+	if ext, ok := v[extendsKey]; ok {
+		parentName, ok := ext.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid 'extends' field for %s (must be a string)", r.pos(n), key)
+		}
+		var parent map[string]any
+		var err error
+		if p, ok := r.defs.Templates[parentName]; ok {
+			parent, err = r.resolve("template", parentName, p)
+		} else if p, ok := r.tools[parentName]; ok {
+			parent, err = r.resolve("tool", parentName, p)
+		} else {
+			return nil, fmt.Errorf("%s: %s extends %q, which is not a template or tool", r.pos(n), key, parentName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		merged := maps.Clone(parent)
+		delete(v, extendsKey)
+		maps.Copy(merged, v)
+		v = merged
+	}
+	r.resolved[key] = v
+	return v, nil
+}
+
+// expandParameterSets replaces the `parameterSet` references of the lists of
+// the definition v by the parameters of the referenced sets.
+func (r *toolResolver) expandParameterSets(key string, n yamlNode, v map[string]any) error {
+	for field, value := range v {
+		list, ok := value.([]any)
+		if !ok {
+			continue
+		}
+		expanded, err := r.expandList(key, n, list)
+		if err != nil {
+			return err
+		}
+		v[field] = expanded
+	}
+	return nil
+}
+
+func (r *toolResolver) expandList(key string, n yamlNode, list []any) ([]any, error) {
+	var expanded []any
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok || m[parameterSetKey] == nil {
+			expanded = append(expanded, item)
+			continue
+		}
+		if len(m) != 1 {
+			return nil, fmt.Errorf("%s: a 'parameterSet' reference of %s must not have other fields", r.pos(n), key)
+		}
+		name, ok := m[parameterSetKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid 'parameterSet' reference of %s (must be a string)", r.pos(n), key)
+		}
+		params, err := r.parameterSet(key, n, name)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, params...)
+	}
+	return expanded, nil
+}
+
+// parameterSet returns the parameters of the parameter set name, referenced
+// by the definition key.
+func (r *toolResolver) parameterSet(key string, n yamlNode, name string) ([]any, error) {
+	setKey := fmt.Sprintf("parameterSet %q", name)
+	if params, ok := r.params[setKey]; ok {
+		return params, nil
+	}
+	set, ok := r.defs.ParameterSets[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s references %s, which does not exist", r.pos(n), key, setKey)
+	}
+	if i := slices.Index(r.stack, setKey); i >= 0 {
+		return nil, fmt.Errorf("%s: %s references itself: %s", r.pos(set), setKey, strings.Join(slices.Concat(r.stack[i:], []string{setKey}), " -> "))
+	}
+	r.stack = append(r.stack, setKey)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	var list []any
+	if err := set.decode(&list); err != nil {
+		return nil, fmt.Errorf("%s: unable to unmarshal %s (must be a list of parameters): %w", r.pos(set), setKey, err)
+	}
+	params, err := r.expandList(setKey, set, list)
+	if err != nil {
+		return nil, err
+	}
+	r.params[setKey] = params
+	return params, nil
+}
@@ -95,6 +95,17 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool once all acquired connections are released.
+func (s *Source) Close(context.Context) error {
+	s.Pool.Close()
+	return nil
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
@@ -182,6 +193,7 @@ func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 	config.ConnConfig.DialFunc = func(ctx context.Context, _ string, instance string) (net.Conn, error) {
 		return d.Dial(ctx, i)
 	}
+	sources.CancelPostgresQueries(&config.ConnConfig.Config)
 
 	// Interact with the driver directly as you normally would
 	pool, err := pgxpool.NewWithConfig(ctx, config)
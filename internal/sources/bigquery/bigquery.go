@@ -164,6 +164,14 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client, if any.
+func (s *Source) Close(context.Context) error {
+	if s.Client == nil {
+		return nil
+	}
+	return s.Client.Close()
+}
+
 func (s *Source) BigQueryClient() *bigqueryapi.Client {
 	return s.Client
 }
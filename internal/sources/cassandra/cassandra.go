@@ -91,6 +91,12 @@ func (s Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the session.
+func (s Source) Close(context.Context) error {
+	s.Session.Close()
+	return nil
+}
+
 var _ sources.Source = &Source{}
 
 func initCassandraSession(ctx context.Context, tracer trace.Tracer, c Config) (*gocql.Session, error) {
@@ -90,6 +90,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client.
+func (s *Source) Close(context.Context) error {
+	return s.Client.Close()
+}
+
 func (s *Source) ProjectID() string {
 	return s.Project
 }
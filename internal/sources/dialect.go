@@ -30,6 +30,11 @@ func (i *Dialect) String() string {
 	return "googlesql"
 }
 
+// Enum returns the values allowed for a Dialect.
+func (Dialect) Enum() []string {
+	return []string{"googlesql", "postgresql"}
+}
+
 func (i *Dialect) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	var dialect string
 	if err := unmarshal(&dialect); err != nil {
@@ -100,6 +100,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client.
+func (s *Source) Close(context.Context) error {
+	return s.Client.Close()
+}
+
 func (s *Source) FirestoreClient() *firestore.Client {
 	return s.Client
 }
@@ -15,8 +15,10 @@
 package hana
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/url"
 
@@ -49,16 +51,38 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 // Config defines the YAML schema for a SAP HANA source.
 //
 // NOTE: The go-hdb driver automatically negotiates TLS when required (e.g. HANA Cloud).
-// All fields except QueryTimeout are required.
+// All fields except QueryTimeout, Tenancy and Tenants are required.
 type Config struct {
-	Name         string `yaml:"name" validate:"required"`
-	Kind         string `yaml:"kind" validate:"required"`
-	Host         string `yaml:"host" validate:"required"`
-	Port         string `yaml:"port" validate:"required"`
-	User         string `yaml:"user" validate:"required"`
-	Password     string `yaml:"password" validate:"required"`
-	Database     string `yaml:"database" validate:"required"`
-	QueryTimeout string `yaml:"queryTimeout"`
+	Name           string `yaml:"name" validate:"required"`
+	Kind           string `yaml:"kind" validate:"required"`
+	Host           string `yaml:"host" validate:"required"`
+	Port           string `yaml:"port" validate:"required"`
+	User           string `yaml:"user" validate:"required"`
+	Password       string `yaml:"password" validate:"required"`
+	Database       string `yaml:"database" validate:"required"`
+	QueryTimeout   string `yaml:"queryTimeout"`
+	MaxRows        int    `yaml:"maxRows"`
+	MaxResultBytes int    `yaml:"maxResultBytes"`
+	// MaxLobBytes is the number of bytes of a LOB value returned by tools,
+	// 1 MiB by default.
+	MaxLobBytes int `yaml:"maxLobBytes"`
+	// SpatialFormat is the format of spatial values returned by tools, "wkt"
+	// by default or "geojson".
+	SpatialFormat string `yaml:"spatialFormat"`
+	// Tenancy routes each request to the connection pool of its tenant,
+	// whose connection settings are defined by Tenants.
+	Tenancy *sources.TenancyConfig  `yaml:"tenancy"`
+	Tenants map[string]TenantConfig `yaml:"tenants"`
+}
+
+// TenantConfig overrides the connection settings of the source for a tenant.
+// Empty fields default to the settings of the source.
+type TenantConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
 }
 
 func (c Config) SourceConfigKind() string {
@@ -66,6 +90,12 @@ func (c Config) SourceConfigKind() string {
 }
 
 func (c Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	switch c.SpatialFormat {
+	case "", "wkt", "geojson":
+	default:
+		return nil, fmt.Errorf("invalid spatialFormat %q: must be one of %q", c.SpatialFormat, []string{"wkt", "geojson"})
+	}
+
 	db, err := initHanaConnection(ctx, tracer, c.Name, c.Host, c.Port, c.User, c.Password, c.Database, c.QueryTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create SAP HANA connection: %w", err)
@@ -75,27 +105,143 @@ func (c Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		return nil, fmt.Errorf("unable to connect successfully: %w", err)
 	}
 
-	return &Source{
-		Name: c.Name,
-		Kind: SourceKind,
-		Db:   db,
-	}, nil
+	s := &Source{
+		Name:           c.Name,
+		Kind:           SourceKind,
+		MaxRows:        c.MaxRows,
+		MaxResultBytes: c.MaxResultBytes,
+		maxLobBytes:    c.MaxLobBytes,
+		spatialFormat:  c.SpatialFormat,
+		Db:             db,
+	}
+	if c.Tenancy != nil {
+		if s.tenants, err = c.tenantPools(ctx, tracer); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		s.tenancy = *c.Tenancy
+	} else if len(c.Tenants) > 0 {
+		_ = db.Close()
+		return nil, fmt.Errorf("'tenants' requires 'tenancy' to select the tenant of a request")
+	}
+	return s, nil
+}
+
+// tenantPools returns the connection pools of the tenants, which connect
+// lazily on first use.
+func (c Config) tenantPools(ctx context.Context, tracer trace.Tracer) (*sources.TenantPools, error) {
+	idleTimeout, err := c.Tenancy.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Tenants) == 0 {
+		return nil, fmt.Errorf("'tenancy' requires at least one tenant in 'tenants'")
+	}
+	return sources.NewTenantPools(idleTimeout, func(tenant string) (*sql.DB, error) {
+		t, ok := c.Tenants[tenant]
+		if !ok {
+			return nil, fmt.Errorf("unknown tenant %q", tenant)
+		}
+		db, err := initHanaConnection(ctx, tracer, c.Name, cmp.Or(t.Host, c.Host), cmp.Or(t.Port, c.Port), cmp.Or(t.User, c.User), cmp.Or(t.Password, c.Password), cmp.Or(t.Database, c.Database), c.QueryTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create SAP HANA connection for tenant %q: %w", tenant, err)
+		}
+		return db, nil
+	}), nil
 }
 
 // Source wraps a *sql.DB backed by the go-hdb driver.
-var _ sources.Source = &Source{}
+var (
+	_ sources.Source       = &Source{}
+	_ sources.TenantSource = &Source{}
+)
 
 type Source struct {
 	Name string `yaml:"name"`
 	Kind string `yaml:"kind"`
 	Db   *sql.DB
+
+	MaxRows        int
+	MaxResultBytes int
+
+	maxLobBytes   int
+	spatialFormat string
+
+	// tenancy and tenants route requests to the connection pool of their
+	// tenant, if set.
+	tenancy sources.TenancyConfig
+	tenants *sources.TenantPools
 }
 
 func (s *Source) SourceKind() string { return SourceKind }
 
+// Close closes the connection pools.
+func (s *Source) Close(context.Context) error {
+	err := s.Db.Close()
+	if s.tenants != nil {
+		err = errors.Join(err, s.tenants.Close())
+	}
+	return err
+}
+
+// Health pings the database, and the open connection pools of the tenants.
+func (s *Source) Health(ctx context.Context) error {
+	err := s.Db.PingContext(ctx)
+	if s.tenants != nil {
+		err = errors.Join(err, s.tenants.Ping(ctx))
+	}
+	return err
+}
+
 // HanaDB exposes the underlying *sql.DB so that tools can reuse a shared pool.
 func (s *Source) HanaDB() *sql.DB { return s.Db }
 
+// HanaDBContext returns the connection pool of the tenant of the request of
+// ctx, or the shared pool if the source has no tenancy.
+func (s *Source) HanaDBContext(ctx context.Context) (*sql.DB, error) {
+	if s.tenants == nil {
+		return s.Db, nil
+	}
+	tenant, err := s.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.tenants.Get(tenant)
+}
+
+// Tenant returns the tenant of the request of ctx, or "" if the source has no
+// tenancy.
+func (s *Source) Tenant(ctx context.Context) (string, error) {
+	if s.tenants == nil {
+		return "", nil
+	}
+	return s.tenancy.Tenant(ctx)
+}
+
+// DBStats returns the statistics of the connection pool.
+func (s *Source) DBStats() sql.DBStats { return s.Db.Stats() }
+
+// TenantDBStats returns the statistics of the open connection pool of each
+// tenant.
+func (s *Source) TenantDBStats() map[string]sql.DBStats {
+	if s.tenants == nil {
+		return nil
+	}
+	return s.tenants.Stats()
+}
+
+// ResultLimits returns the default result limits for tools using this source.
+func (s *Source) ResultLimits() (int, int) {
+	return s.MaxRows, s.MaxResultBytes
+}
+
+// MaxLobBytes returns the number of bytes of a LOB value returned by tools,
+// or 0 for the default.
+func (s *Source) MaxLobBytes() int { return s.maxLobBytes }
+
+// SpatialFormat returns the format of spatial values returned by tools.
+func (s *Source) SpatialFormat() string { return s.spatialFormat }
+
 // initHanaConnection creates a connection pool using the go-hdb driver.
 func initHanaConnection(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string) (*sql.DB, error) {
 	//nolint:all // Span end handled below; ctx reassignment intentional.
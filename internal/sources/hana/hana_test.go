@@ -20,6 +20,7 @@ import (
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/hana"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 )
@@ -54,6 +55,82 @@ func TestParseFromYamlHana(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with result limits and formats",
+			in: `
+            sources:
+                my-hana-instance:
+                    kind: hana
+                    host: hana-host
+                    port: "39015"
+                    database: HDB
+                    user: my_user
+                    password: my_pass
+                    maxRows: 500
+                    maxResultBytes: 1048576
+                    maxLobBytes: 65536
+                    spatialFormat: geojson
+            `,
+			want: server.SourceConfigs{
+				"my-hana-instance": hana.Config{
+					Name:           "my-hana-instance",
+					Kind:           hana.SourceKind,
+					Host:           "hana-host",
+					Port:           "39015",
+					Database:       "HDB",
+					User:           "my_user",
+					Password:       "my_pass",
+					MaxRows:        500,
+					MaxResultBytes: 1048576,
+					MaxLobBytes:    65536,
+					SpatialFormat:  "geojson",
+				},
+			},
+		},
+		{
+			desc: "with tenancy",
+			in: `
+            sources:
+                my-hana-instance:
+                    kind: hana
+                    host: hana-host
+                    port: "39015"
+                    database: HDB
+                    user: my_user
+                    password: my_pass
+                    tenancy:
+                        authService: my-google-auth
+                        claim: tenant
+                        idleTimeout: 5m
+                    tenants:
+                        acme:
+                            database: ACME
+                        globex:
+                            host: globex-host
+                            user: globex_user
+                            password: globex_pass
+            `,
+			want: server.SourceConfigs{
+				"my-hana-instance": hana.Config{
+					Name:     "my-hana-instance",
+					Kind:     hana.SourceKind,
+					Host:     "hana-host",
+					Port:     "39015",
+					Database: "HDB",
+					User:     "my_user",
+					Password: "my_pass",
+					Tenancy: &sources.TenancyConfig{
+						AuthService: "my-google-auth",
+						Claim:       "tenant",
+						IdleTimeout: "5m",
+					},
+					Tenants: map[string]hana.TenantConfig{
+						"acme":   {Database: "ACME"},
+						"globex": {Host: "globex-host", User: "globex_user", Password: "globex_pass"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
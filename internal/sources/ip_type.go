@@ -29,6 +29,11 @@ func (i *IPType) String() string {
 	return "public"
 }
 
+// Enum returns the values allowed for an IPType.
+func (IPType) Enum() []string {
+	return []string{"public", "private"}
+}
+
 func (i *IPType) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	var ipType string
 	if err := unmarshal(&ipType); err != nil {
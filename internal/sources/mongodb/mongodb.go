@@ -87,6 +87,16 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close disconnects the client.
+func (s *Source) Close(ctx context.Context) error {
+	return s.Client.Disconnect(ctx)
+}
+
+// Health pings the primary.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Client.Ping(ctx, nil)
+}
+
 func (s *Source) MongoClient() *mongo.Client {
 	return s.Client
 }
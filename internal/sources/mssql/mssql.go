@@ -98,6 +98,16 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool.
+func (s *Source) Close(context.Context) error {
+	return s.Db.Close()
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
 func (s *Source) MSSQLDB() *sql.DB {
 	// Returns a Cloud SQL MSSQL database connection pool
 	return s.Db
@@ -48,15 +48,17 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name         string            `yaml:"name" validate:"required"`
-	Kind         string            `yaml:"kind" validate:"required"`
-	Host         string            `yaml:"host" validate:"required"`
-	Port         string            `yaml:"port" validate:"required"`
-	User         string            `yaml:"user" validate:"required"`
-	Password     string            `yaml:"password" validate:"required"`
-	Database     string            `yaml:"database" validate:"required"`
-	QueryTimeout string            `yaml:"queryTimeout"`
-	QueryParams  map[string]string `yaml:"queryParams"`
+	Name           string            `yaml:"name" validate:"required"`
+	Kind           string            `yaml:"kind" validate:"required"`
+	Host           string            `yaml:"host" validate:"required"`
+	Port           string            `yaml:"port" validate:"required"`
+	User           string            `yaml:"user" validate:"required"`
+	Password       string            `yaml:"password" validate:"required"`
+	Database       string            `yaml:"database" validate:"required"`
+	QueryTimeout   string            `yaml:"queryTimeout"`
+	QueryParams    map[string]string `yaml:"queryParams"`
+	MaxRows        int               `yaml:"maxRows"`
+	MaxResultBytes int               `yaml:"maxResultBytes"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -75,9 +77,11 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		Name:           r.Name,
+		Kind:           SourceKind,
+		MaxRows:        r.MaxRows,
+		MaxResultBytes: r.MaxResultBytes,
+		Pool:           pool,
 	}
 	return s, nil
 }
@@ -88,16 +92,39 @@ type Source struct {
 	Name string `yaml:"name"`
 	Kind string `yaml:"kind"`
 	Pool *sql.DB
+
+	MaxRows        int
+	MaxResultBytes int
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool.
+func (s *Source) Close(context.Context) error {
+	return s.Pool.Close()
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Pool.PingContext(ctx)
+}
+
+// ResultLimits returns the default result limits for tools using this source.
+func (s *Source) ResultLimits() (int, int) {
+	return s.MaxRows, s.MaxResultBytes
+}
+
 func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+// DBStats returns the statistics of the connection pool.
+func (s *Source) DBStats() sql.DBStats {
+	return s.Pool.Stats()
+}
+
 func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string, queryParams map[string]string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -92,6 +92,16 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the driver.
+func (s *Source) Close(ctx context.Context) error {
+	return s.Driver.Close(ctx)
+}
+
+// Health verifies that the driver can connect to the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Driver.VerifyConnectivity(ctx)
+}
+
 func (s *Source) Neo4jDriver() neo4j.DriverWithContext {
 	return s.Driver
 }
@@ -16,6 +16,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/url"
 	"strings"
@@ -47,14 +48,16 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources
 }
 
 type Config struct {
-	Name        string            `yaml:"name" validate:"required"`
-	Kind        string            `yaml:"kind" validate:"required"`
-	Host        string            `yaml:"host" validate:"required"`
-	Port        string            `yaml:"port" validate:"required"`
-	User        string            `yaml:"user" validate:"required"`
-	Password    string            `yaml:"password" validate:"required"`
-	Database    string            `yaml:"database" validate:"required"`
-	QueryParams map[string]string `yaml:"queryParams"`
+	Name           string            `yaml:"name" validate:"required"`
+	Kind           string            `yaml:"kind" validate:"required"`
+	Host           string            `yaml:"host" validate:"required"`
+	Port           string            `yaml:"port" validate:"required"`
+	User           string            `yaml:"user" validate:"required"`
+	Password       string            `yaml:"password" validate:"required"`
+	Database       string            `yaml:"database" validate:"required"`
+	QueryParams    map[string]string `yaml:"queryParams"`
+	MaxRows        int               `yaml:"maxRows"`
+	MaxResultBytes int               `yaml:"maxResultBytes"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -73,9 +76,11 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		Name:           r.Name,
+		Kind:           SourceKind,
+		MaxRows:        r.MaxRows,
+		MaxResultBytes: r.MaxResultBytes,
+		Pool:           pool,
 	}
 	return s, nil
 }
@@ -86,16 +91,51 @@ type Source struct {
 	Name string `yaml:"name"`
 	Kind string `yaml:"kind"`
 	Pool *pgxpool.Pool
+
+	MaxRows        int
+	MaxResultBytes int
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool once all acquired connections are released.
+func (s *Source) Close(context.Context) error {
+	s.Pool.Close()
+	return nil
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
+// ResultLimits returns the default result limits for tools using this source.
+func (s *Source) ResultLimits() (int, int) {
+	return s.MaxRows, s.MaxResultBytes
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// DBStats returns the statistics of the connection pool in the form reported
+// by database/sql.
+func (s *Source) DBStats() sql.DBStats {
+	stat := s.Pool.Stat()
+	return sql.DBStats{
+		MaxOpenConnections: int(stat.MaxConns()),
+		OpenConnections:    int(stat.TotalConns()),
+		InUse:              int(stat.AcquiredConns()),
+		Idle:               int(stat.IdleConns()),
+		WaitCount:          stat.EmptyAcquireCount(),
+		WaitDuration:       stat.EmptyAcquireWaitTime(),
+		MaxIdleTimeClosed:  stat.MaxIdleDestroyCount(),
+		MaxLifetimeClosed:  stat.MaxLifetimeDestroyCount(),
+	}
+}
+
 func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string, queryParams map[string]string) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -120,7 +160,13 @@ func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 		Path:     dbname,
 		RawQuery: ConvertParamMapToRawQuery(queryParams),
 	}
-	pool, err := pgxpool.New(ctx, url.String())
+	config, err := pgxpool.ParseConfig(url.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection uri: %w", err)
+	}
+	sources.CancelPostgresQueries(&config.ConnConfig.Config)
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -16,6 +16,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/goccy/go-yaml"
@@ -147,6 +148,19 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client.
+func (s *Source) Close(context.Context) error {
+	if c, ok := s.Client.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Health pings the server.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Client.Do(ctx, "PING").Err()
+}
+
 func (s *Source) RedisClient() RedisClient {
 	return s.Client
 }
@@ -16,8 +16,9 @@ package sources
 
 import (
 	"context"
-
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/goccy/go-yaml"
 	"go.opentelemetry.io/otel/attribute"
@@ -40,6 +41,11 @@ func Register(kind string, factory SourceConfigFactory) bool {
 	return true
 }
 
+// Kinds returns the registered source kinds in sorted order.
+func Kinds() []string {
+	return slices.Sorted(maps.Keys(sourceRegistry))
+}
+
 // DecodeConfig decodes a source configuration using the registered factory for the given kind.
 func DecodeConfig(ctx context.Context, kind string, name string, decoder *yaml.Decoder) (SourceConfig, error) {
 	factory, found := sourceRegistry[kind]
@@ -64,6 +70,43 @@ type Source interface {
 	SourceKind() string
 }
 
+// Closer is implemented by sources holding resources, such as connection
+// pools, that must be released once the source is no longer used.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// HealthChecker is implemented by sources that can check the connectivity to
+// their backend.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// TenantSource is implemented by sources that route requests to the
+// connection pool of their tenant.
+type TenantSource interface {
+	// Tenant returns the tenant of the request of ctx, or "" if the source
+	// does not route requests by tenant.
+	Tenant(ctx context.Context) (string, error)
+}
+
+// Close releases the resources held by s, if it implements Closer.
+func Close(ctx context.Context, s Source) error {
+	if c, ok := s.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// Health checks the connectivity of s. Sources that do not implement
+// HealthChecker are considered healthy.
+func Health(ctx context.Context, s Source) error {
+	if h, ok := s.(HealthChecker); ok {
+		return h.Health(ctx)
+	}
+	return nil
+}
+
 // InitConnectionSpan adds a span for database pool connection initialization
 func InitConnectionSpan(ctx context.Context, tracer trace.Tracer, sourceKind, sourceName string) (context.Context, trace.Span) {
 	ctx, span := tracer.Start(
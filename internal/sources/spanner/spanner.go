@@ -85,6 +85,12 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client and its sessions.
+func (s *Source) Close(context.Context) error {
+	s.Client.Close()
+	return nil
+}
+
 func (s *Source) SpannerClient() *spanner.Client {
 	return s.Client
 }
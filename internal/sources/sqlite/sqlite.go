@@ -85,6 +85,16 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool.
+func (s *Source) Close(context.Context) error {
+	return s.Db.Close()
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
 func (s *Source) SQLiteDB() *sql.DB {
 	return s.Db
 }
@@ -0,0 +1,227 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// defaultTenantIdleTimeout is how long the connection pool of a tenant is
+// kept open without requests, if no idle timeout is configured.
+const defaultTenantIdleTimeout = 10 * time.Minute
+
+// TenancyConfig selects the tenant of a request, from a claim of a verified
+// auth service or from a request header, to route the request to the
+// connection pool of the tenant.
+type TenancyConfig struct {
+	// AuthService and Claim select the tenant from a claim of the ID token
+	// verified by the auth service.
+	AuthService string `yaml:"authService"`
+	Claim       string `yaml:"claim"`
+	// Header selects the tenant from a request header. It must only be used
+	// behind a proxy that sets the header, since clients may set any value.
+	Header string `yaml:"header"`
+	// IdleTimeout is how long the connection pool of a tenant is kept open
+	// without requests. Defaults to 10m.
+	IdleTimeout string `yaml:"idleTimeout"`
+}
+
+// Validate checks that the tenant is selected by either a claim or a header,
+// and returns the idle timeout.
+func (c TenancyConfig) Validate() (time.Duration, error) {
+	byClaim := c.AuthService != "" || c.Claim != ""
+	if byClaim && (c.AuthService == "" || c.Claim == "") {
+		return 0, fmt.Errorf("tenancy requires both 'authService' and 'claim' to select the tenant from a claim")
+	}
+	if byClaim == (c.Header != "") {
+		return 0, fmt.Errorf("tenancy requires either 'authService' and 'claim', or 'header' to select the tenant")
+	}
+	if c.IdleTimeout == "" {
+		return defaultTenantIdleTimeout, nil
+	}
+	d, err := time.ParseDuration(c.IdleTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid tenancy 'idleTimeout' %q: must be a positive duration", c.IdleTimeout)
+	}
+	return d, nil
+}
+
+// Tenant returns the tenant of the request of ctx.
+func (c TenancyConfig) Tenant(ctx context.Context) (string, error) {
+	if c.Header != "" {
+		tenant := util.HeaderFromContext(ctx).Get(c.Header)
+		if tenant == "" {
+			return "", fmt.Errorf("unable to determine the tenant: missing %q header", c.Header)
+		}
+		return tenant, nil
+	}
+	claim, ok := util.ClaimsFromContext(ctx)[c.AuthService][c.Claim]
+	if !ok || claim == nil || claim == "" {
+		return "", fmt.Errorf("unable to determine the tenant: missing claim %q of auth service %q", c.Claim, c.AuthService)
+	}
+	return fmt.Sprint(claim), nil
+}
+
+// TenantPools opens a connection pool per tenant on first use, and closes the
+// pools without requests for the idle timeout. Pools with connections in use
+// are never closed.
+type TenantPools struct {
+	open        func(tenant string) (*sql.DB, error)
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	pools  map[string]*tenantPool
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+type tenantPool struct {
+	db       *sql.DB
+	lastUsed time.Time
+}
+
+// NewTenantPools returns TenantPools opening the pool of a tenant with open,
+// and closing it once idle for idleTimeout.
+func NewTenantPools(idleTimeout time.Duration, open func(tenant string) (*sql.DB, error)) *TenantPools {
+	p := &TenantPools{
+		open:        open,
+		idleTimeout: idleTimeout,
+		pools:       make(map[string]*tenantPool),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go p.evictLoop()
+	return p
+}
+
+// Get returns the connection pool of tenant, opening it if needed.
+func (p *TenantPools) Get(tenant string) (*sql.DB, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, fmt.Errorf("connection pools are closed")
+	}
+	if tp, ok := p.pools[tenant]; ok {
+		tp.lastUsed = time.Now()
+		return tp.db, nil
+	}
+	db, err := p.open(tenant)
+	if err != nil {
+		return nil, err
+	}
+	p.pools[tenant] = &tenantPool{db: db, lastUsed: time.Now()}
+	return db, nil
+}
+
+// Tenants returns the tenants with an open connection pool.
+func (p *TenantPools) Tenants() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Sorted(maps.Keys(p.pools))
+}
+
+// Stats returns the statistics of the open connection pool of each tenant.
+func (p *TenantPools) Stats() map[string]sql.DBStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make(map[string]sql.DBStats, len(p.pools))
+	for tenant, tp := range p.pools {
+		stats[tenant] = tp.db.Stats()
+	}
+	return stats
+}
+
+// Ping pings the open connection pool of each tenant.
+func (p *TenantPools) Ping(ctx context.Context) error {
+	p.mu.Lock()
+	pools := make(map[string]*sql.DB, len(p.pools))
+	for tenant, tp := range p.pools {
+		pools[tenant] = tp.db
+	}
+	p.mu.Unlock()
+
+	var errs error
+	for _, tenant := range slices.Sorted(maps.Keys(pools)) {
+		if err := pools[tenant].PingContext(ctx); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("tenant %q: %w", tenant, err))
+		}
+	}
+	return errs
+}
+
+func (p *TenantPools) evictLoop() {
+	defer close(p.done)
+	ticker := time.NewTicker(max(p.idleTimeout/2, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			_ = p.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes the pools without requests since the idle timeout before
+// now, and without connections in use.
+func (p *TenantPools) evictIdle(now time.Time) error {
+	p.mu.Lock()
+	var idle []*sql.DB
+	for tenant, tp := range p.pools {
+		if now.Sub(tp.lastUsed) >= p.idleTimeout && tp.db.Stats().InUse == 0 {
+			idle = append(idle, tp.db)
+			delete(p.pools, tenant)
+		}
+	}
+	p.mu.Unlock()
+
+	var errs error
+	for _, db := range idle {
+		errs = errors.Join(errs, db.Close())
+	}
+	return errs
+}
+
+// Close closes every connection pool.
+func (p *TenantPools) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	pools := p.pools
+	p.pools = nil
+	p.mu.Unlock()
+
+	close(p.stop)
+	<-p.done
+	var errs error
+	for _, tp := range pools {
+		errs = errors.Join(errs, tp.db.Close())
+	}
+	return errs
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/SAP/go-hdb/driver"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestTenancyConfigValidate(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  TenancyConfig
+		want time.Duration
+		err  string
+	}{
+		{desc: "claim", cfg: TenancyConfig{AuthService: "my-auth", Claim: "tenant"}, want: defaultTenantIdleTimeout},
+		{desc: "header", cfg: TenancyConfig{Header: "X-Tenant", IdleTimeout: "30s"}, want: 30 * time.Second},
+		{desc: "claim without auth service", cfg: TenancyConfig{Claim: "tenant"}, err: "requires both"},
+		{desc: "neither", cfg: TenancyConfig{}, err: "requires either"},
+		{desc: "both", cfg: TenancyConfig{AuthService: "my-auth", Claim: "tenant", Header: "X-Tenant"}, err: "requires either"},
+		{desc: "invalid idle timeout", cfg: TenancyConfig{Header: "X-Tenant", IdleTimeout: "-1m"}, err: "invalid tenancy 'idleTimeout'"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.cfg.Validate()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected idle timeout: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTenancyConfigTenant(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Tenant", "acme")
+	claims := map[string]map[string]any{"my-auth": {"tenant": "globex"}}
+	ctx := util.WithHeader(util.WithClaims(context.Background(), claims), header)
+
+	tcs := []struct {
+		desc string
+		cfg  TenancyConfig
+		ctx  context.Context
+		want string
+		err  string
+	}{
+		{desc: "header", cfg: TenancyConfig{Header: "X-Tenant"}, ctx: ctx, want: "acme"},
+		{desc: "claim", cfg: TenancyConfig{AuthService: "my-auth", Claim: "tenant"}, ctx: ctx, want: "globex"},
+		{desc: "missing header", cfg: TenancyConfig{Header: "X-Other"}, ctx: ctx, err: `missing "X-Other" header`},
+		{desc: "missing claim", cfg: TenancyConfig{AuthService: "other-auth", Claim: "tenant"}, ctx: ctx, err: `missing claim "tenant"`},
+		{desc: "unauthenticated", cfg: TenancyConfig{AuthService: "my-auth", Claim: "tenant"}, ctx: context.Background(), err: "missing claim"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.cfg.Tenant(tc.ctx)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected tenant: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTenantPools(t *testing.T) {
+	var opened []string
+	p := NewTenantPools(time.Hour, func(tenant string) (*sql.DB, error) {
+		if tenant == "unknown" {
+			return nil, fmt.Errorf("unknown tenant %q", tenant)
+		}
+		opened = append(opened, tenant)
+		// sql.Open does not connect until the pool is used
+		return sql.Open("hdb", fmt.Sprintf("hdb://user:pass@%s.invalid:39015", tenant))
+	})
+	defer p.Close()
+
+	acme, err := p.Get("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	again, err := p.Get("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if again != acme {
+		t.Errorf("expected the pool of a tenant to be reused")
+	}
+	if _, err := p.Get("globex"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := p.Get("unknown"); err == nil {
+		t.Fatalf("expected error for unknown tenant")
+	}
+	if !slices.Equal(opened, []string{"acme", "globex"}) {
+		t.Errorf("unexpected opened pools: %q", opened)
+	}
+	if got := slices.Sorted(maps.Keys(p.Stats())); !slices.Equal(got, []string{"acme", "globex"}) {
+		t.Errorf("unexpected tenants of pool statistics: %q", got)
+	}
+
+	// only pools idle for the idle timeout are evicted
+	p.mu.Lock()
+	p.pools["globex"].lastUsed = time.Now().Add(-2 * time.Hour)
+	p.mu.Unlock()
+	if err := p.evictIdle(time.Now()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := p.Tenants(); !slices.Equal(got, []string{"acme"}) {
+		t.Errorf("unexpected tenants after eviction: %q", got)
+	}
+
+	// an evicted tenant is reopened on the next request
+	if _, err := p.Get("globex"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := p.Tenants(); !slices.Equal(got, []string{"acme", "globex"}) {
+		t.Errorf("unexpected tenants after reopening: %q", got)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := p.Get("acme"); err == nil {
+		t.Fatalf("expected error after close")
+	}
+}
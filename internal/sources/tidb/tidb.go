@@ -98,6 +98,16 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool.
+func (s *Source) Close(context.Context) error {
+	return s.Pool.Close()
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Pool.PingContext(ctx)
+}
+
 func (s *Source) TiDBPool() *sql.DB {
 	return s.Pool
 }
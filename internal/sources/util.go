@@ -21,8 +21,11 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/cloudsqlconn"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"golang.org/x/oauth2/google"
 )
 
@@ -45,6 +48,19 @@ func GetCloudSQLOpts(ipType, userAgent string, useIAM bool) ([]cloudsqlconn.Opti
 	return opts, nil
 }
 
+// postgresCancelDeadlineDelay is how long a Postgres query may take to stop
+// once cancelled before its connection is closed.
+const postgresCancelDeadlineDelay = 5 * time.Second
+
+// CancelPostgresQueries makes connections send a cancel request to the server
+// when the context of a query is done, so that the query stops instead of
+// running until the server notices that the connection was closed.
+func CancelPostgresQueries(config *pgconn.Config) {
+	config.BuildContextWatcherHandler = func(conn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: conn, DeadlineDelay: postgresCancelDeadlineDelay}
+	}
+}
+
 // GetIAMPrincipalEmailFromADC finds the email associated with ADC
 func GetIAMPrincipalEmailFromADC(ctx context.Context) (string, error) {
 	// Finds ADC and returns an HTTP client associated with it
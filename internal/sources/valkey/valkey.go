@@ -120,6 +120,17 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the client.
+func (s *Source) Close(context.Context) error {
+	s.Client.Close()
+	return nil
+}
+
+// Health pings the server.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Client.Do(ctx, s.Client.B().Ping().Build()).Error()
+}
+
 func (s *Source) ValkeyClient() valkey.Client {
 	return s.Client
 }
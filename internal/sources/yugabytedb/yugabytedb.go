@@ -93,6 +93,17 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connection pool once all acquired connections are released.
+func (s *Source) Close(context.Context) error {
+	s.Pool.Close()
+	return nil
+}
+
+// Health pings the database.
+func (s *Source) Health(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
 func (s *Source) YugabyteDBPool() *pgxpool.Pool {
 	return s.Pool
 }
@@ -15,9 +15,16 @@
 package telemetry
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -31,8 +38,82 @@ const (
 	toolInvokeCountName = "toolbox.server.tool.invoke.count"
 	mcpSseCountName     = "toolbox.server.mcp.sse.count"
 	mcpPostCountName    = "toolbox.server.mcp.post.count"
+	cacheHitCountName   = "toolbox.server.tool.cache.hit.count"
+	cacheMissCountName  = "toolbox.server.tool.cache.miss.count"
+
+	toolInvokeDurationName   = "toolbox.server.tool.invoke.duration"
+	toolInvokeErrorCountName = "toolbox.server.tool.invoke.error.count"
+	toolResultRowsName       = "toolbox.server.tool.result.rows"
+	toolResultSizeName       = "toolbox.server.tool.result.size"
+	poolConnectionsName      = "toolbox.source.pool.connections"
+	poolMaxConnectionsName   = "toolbox.source.pool.connections.max"
+	poolWaitCountName        = "toolbox.source.pool.wait.count"
+	poolWaitDurationName     = "toolbox.source.pool.wait.duration"
+	serverReadyName          = "toolbox.server.ready"
+	sourceHealthName         = "toolbox.source.health"
+)
+
+// Error types recorded with failed tool invocations.
+const (
+	ErrorTypeNotFound             = "not_found"
+	ErrorTypeInvalidRequest       = "invalid_request"
+	ErrorTypeUnauthorized         = "unauthorized"
+	ErrorTypeInvalidParams        = "invalid_parameters"
+	ErrorTypeUpstreamAuth         = "upstream_auth"
+	ErrorTypeTimeout              = "timeout"
+	ErrorTypeCanceled             = "canceled"
+	ErrorTypeExecution            = "execution"
+	ErrorTypeInternal             = "internal"
+	ErrorTypeUnavailable          = "unavailable"
+	ErrorTypeConfirmationRequired = "confirmation_required"
 )
 
+// InvokeErrorType returns the error type of an error returned by a tool
+// invocation.
+func InvokeErrorType(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTypeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorTypeCanceled
+	default:
+		return ErrorTypeExecution
+	}
+}
+
+// ToolMetadata describes a tool in the attributes of its metrics.
+type ToolMetadata struct {
+	Kind   string
+	Source string
+}
+
+// HealthStatus is the readiness of the server and the health of its sources,
+// reported by the health gauges.
+type HealthStatus struct {
+	Ready   bool
+	Sources map[string]SourceHealth
+}
+
+// SourceHealth is the health of a single source.
+type SourceHealth struct {
+	Kind    string
+	Healthy bool
+}
+
+// Pool is a source backed by a connection pool, whose statistics are
+// reported as metrics.
+type Pool interface {
+	SourceKind() string
+	DBStats() sql.DBStats
+}
+
+// TenantPool is a Pool that also opens a connection pool per tenant, whose
+// statistics are reported with the tenant.
+type TenantPool interface {
+	Pool
+	TenantDBStats() map[string]sql.DBStats
+}
+
 // Instrumentation defines the telemetry instrumentation for toolbox
 type Instrumentation struct {
 	Tracer     trace.Tracer
@@ -42,6 +123,18 @@ type Instrumentation struct {
 	ToolInvoke metric.Int64Counter
 	McpSse     metric.Int64Counter
 	McpPost    metric.Int64Counter
+	CacheHit   metric.Int64Counter
+	CacheMiss  metric.Int64Counter
+
+	ToolInvokeDuration   metric.Float64Histogram
+	ToolInvokeErrorCount metric.Int64Counter
+	ToolResultRows       metric.Int64Histogram
+	ToolResultSize       metric.Int64Histogram
+
+	mu          sync.RWMutex
+	tools       map[string]ToolMetadata
+	pools       map[string]Pool
+	healthCheck func(context.Context) HealthStatus
 }
 
 func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, error) {
@@ -96,6 +189,63 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", mcpPostCountName, err)
 	}
 
+	cacheHit, err := meter.Int64Counter(
+		cacheHitCountName,
+		metric.WithDescription("Number of tool invocations served from the response cache."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", cacheHitCountName, err)
+	}
+
+	cacheMiss, err := meter.Int64Counter(
+		cacheMissCountName,
+		metric.WithDescription("Number of tool invocations of cached tools not found in the response cache."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", cacheMissCountName, err)
+	}
+
+	toolInvokeDuration, err := meter.Float64Histogram(
+		toolInvokeDurationName,
+		metric.WithDescription("Duration of tool invocations."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolInvokeDurationName, err)
+	}
+
+	toolInvokeErrorCount, err := meter.Int64Counter(
+		toolInvokeErrorCountName,
+		metric.WithDescription("Number of failed tool invocations."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolInvokeErrorCountName, err)
+	}
+
+	toolResultRows, err := meter.Int64Histogram(
+		toolResultRowsName,
+		metric.WithDescription("Number of rows returned by tool invocations."),
+		metric.WithUnit("{row}"),
+		metric.WithExplicitBucketBoundaries(0, 1, 10, 100, 1000, 10000, 100000),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolResultRowsName, err)
+	}
+
+	toolResultSize, err := meter.Int64Histogram(
+		toolResultSizeName,
+		metric.WithDescription("Size of the results of tool invocations as encoded in the response."),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(0, 1024, 10240, 102400, 1048576, 10485760, 104857600),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", toolResultSizeName, err)
+	}
+
 	instrumentation := &Instrumentation{
 		Tracer:     tracer,
 		meter:      meter,
@@ -104,6 +254,179 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		ToolInvoke: toolInvoke,
 		McpSse:     mcpSse,
 		McpPost:    mcpPost,
+		CacheHit:   cacheHit,
+		CacheMiss:  cacheMiss,
+
+		ToolInvokeDuration:   toolInvokeDuration,
+		ToolInvokeErrorCount: toolInvokeErrorCount,
+		ToolResultRows:       toolResultRows,
+		ToolResultSize:       toolResultSize,
+	}
+	if err := instrumentation.registerPoolMetrics(); err != nil {
+		return nil, err
+	}
+	if err := instrumentation.registerHealthMetrics(); err != nil {
+		return nil, err
 	}
 	return instrumentation, nil
 }
+
+// SetTools replaces the metadata of the tools being served, which is added
+// to the attributes of tool invocation metrics.
+func (i *Instrumentation) SetTools(tools map[string]ToolMetadata) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.tools = tools
+}
+
+// SetPools replaces the connection pools whose statistics are reported.
+func (i *Instrumentation) SetPools(pools map[string]Pool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.pools = pools
+}
+
+// SetHealthCheck sets the function checking the health reported by the
+// health gauges.
+func (i *Instrumentation) SetHealthCheck(check func(context.Context) HealthStatus) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.healthCheck = check
+}
+
+// RecordToolInvocation records the duration and result size of a tool
+// invocation. errorType is empty for successful invocations, whose result
+// size is recorded.
+func (i *Instrumentation) RecordToolInvocation(ctx context.Context, toolName string, d time.Duration, errorType string, rows, size int) {
+	i.mu.RLock()
+	md := i.tools[toolName]
+	i.mu.RUnlock()
+	attrs := []attribute.KeyValue{
+		attribute.String("toolbox.name", toolName),
+		attribute.String("toolbox.tool.kind", md.Kind),
+		attribute.String("toolbox.source.name", md.Source),
+	}
+	if errorType != "" {
+		errAttrs := metric.WithAttributes(append(attrs, attribute.String("error.type", errorType))...)
+		i.ToolInvokeDuration.Record(ctx, d.Seconds(), errAttrs)
+		i.ToolInvokeErrorCount.Add(ctx, 1, errAttrs)
+		return
+	}
+	i.ToolInvokeDuration.Record(ctx, d.Seconds(), metric.WithAttributes(attrs...))
+	i.ToolResultRows.Record(ctx, int64(rows), metric.WithAttributes(attrs...))
+	i.ToolResultSize.Record(ctx, int64(size), metric.WithAttributes(attrs...))
+}
+
+// registerPoolMetrics registers gauges observing the connection pools set
+// with SetPools.
+func (i *Instrumentation) registerPoolMetrics() error {
+	connections, err := i.meter.Int64ObservableGauge(
+		poolConnectionsName,
+		metric.WithDescription("Number of connections in the connection pool of a source, by state."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", poolConnectionsName, err)
+	}
+	maxConnections, err := i.meter.Int64ObservableGauge(
+		poolMaxConnectionsName,
+		metric.WithDescription("Maximum number of open connections in the connection pool of a source."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", poolMaxConnectionsName, err)
+	}
+	waitCount, err := i.meter.Int64ObservableCounter(
+		poolWaitCountName,
+		metric.WithDescription("Number of times a connection was waited for."),
+		metric.WithUnit("{wait}"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", poolWaitCountName, err)
+	}
+	waitDuration, err := i.meter.Float64ObservableCounter(
+		poolWaitDurationName,
+		metric.WithDescription("Total time spent waiting for a connection."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", poolWaitDurationName, err)
+	}
+
+	_, err = i.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		i.mu.RLock()
+		defer i.mu.RUnlock()
+		observe := func(stats sql.DBStats, attrs ...attribute.KeyValue) {
+			o.ObserveInt64(connections, int64(stats.InUse), metric.WithAttributes(append(attrs, attribute.String("state", "in_use"))...))
+			o.ObserveInt64(connections, int64(stats.Idle), metric.WithAttributes(append(attrs, attribute.String("state", "idle"))...))
+			o.ObserveInt64(maxConnections, int64(stats.MaxOpenConnections), metric.WithAttributes(attrs...))
+			o.ObserveInt64(waitCount, stats.WaitCount, metric.WithAttributes(attrs...))
+			o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), metric.WithAttributes(attrs...))
+		}
+		for name, p := range i.pools {
+			attrs := []attribute.KeyValue{
+				attribute.String("toolbox.source.name", name),
+				attribute.String("toolbox.source.kind", p.SourceKind()),
+			}
+			observe(p.DBStats(), attrs...)
+			if tp, ok := p.(TenantPool); ok {
+				for tenant, stats := range tp.TenantDBStats() {
+					observe(stats, append(slices.Clip(attrs), attribute.String("toolbox.source.tenant", tenant))...)
+				}
+			}
+		}
+		return nil
+	}, connections, maxConnections, waitCount, waitDuration)
+	if err != nil {
+		return fmt.Errorf("unable to register connection pool metrics: %w", err)
+	}
+	return nil
+}
+
+// registerHealthMetrics registers gauges observing the health status checked
+// by the function set with SetHealthCheck.
+func (i *Instrumentation) registerHealthMetrics() error {
+	ready, err := i.meter.Int64ObservableGauge(
+		serverReadyName,
+		metric.WithDescription("Whether the server is ready to serve requests (1) or not (0)."),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", serverReadyName, err)
+	}
+	sourceHealth, err := i.meter.Int64ObservableGauge(
+		sourceHealthName,
+		metric.WithDescription("Whether a source is healthy (1) or not (0)."),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create %s metric: %w", sourceHealthName, err)
+	}
+
+	_, err = i.meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		i.mu.RLock()
+		check := i.healthCheck
+		i.mu.RUnlock()
+		if check == nil {
+			return nil
+		}
+		status := check(ctx)
+		o.ObserveInt64(ready, boolToInt64(status.Ready))
+		for name, h := range status.Sources {
+			o.ObserveInt64(sourceHealth, boolToInt64(h.Healthy), metric.WithAttributes(
+				attribute.String("toolbox.source.name", name),
+				attribute.String("toolbox.source.kind", h.Kind),
+			))
+		}
+		return nil
+	}, ready, sourceHealth)
+	if err != nil {
+		return fmt.Errorf("unable to register health metrics: %w", err)
+	}
+	return nil
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -18,22 +18,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 
 	mexporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/metric"
 	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/propagators/autoprop"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	promexporter "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
+var (
+	promMu       sync.Mutex
+	promRegistry *prometheus.Registry
+)
+
+// PrometheusHandler returns a handler serving the metrics in the Prometheus
+// text format, or nil if SetupOTel was not called with telemetryPrometheus.
+func PrometheusHandler() http.Handler {
+	promMu.Lock()
+	defer promMu.Unlock()
+	if promRegistry == nil {
+		return nil
+	}
+	return promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{})
+}
+
 // setupOTelSDK bootstraps the OpenTelemetry pipeline.
 // If it does not return an error, make sure to call shutdown for proper cleanup.
-func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemetryGCP bool, telemetryServiceName string) (shutdown func(context.Context) error, err error) {
+func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemetryGCP, telemetryPrometheus bool, telemetryServiceName string) (shutdown func(context.Context) error, err error) {
 	var shutdownFuncs []func(context.Context) error
 
 	// shutdown calls cleanup functions registered via shutdownFuncs.
@@ -72,7 +94,7 @@ func SetupOTel(ctx context.Context, versionString, telemetryOTLP string, telemet
 	shutdownFuncs = append(shutdownFuncs, tracerProvider.Shutdown)
 	otel.SetTracerProvider(tracerProvider)
 
-	meterProvider, err := newMeterProvider(ctx, res, telemetryOTLP, telemetryGCP)
+	meterProvider, err := newMeterProvider(ctx, res, telemetryOTLP, telemetryGCP, telemetryPrometheus)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to set up meter provider: %w", err)
 		handleErr(errMsg)
@@ -135,7 +157,7 @@ func newTracerProvider(ctx context.Context, r *resource.Resource, telemetryOTLP
 
 // newMeterProvider creates MeterProvider.
 // MeterProvider is a factory for Meters, and is responsible for creating metrics.
-func newMeterProvider(ctx context.Context, r *resource.Resource, telemetryOTLP string, telemetryGCP bool) (*metric.MeterProvider, error) {
+func newMeterProvider(ctx context.Context, r *resource.Resource, telemetryOTLP string, telemetryGCP, telemetryPrometheus bool) (*metric.MeterProvider, error) {
 	metricOpts := []metric.Option{}
 	if telemetryOTLP != "" {
 		// otlpmetrichttp provides an OTLP metrics exporter using HTTP with protobuf payloads.
@@ -153,6 +175,20 @@ func newMeterProvider(ctx context.Context, r *resource.Resource, telemetryOTLP s
 		}
 		metricOpts = append(metricOpts, metric.WithReader(metric.NewPeriodicReader(gcpExporter)))
 	}
+	if telemetryPrometheus {
+		// metrics are collected when the registry is scraped, so a new
+		// registry is used for every meter provider.
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		promExporter, err := promexporter.New(promexporter.WithRegisterer(registry))
+		if err != nil {
+			return nil, err
+		}
+		metricOpts = append(metricOpts, metric.WithReader(promExporter))
+		promMu.Lock()
+		promRegistry = registry
+		promMu.Unlock()
+	}
 
 	meterProvider := metric.NewMeterProvider(metricOpts...)
 	return meterProvider, nil
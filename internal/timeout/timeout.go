@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timeout bounds the invocation of tools configured with a `timeout`.
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// errTimedOut is the cause of the context of an invocation which timed out.
+var errTimedOut = errors.New("tool timed out")
+
+// Error is returned when the invocation of a tool does not complete before
+// its timeout.
+type Error struct {
+	Tool    string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("tool %q timed out after %s: %s", e.Tool, e.Timeout, e.Err)
+}
+
+// Unwrap returns context.DeadlineExceeded and the error of the invocation.
+func (e *Error) Unwrap() []error { return []error{context.DeadlineExceeded, e.Err} }
+
+// Parse parses the `timeout` of a tool, which must be a positive duration.
+func Parse(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be a positive duration", s)
+	}
+	return d, nil
+}
+
+// ToolConfig wraps the config of a tool with a timeout.
+type ToolConfig struct {
+	tools.ToolConfig
+	Name    string
+	Timeout time.Duration
+}
+
+var _ tools.ComposedToolConfig = ToolConfig{}
+
+// Unwrap returns the config of the wrapped tool.
+func (cfg ToolConfig) Unwrap() tools.ToolConfig { return cfg.ToolConfig }
+
+// ToolNames returns the tools invoked by the wrapped tool.
+func (cfg ToolConfig) ToolNames() []string {
+	return tools.WrappedToolNames(cfg.ToolConfig)
+}
+
+func (cfg ToolConfig) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	return cfg.InitializeWithTools(srcs, nil)
+}
+
+func (cfg ToolConfig) InitializeWithTools(srcs map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	if cfg.Timeout <= 0 {
+		return nil, fmt.Errorf("invalid timeout %s: must be a positive duration", cfg.Timeout)
+	}
+	t, err := tools.InitializeWrapped(cfg.ToolConfig, srcs, toolsMap)
+	if err != nil {
+		return nil, err
+	}
+	return Tool{Tool: t, toolName: cfg.Name, timeout: cfg.Timeout}, nil
+}
+
+// Tool invokes the wrapped tool with a context cancelled after the timeout.
+// Sources cancel the work of the database once the context is done.
+type Tool struct {
+	tools.Tool
+	toolName string
+	timeout  time.Duration
+}
+
+var _ tools.Tool = Tool{}
+
+// Unwrap returns the wrapped tool.
+func (t Tool) Unwrap() tools.Tool { return t.Tool }
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, t.timeout, errTimedOut)
+	defer cancel()
+	res, err := t.Tool.Invoke(ctx, params, accessToken)
+	// the cause tells the timeout of the tool from the deadline of the caller
+	if err != nil && errors.Is(context.Cause(ctx), errTimedOut) {
+		return nil, &Error{Tool: t.toolName, Timeout: t.timeout, Err: err}
+	}
+	return res, err
+}
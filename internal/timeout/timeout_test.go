@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeout_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/timeout"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/dgraph"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/wait"
+)
+
+// delayConfig initializes a fake tool that takes delay to complete, unless
+// its context is done first.
+func delayConfig(delay time.Duration) toolstest.Config {
+	return toolstest.Config{InvokeFunc: func(ctx context.Context, _ tools.ParamValues) (any, error) {
+		select {
+		case <-time.After(delay):
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}}
+}
+
+func TestParseFromYamlTimeout(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: postgres-sql
+			source: my-pg-instance
+			description: some description
+			statement: SELECT 1;
+			timeout: 30s
+		wait_tool:
+			kind: wait
+			description: some description
+			timeout: 1m
+		dgraph_tool:
+			kind: dgraph-dql
+			source: my-dgraph-instance
+			description: some description
+			statement: "{ q() }"
+			isQuery: true
+			timeout: 20s
+	`
+	want := server.ToolConfigs{
+		"example_tool": timeout.ToolConfig{
+			ToolConfig: postgressql.Config{
+				Name:         "example_tool",
+				Kind:         "postgres-sql",
+				Source:       "my-pg-instance",
+				Description:  "some description",
+				Statement:    "SELECT 1;",
+				AuthRequired: []string{},
+			},
+			Name:    "example_tool",
+			Timeout: 30 * time.Second,
+		},
+		// the timeout of a wait tool is its own
+		"wait_tool": wait.Config{
+			Name:         "wait_tool",
+			Kind:         "wait",
+			Description:  "some description",
+			Timeout:      "1m",
+			AuthRequired: []string{},
+		},
+		// and so is the timeout of a dgraph tool, sent with its query
+		"dgraph_tool": dgraph.Config{
+			Name:         "dgraph_tool",
+			Kind:         "dgraph-dql",
+			Source:       "my-dgraph-instance",
+			Description:  "some description",
+			Statement:    "{ q() }",
+			IsQuery:      true,
+			Timeout:      "20s",
+			AuthRequired: []string{},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestFailParseFromYamlTimeout(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc    string
+		timeout string
+		err     string
+	}{
+		{desc: "not a duration", timeout: "soon", err: `invalid timeout "soon"`},
+		{desc: "negative", timeout: "-1s", err: `invalid timeout "-1s"`},
+		{desc: "not a string", timeout: "[1s]", err: "must be a string"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			in := `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: SELECT 1;
+					timeout: ` + tc.timeout
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestTool(t *testing.T) {
+	cfg := timeout.ToolConfig{ToolConfig: delayConfig(time.Minute), Name: "slow", Timeout: 10 * time.Millisecond}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	_, err = tool.Invoke(context.Background(), nil, "")
+	var timedOut *timeout.Error
+	if !errors.As(err, &timedOut) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if timedOut.Tool != "slow" || timedOut.Timeout != 10*time.Millisecond {
+		t.Errorf("unexpected timeout error: %v", timedOut)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected timeout error to be a deadline exceeded error")
+	}
+
+	// the deadline of the caller is not the timeout of the tool
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	cfg.Timeout = time.Minute
+	tool, err = cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	_, err = tool.Invoke(ctx, nil, "")
+	if !errors.Is(err, context.DeadlineExceeded) || errors.As(err, &timedOut) {
+		t.Errorf("expected deadline exceeded error of the caller, got %v", err)
+	}
+
+	cfg.ToolConfig = delayConfig(0)
+	tool, err = cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	res, err := tool.Invoke(context.Background(), nil, "")
+	if err != nil || res != "done" {
+		t.Errorf("expected result of the tool, got %v and %v", res, err)
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	cfg := timeout.ToolConfig{ToolConfig: delayConfig(0), Name: "tool"}
+	if _, err := cfg.Initialize(nil); err == nil || !strings.Contains(err.Error(), "must be a positive duration") {
+		t.Fatalf("expected invalid timeout error, got %v", err)
+	}
+}
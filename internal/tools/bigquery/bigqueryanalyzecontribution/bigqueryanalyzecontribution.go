@@ -25,6 +25,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	bqutil "github.com/googleapis/genai-toolbox/internal/tools/bigquery/bigquerycommon"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 	"google.golang.org/api/iterator"
 )
@@ -227,6 +228,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err != nil {
 		return nil, fmt.Errorf("failed to start create model job: %w", err)
 	}
+	defer bqutil.CancelJobOnDone(ctx, createModelJob)()
 
 	status, err := createModelJob.Wait(ctx)
 	if err != nil {
@@ -251,6 +253,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get insights query: %w", err)
 	}
+	defer bqutil.CancelJobOnDone(ctx, job)()
 	it, err := job.Read(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read query results: %w", err)
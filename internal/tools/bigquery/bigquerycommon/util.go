@@ -17,11 +17,51 @@ package bigquerycommon
 import (
 	"context"
 	"fmt"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	bigqueryrestapi "google.golang.org/api/bigquery/v2"
 )
 
+// cancelTimeout bounds the request cancelling a job.
+const cancelTimeout = 10 * time.Second
+
+// CancelJobOnDone cancels job once ctx is done, since a job keeps running
+// when the client stops waiting for it. The returned function must be called
+// once the job is no longer used, so that a job is not cancelled when the
+// invocation completes normally.
+func CancelJobOnDone(ctx context.Context, job *bigqueryapi.Job) func() bool {
+	return context.AfterFunc(ctx, func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+		defer cancel()
+		_ = job.Cancel(ctx)
+	})
+}
+
+// ReadQuery runs q and returns an iterator over its rows, and a function to
+// call once the rows are read. If ctx has a deadline, q runs as a job which
+// is cancelled if ctx is done before its rows are read.
+func ReadQuery(ctx context.Context, q *bigqueryapi.Query) (*bigqueryapi.RowIterator, func() bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		it, err := q.Read(ctx)
+		return it, func() bool { return false }, err
+	}
+	job, err := q.Run(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := CancelJobOnDone(ctx, job)
+	it, err := job.Read(ctx)
+	if err != nil {
+		// the job is still cancelled if it timed out
+		if ctx.Err() == nil {
+			stop()
+		}
+		return nil, nil, err
+	}
+	return it, stop, nil
+}
+
 // DryRunQuery performs a dry run of the SQL query to validate it and get metadata.
 func DryRunQuery(ctx context.Context, restService *bigqueryrestapi.Service, projectID string, location string, sql string, params []*bigqueryrestapi.QueryParameter, connProps []*bigqueryapi.ConnectionProperty) (*bigqueryrestapi.Job, error) {
 	useLegacySql := false
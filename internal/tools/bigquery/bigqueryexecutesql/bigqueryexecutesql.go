@@ -76,6 +76,10 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsColumnMasks reports that the result columns cannot be masked by
+// name, since the statements of the caller may alias them.
+func (cfg Config) SupportsColumnMasks() bool { return false }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -270,10 +274,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// We iterate through the results, convert each row into a map of
 	// column names to values, and return the collection of rows.
 	var out []any
-	it, err := query.Read(ctx)
+	it, stop, err := bqutil.ReadQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer stop()
 	for {
 		var row map[string]bigqueryapi.Value
 		err = it.Next(&row)
@@ -276,10 +276,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	// We iterate through the results, convert each row into a map of
 	// column names to values, and return the collection of rows.
 	var out []any
-	it, err := query.Read(ctx)
+	it, stop, err := bqutil.ReadQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer stop()
 	for {
 		var row map[string]bigqueryapi.Value
 		err = it.Next(&row)
@@ -68,6 +68,7 @@ type Config struct {
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	DryRun             bool             `yaml:"dryRun"`
 }
 
 // validate interface
@@ -94,6 +95,13 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.DryRun {
+		allParameters, err = tools.WithDryRunParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
@@ -105,6 +113,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Parameters:         cfg.Parameters,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
+		DryRun:             cfg.DryRun,
 
 		Statement:      cfg.Statement,
 		UseClientOAuth: s.UseClientAuthorization(),
@@ -128,6 +137,7 @@ type Tool struct {
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
 	AllParams          tools.Parameters `yaml:"allParams"`
+	DryRun             bool             `yaml:"dryRun"`
 
 	Statement     string
 	Client        *bigqueryapi.Client
@@ -239,14 +249,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("final query validation failed: %w", err)
 	}
 	statementType := dryRunJob.Statistics.Query.StatementType
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return tools.DryRunResult{
+			Statement:           newStatement,
+			Parameters:          boundParameters(highLevelParams),
+			StatementType:       statementType,
+			TotalBytesProcessed: &dryRunJob.Statistics.TotalBytesProcessed,
+		}, nil
+	}
 
 	// This block handles SELECT statements, which return a row set.
 	// We iterate through the results, convert each row into a map of
 	// column names to values, and return the collection of rows.
-	it, err := query.Read(ctx)
+	it, stop, err := bqutil.ReadQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer stop()
 
 	var out []any
 	for {
@@ -301,6 +320,26 @@ func (t Tool) RequiresClientAuthorization() bool {
 	return t.UseClientOAuth
 }
 
+// boundParameters returns the values of named parameters by name, or the
+// values of positional parameters in order.
+func boundParameters(params []bigqueryapi.QueryParameter) any {
+	if len(params) == 0 {
+		return nil
+	}
+	if params[0].Name == "" {
+		values := make([]any, 0, len(params))
+		for _, p := range params {
+			values = append(values, p.Value)
+		}
+		return values
+	}
+	values := make(map[string]any, len(params))
+	for _, p := range params {
+		values[p.Name] = p.Value
+	}
+	return values
+}
+
 func BQTypeStringFromToolType(toolType string) (string, error) {
 	switch toolType {
 	case "string":
@@ -69,6 +69,10 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// OwnsTimeout reports that the tool decodes `timeout`, which is sent to
+// Dgraph with the query.
+func (cfg Config) OwnsTimeout() bool { return true }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DryRunParameterName is the name of the optional argument requesting a dry
+// run of a SQL tool configured with `dryRun`.
+const DryRunParameterName = "dryRun"
+
+// WithDryRunParameter appends the optional dryRun parameter to ps.
+func WithDryRunParameter(ps Parameters) (Parameters, error) {
+	for _, p := range ps {
+		if p.GetName() == DryRunParameterName {
+			return nil, fmt.Errorf("parameter name %q is reserved when dryRun is set", DryRunParameterName)
+		}
+	}
+	dryRun := NewBooleanParameterWithDefault(DryRunParameterName, false, "If true, the statement is not executed. Instead, the resolved statement is returned with an estimate of its impact.")
+	return append(slices.Clone(ps), dryRun), nil
+}
+
+// IsDryRun reports whether the arguments of an invocation request a dry run.
+func IsDryRun(paramsMap map[string]any) bool {
+	dryRun, _ := paramsMap[DryRunParameterName].(bool)
+	return dryRun
+}
+
+// DryRunResult describes what a SQL tool would do, without doing it.
+type DryRunResult struct {
+	// Statement is the statement after resolving template parameters.
+	Statement string `json:"statement"`
+	// Parameters are the values bound to the placeholders of the statement:
+	// a list for positional placeholders, or an object for named ones.
+	Parameters any `json:"parameters,omitempty"`
+	// StatementType is the type of the statement, if known, such as SELECT.
+	StatementType string `json:"statementType,omitempty"`
+	// Plan is the execution plan of the statement.
+	Plan any `json:"plan,omitempty"`
+	// RowsAffected is the number of rows affected by a DML statement,
+	// counted by executing it in a transaction that is rolled back.
+	RowsAffected *int64 `json:"rowsAffected,omitempty"`
+	// TotalBytesProcessed is the estimate of the bytes processed by the
+	// statement.
+	TotalBytesProcessed *int64 `json:"totalBytesProcessed,omitempty"`
+}
+
+// IsDML reports whether statement modifies rows, judging by its first
+// keyword.
+func IsDML(statement string) bool {
+	fields := strings.Fields(strings.TrimLeft(statement, " \t\r\n("))
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "UPSERT", "REPLACE":
+		return true
+	}
+	return false
+}
+
+// TxBeginner begins transactions, such as a *sql.DB or a *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// SQLDryRun runs a dry run of statement in a transaction of db that is
+// rolled back. explain returns the plan of the statement, and DML statements
+// are executed to count the rows they affect.
+func SQLDryRun(ctx context.Context, db TxBeginner, statement string, args []any, explain func(context.Context, *sql.Tx) (any, error)) (DryRunResult, error) {
+	res := DryRunResult{Statement: statement, Parameters: args}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if res.Plan, err = explain(ctx, tx); err != nil {
+		return res, fmt.Errorf("unable to explain statement: %w", err)
+	}
+	if IsDML(statement) {
+		r, err := tx.ExecContext(ctx, statement, args...)
+		if err != nil {
+			return res, fmt.Errorf("unable to execute statement: %w", err)
+		}
+		n, err := r.RowsAffected()
+		if err != nil {
+			return res, fmt.Errorf("unable to get rows affected: %w", err)
+		}
+		res.RowsAffected = &n
+	}
+	return res, nil
+}
+
+// ScanSQLRows returns rows as maps of column names to values. Byte slices are
+// returned as strings.
+func ScanSQLRows(rows *sql.Rows) ([]any, error) {
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get columns: %w", err)
+	}
+	out := []any{}
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		valPtrs := make([]any, len(cols))
+		for i := range vals {
+			valPtrs[i] = &vals[i]
+		}
+		if err := rows.Scan(valPtrs...); err != nil {
+			return nil, fmt.Errorf("unable to scan row: %w", err)
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			if b, ok := vals[i].([]byte); ok {
+				row[col] = string(b)
+				continue
+			}
+			row[col] = vals[i]
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return out, nil
+}
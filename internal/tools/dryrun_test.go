@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestWithDryRunParameter(t *testing.T) {
+	params := tools.Parameters{tools.NewStringParameter("sql", "The sql to execute.")}
+
+	got, err := tools.WithDryRunParameter(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 2 || got[1].GetName() != tools.DryRunParameterName || tools.CheckParamRequired(got[1].GetRequired(), got[1].GetDefault()) {
+		t.Errorf("expected optional %q parameter to be added, got %v", tools.DryRunParameterName, got)
+	}
+	if len(params) != 1 {
+		t.Errorf("expected input parameters to be unchanged")
+	}
+
+	_, err = tools.WithDryRunParameter(got)
+	if err == nil {
+		t.Errorf("expected error for reserved parameter name")
+	}
+
+	values, err := tools.ParseParams(got, map[string]any{"sql": "SELECT 1"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if tools.IsDryRun(values.AsMap()) {
+		t.Errorf("expected dry run to default to false")
+	}
+	values, err = tools.ParseParams(got, map[string]any{"sql": "SELECT 1", "dryRun": true}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !tools.IsDryRun(values.AsMap()) {
+		t.Errorf("expected dry run to be requested")
+	}
+}
+
+func TestIsDML(t *testing.T) {
+	tcs := []struct {
+		statement string
+		want      bool
+	}{
+		{statement: "SELECT * FROM t", want: false},
+		{statement: "WITH x AS (SELECT 1) SELECT * FROM x", want: false},
+		{statement: "  insert INTO t VALUES (1)", want: true},
+		{statement: "UPDATE t SET a = 1", want: true},
+		{statement: "\nDELETE FROM t", want: true},
+		{statement: "MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN DELETE", want: true},
+		{statement: "UPSERT t VALUES (1) WITH PRIMARY KEY", want: true},
+		{statement: "CREATE TABLE t (a INT)", want: false},
+		{statement: "", want: false},
+	}
+	for _, tc := range tcs {
+		if got := tools.IsDML(tc.statement); got != tc.want {
+			t.Errorf("IsDML(%q) = %t, want %t", tc.statement, got, tc.want)
+		}
+	}
+}
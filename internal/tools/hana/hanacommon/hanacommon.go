@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hanacommon contains helpers shared by the SAP HANA tools.
+package hanacommon
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// planColumns are the columns of EXPLAIN_PLAN_TABLE returned as the plan.
+const planColumns = "OPERATOR_ID, PARENT_OPERATOR_ID, LEVEL, OPERATOR_NAME, OPERATOR_DETAILS, " +
+	"SCHEMA_NAME, TABLE_NAME, TABLE_TYPE, TABLE_SIZE, OUTPUT_SIZE, SUBTREE_COST"
+
+// cancelTimeout bounds the statement cancelling the session of a connection.
+const cancelTimeout = 10 * time.Second
+
+// maxCachedConnectionIDs bounds the connection IDs cached by Conn. The cache
+// is cleared once full, as the IDs of closed connections are not removed.
+const maxCachedConnectionIDs = 256
+
+// connectionIDs caches the connection ID of the physical connections, keyed
+// by their driver connection, so that it is looked up once per connection
+// rather than once per query.
+var connectionIDs = struct {
+	sync.Mutex
+	ids map[any]int64
+}{ids: make(map[any]int64)}
+
+// Conn returns a connection of db, and a function releasing it once it is no
+// longer used. When the context of a query is done, the driver abandons the
+// session of the connection but the server completes the query. So if ctx can
+// be done, by a deadline or a cancellation, the session is cancelled with
+// CANCEL SESSION once ctx is done, and the connection is then discarded rather
+// than reused.
+func Conn(ctx context.Context, db *sql.DB) (*sql.Conn, func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	if ctx.Done() == nil {
+		return conn, func() { _ = conn.Close() }, nil
+	}
+	key, id, err := connectionID(ctx, conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("unable to get connection id: %w", err)
+	}
+	cancelled := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(cancelled)
+		cancelSession(ctx, db, id)
+	})
+	return conn, func() {
+		if !stop() {
+			// the session is being cancelled, which must not cancel the
+			// statements of the next user of the connection
+			<-cancelled
+			connectionIDs.Lock()
+			delete(connectionIDs.ids, key)
+			connectionIDs.Unlock()
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}, nil
+}
+
+// connectionID returns the driver connection of conn and its connection ID,
+// which is looked up the first time the connection is used.
+func connectionID(ctx context.Context, conn *sql.Conn) (any, int64, error) {
+	var key any
+	if err := conn.Raw(func(dc any) error {
+		key = dc
+		return nil
+	}); err != nil {
+		return nil, 0, err
+	}
+	connectionIDs.Lock()
+	id, ok := connectionIDs.ids[key]
+	connectionIDs.Unlock()
+	if ok {
+		return key, id, nil
+	}
+	if err := conn.QueryRowContext(ctx, "SELECT CURRENT_CONNECTION FROM DUMMY").Scan(&id); err != nil {
+		return nil, 0, err
+	}
+	connectionIDs.Lock()
+	if len(connectionIDs.ids) >= maxCachedConnectionIDs {
+		clear(connectionIDs.ids)
+	}
+	connectionIDs.ids[key] = id
+	connectionIDs.Unlock()
+	return key, id, nil
+}
+
+// cancelSession cancels the statement running in the session id.
+func cancelSession(ctx context.Context, db *sql.DB, id int64) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER SYSTEM CANCEL SESSION '%d'", id)); err != nil {
+		if logger, lerr := util.LoggerFromContext(ctx); lerr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("unable to cancel session %d: %s", id, err))
+		}
+	}
+}
+
+// DryRun returns the EXPLAIN PLAN output of statement, and the number of rows
+// it affects if it is a DML statement, in a transaction that is rolled back.
+// The transaction runs on a connection of Conn, so that its statements are
+// cancelled with ctx.
+func DryRun(ctx context.Context, db *sql.DB, statement string, args []any) (tools.DryRunResult, error) {
+	conn, release, err := Conn(ctx, db)
+	if err != nil {
+		return tools.DryRunResult{Statement: statement, Parameters: args}, err
+	}
+	defer release()
+	return tools.SQLDryRun(ctx, conn, statement, args, func(ctx context.Context, tx *sql.Tx) (any, error) {
+		// the plan is written to EXPLAIN_PLAN_TABLE, under a unique name,
+		// and discarded with the transaction
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		name := "toolbox_" + hex.EncodeToString(b)
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_NAME = '%s' FOR %s", name, statement), args...); err != nil {
+			return nil, err
+		}
+		rows, err := tx.QueryContext(ctx, "SELECT "+planColumns+" FROM EXPLAIN_PLAN_TABLE WHERE STATEMENT_NAME = ? ORDER BY OPERATOR_ID", name)
+		if err != nil {
+			return nil, err
+		}
+		return tools.ScanSQLRows(rows)
+	})
+}
+
+// FilterStatement applies the row filters of ctx to statement. See
+// tools.FilterStatement.
+func FilterStatement(ctx context.Context, statement string, args []any) (string, []any, error) {
+	return tools.FilterStatement(statement, args, tools.RowFiltersFromContext(ctx), func(int) string { return "?" }, tools.QuoteIdentifier)
+}
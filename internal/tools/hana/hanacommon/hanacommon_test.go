@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanacommon
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDriver records the statements of its connections, which are numbered
+// from 1 in the order they are opened.
+type fakeDriver struct {
+	mu         sync.Mutex
+	opened     int
+	closed     []int
+	statements []string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.opened++
+	return &fakeConn{d: d, id: d.opened}, nil
+}
+
+func (d *fakeDriver) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, statement)
+}
+
+func (d *fakeDriver) snapshot() (statements []string, closed []int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.statements), slices.Clone(d.closed)
+}
+
+type fakeConn struct {
+	d  *fakeDriver
+	id int
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, fmt.Errorf("not supported") }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, fmt.Errorf("not supported") }
+
+func (c *fakeConn) Close() error {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.closed = append(c.d.closed, c.id)
+	return nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query)
+	return &fakeRows{id: int64(c.id)}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+// fakeRows returns the id of the connection.
+type fakeRows struct {
+	id   int64
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"CURRENT_CONNECTION"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.id
+	return nil
+}
+
+type fakeConnector struct{ d *fakeDriver }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c fakeConnector) Driver() driver.Driver                        { return c.d }
+
+func TestConn(t *testing.T) {
+	d := &fakeDriver{}
+	db := sql.OpenDB(fakeConnector{d})
+	defer db.Close()
+	db.SetMaxIdleConns(1)
+
+	// the connection id is looked up once per connection, and the session is
+	// not cancelled once released
+	for range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		_, release, err := Conn(ctx, db)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		release()
+		cancel()
+	}
+	statements, closed := d.snapshot()
+	if want := []string{"SELECT CURRENT_CONNECTION FROM DUMMY"}; !slices.Equal(statements, want) {
+		t.Fatalf("unexpected statements: got %q, want %q", statements, want)
+	}
+	if len(closed) != 0 {
+		t.Fatalf("unexpected closed connections: %v", closed)
+	}
+
+	// contexts that cannot be done do not need the connection id
+	_, release, err := Conn(context.Background(), db)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	release()
+	if statements, _ := d.snapshot(); len(statements) != 1 {
+		t.Fatalf("unexpected statements: %q", statements)
+	}
+
+	// once the context is done, even without a deadline, the session is
+	// cancelled and the connection is discarded
+	ctx, cancel := context.WithCancel(context.Background())
+	_, release, err = Conn(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cancel()
+	release()
+	statements, closed = d.snapshot()
+	if want := "ALTER SYSTEM CANCEL SESSION '1'"; !slices.Contains(statements, want) {
+		t.Errorf("expected statement %q, got %q", want, statements)
+	}
+	if !slices.Contains(closed, 1) {
+		t.Errorf("expected connection 1 to be discarded, got closed connections %v", closed)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanacommon
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-hdb/driver"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// DefaultMaxLobBytes is the number of bytes of a LOB value returned by
+// default.
+const DefaultMaxLobBytes = 1 << 20
+
+// maxDecimalScale is the largest scale of a fixed DECIMAL. Floating
+// DECIMAL columns report a larger scale.
+const maxDecimalScale = 38
+
+// Database type names of ST_GEOMETRY and ST_POINT columns. go-hdb names
+// types after its type codes, tcStGeometry and tcStPoint, rather than after
+// their SQL names.
+const (
+	typeNameStGeometry = "STGEOMETRY"
+	typeNameStPoint    = "STPOINT"
+)
+
+// Spatial formats of ST_GEOMETRY and ST_POINT values.
+const (
+	SpatialWKT     = "wkt"
+	SpatialGeoJSON = "geojson"
+)
+
+// ScanOptions configures the mapping of the values of a result.
+type ScanOptions struct {
+	// MaxLobBytes is the number of bytes read of a LOB value. Defaults to
+	// DefaultMaxLobBytes.
+	MaxLobBytes int
+	// SpatialFormat is SpatialWKT or SpatialGeoJSON. Defaults to
+	// SpatialWKT.
+	SpatialFormat string
+}
+
+// ScanOptionsOf returns the scan options configured on the source s.
+func ScanOptionsOf(s sources.Source) ScanOptions {
+	var opts ScanOptions
+	if l, ok := s.(interface{ MaxLobBytes() int }); ok {
+		opts.MaxLobBytes = l.MaxLobBytes()
+	}
+	if f, ok := s.(interface{ SpatialFormat() string }); ok {
+		opts.SpatialFormat = f.SpatialFormat()
+	}
+	return opts
+}
+
+// TruncatedLob is the value of a LOB larger than MaxLobBytes.
+type TruncatedLob struct {
+	// Value is the text of a CLOB or NCLOB, or the base64 encoding of a
+	// BLOB, cut off after MaxLobBytes bytes.
+	Value string `json:"value"`
+	// Size is the size of the LOB in bytes.
+	Size      int64 `json:"size"`
+	Truncated bool  `json:"truncated"`
+}
+
+// ScanRows scans rows into maps of column names to values, calling add with
+// each row until it returns false. The order of the columns is recorded in the
+// ColumnOrder of ctx. Values are mapped by the type of their column:
+//
+//   - DECIMAL values are exact decimal strings.
+//   - LOB values are streamed, keeping at most MaxLobBytes bytes.
+//   - BINARY and BLOB values are encoded in base64.
+//   - ST_GEOMETRY and ST_POINT values are WKT strings or GeoJSON objects.
+//   - DATE, TIME, SECONDDATE and TIMESTAMP values are ISO 8601 strings,
+//     without a zone since SAP HANA does not store one.
+func ScanRows(ctx context.Context, rows *sql.Rows, opts ScanOptions, add func(map[string]any) bool) error {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return fmt.Errorf("unable to get column types: %w", err)
+	}
+	names := make([]string, len(types))
+	cols := make([]*column, len(types))
+	dest := make([]any, len(types))
+	for i, ct := range types {
+		names[i] = ct.Name()
+		cols[i] = newColumn(ct.DatabaseTypeName(), opts)
+		if _, scale, ok := ct.DecimalSize(); ok && scale >= 0 && scale <= maxDecimalScale {
+			cols[i].scale = int(scale)
+		}
+		dest[i] = cols[i]
+	}
+	tools.SetColumns(ctx, names)
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		row := make(map[string]any, len(types))
+		for i, name := range names {
+			row[name] = cols[i].value
+		}
+		if !add(row) {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
+
+// column is a sql.Scanner mapping the values of a column by its type.
+type column struct {
+	typeName string
+	// scale is the scale of a fixed DECIMAL column, or -1.
+	scale int
+	opts  ScanOptions
+	value any
+}
+
+func newColumn(typeName string, opts ScanOptions) *column {
+	if opts.MaxLobBytes <= 0 {
+		opts.MaxLobBytes = DefaultMaxLobBytes
+	}
+	return &column{typeName: typeName, scale: -1, opts: opts}
+}
+
+func (c *column) Scan(src any) error {
+	if src == nil {
+		c.value = nil
+		return nil
+	}
+	var err error
+	switch c.typeName {
+	case "CLOB", "NCLOB", "TEXT", "BLOB", "BINTEXT", "LOCATOR", "NLOCATOR":
+		c.value, err = c.lob(src)
+	case typeNameStGeometry, typeNameStPoint:
+		c.value, err = c.spatial(src)
+	case "DECIMAL", "SMALLDECIMAL", "FIXED8", "FIXED12", "FIXED16":
+		c.value, err = c.decimal(src)
+	case "BINARY", "VARBINARY":
+		c.value, err = binaryValue(src)
+	case "DATE", "DAYDATE":
+		c.value, err = timeValue(src, time.DateOnly)
+	case "TIME", "SECONDTIME":
+		c.value, err = timeValue(src, time.TimeOnly)
+	case "SECONDDATE":
+		c.value, err = timeValue(src, "2006-01-02T15:04:05")
+	case "TIMESTAMP", "LONGDATE":
+		c.value, err = timeValue(src, "2006-01-02T15:04:05.9999999")
+	default:
+		// the driver reuses the buffers of []byte values
+		if b, ok := src.([]byte); ok {
+			src = string(b)
+		}
+		c.value = src
+	}
+	if err != nil {
+		return fmt.Errorf("unable to convert %s value: %w", c.typeName, err)
+	}
+	return nil
+}
+
+// lob streams a LOB value, keeping at most MaxLobBytes bytes.
+func (c *column) lob(src any) (any, error) {
+	w := &lobWriter{limit: c.opts.MaxLobBytes}
+	if err := driver.ScanLobWriter(src, w); err != nil {
+		return nil, err
+	}
+	return lobValue(w, c.typeName == "BLOB"), nil
+}
+
+// lobValue returns the value of the LOB read by w.
+func lobValue(w *lobWriter, binary bool) any {
+	var s string
+	if binary {
+		s = base64.StdEncoding.EncodeToString(w.buf.Bytes())
+	} else {
+		s = w.buf.String()
+	}
+	if w.size <= int64(w.limit) {
+		return s
+	}
+	if !binary {
+		// the limit may split the last character
+		s = strings.ToValidUTF8(s, "")
+	}
+	return TruncatedLob{Value: s, Size: w.size, Truncated: true}
+}
+
+// lobWriter keeps the first limit bytes written to it, and counts the
+// others. Writing never fails, so that the driver reads the LOB to the end.
+type lobWriter struct {
+	buf   bytes.Buffer
+	limit int
+	size  int64
+}
+
+func (w *lobWriter) Write(p []byte) (int, error) {
+	w.size += int64(len(p))
+	if n := w.limit - w.buf.Len(); n > 0 {
+		w.buf.Write(p[:min(n, len(p))])
+	}
+	return len(p), nil
+}
+
+// spatial decodes a spatial value, which the driver returns as hex encoded
+// WKB.
+func (c *column) spatial(src any) (any, error) {
+	var b []byte
+	var err error
+	switch v := src.(type) {
+	case string:
+		b, err = hex.DecodeString(v)
+	case []byte:
+		b, err = hex.DecodeString(string(v))
+	default:
+		return nil, fmt.Errorf("unexpected type %T", src)
+	}
+	if err != nil {
+		return nil, err
+	}
+	g, err := decodeWKB(b)
+	if err != nil {
+		return nil, err
+	}
+	if c.opts.SpatialFormat == SpatialGeoJSON {
+		return g.geoJSON(), nil
+	}
+	return g.wkt(), nil
+}
+
+// decimal returns the exact decimal string of a DECIMAL value, with the
+// scale of its column if it is fixed.
+func (c *column) decimal(src any) (any, error) {
+	var r *big.Rat
+	switch v := src.(type) {
+	case *big.Rat:
+		r = v
+	case []byte:
+		return string(v), nil
+	case string:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unexpected type %T", src)
+	}
+	if c.scale >= 0 {
+		return r.FloatString(c.scale), nil
+	}
+	return tools.DecimalString(r), nil
+}
+
+func binaryValue(src any) (any, error) {
+	b, ok := src.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", src)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func timeValue(src any, layout string) (any, error) {
+	t, ok := src.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", src)
+	}
+	return t.Format(layout), nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanacommon
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// driverTypeName returns the database type name go-hdb reports for the
+// columns of a type code, named as in its protocol package.
+func driverTypeName(typeCode string) string {
+	return strings.ToUpper(strings.TrimPrefix(typeCode, "tc"))
+}
+
+func TestColumnScan(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 5, 6, 7, 891000000, time.UTC)
+	tcs := []struct {
+		desc     string
+		typeName string
+		scale    int
+		opts     ScanOptions
+		src      any
+		want     any
+	}{
+		{desc: "null", typeName: "DECIMAL", src: nil, want: nil},
+		{desc: "fixed decimal", typeName: "DECIMAL", scale: 3, src: big.NewRat(5, 2), want: "2.500"},
+		{desc: "floating decimal", typeName: "DECIMAL", scale: -1, src: big.NewRat(1, 3000000000000), want: "0.0000000000003333333333333333333333"},
+		{desc: "floating decimal exact", typeName: "DECIMAL", scale: -1, src: big.NewRat(1, 8), want: "0.125"},
+		{desc: "varbinary", typeName: "VARBINARY", src: []byte{0xde, 0xad, 0xbe, 0xef}, want: "3q2+7w=="},
+		{desc: "varchar", typeName: "VARCHAR", src: []byte("abc"), want: "abc"},
+		{desc: "nvarchar", typeName: "NVARCHAR", src: "äbc", want: "äbc"},
+		{desc: "integer", typeName: "INTEGER", src: int64(42), want: int64(42)},
+		{desc: "date", typeName: "DATE", src: ts, want: "2025-03-04"},
+		{desc: "time", typeName: "TIME", src: ts, want: "05:06:07"},
+		{desc: "seconddate", typeName: "SECONDDATE", src: ts, want: "2025-03-04T05:06:07"},
+		{desc: "timestamp", typeName: "TIMESTAMP", src: ts, want: "2025-03-04T05:06:07.891"},
+		{desc: "point wkt", typeName: driverTypeName("tcStPoint"), src: hex.EncodeToString(wkbPointBytes(1.5, -2)), want: "POINT (1.5 -2)"},
+		{desc: "point geojson", typeName: driverTypeName("tcStGeometry"), opts: ScanOptions{SpatialFormat: SpatialGeoJSON}, src: hex.EncodeToString(wkbPointBytes(1.5, -2)), want: map[string]any{"type": "Point", "coordinates": []float64{1.5, -2}}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newColumn(tc.typeName, tc.opts)
+			c.scale = tc.scale
+			if err := c.Scan(tc.src); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, c.value); diff != "" {
+				t.Fatalf("incorrect value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestColumnScanError(t *testing.T) {
+	c := newColumn("DATE", ScanOptions{})
+	if err := c.Scan("2025-03-04"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestLobValue(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		limit  int
+		chunks []string
+		binary bool
+		want   any
+	}{
+		{desc: "text", limit: 16, chunks: []string{"hello ", "world"}, want: "hello world"},
+		{desc: "binary", limit: 10, chunks: []string{"\x00\x01"}, binary: true, want: "AAE="},
+		{desc: "truncated text", limit: 4, chunks: []string{"ab", "cäd"}, want: TruncatedLob{Value: "abc", Size: 6, Truncated: true}},
+		{desc: "truncated binary", limit: 2, chunks: []string{"\x00\x01\x02"}, binary: true, want: TruncatedLob{Value: "AAE=", Size: 3, Truncated: true}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			w := &lobWriter{limit: tc.limit}
+			for _, c := range tc.chunks {
+				if n, err := w.Write([]byte(c)); err != nil || n != len(c) {
+					t.Fatalf("unexpected write result %d, %v", n, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, lobValue(w, tc.binary)); diff != "" {
+				t.Fatalf("incorrect value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeWKB(t *testing.T) {
+	polygon := wkbHeader(binary.LittleEndian, wkbPolygon)
+	polygon = binary.LittleEndian.AppendUint32(polygon, 1)
+	polygon = binary.LittleEndian.AppendUint32(polygon, 4)
+	for _, c := range []float64{0, 0, 1, 0, 1, 1, 0, 0} {
+		polygon = binary.LittleEndian.AppendUint64(polygon, math.Float64bits(c))
+	}
+
+	pointZ := wkbHeader(binary.BigEndian, wkbPoint+1000)
+	for _, c := range []float64{1, 2, 3} {
+		pointZ = binary.BigEndian.AppendUint64(pointZ, math.Float64bits(c))
+	}
+
+	// extended WKB with an SRID
+	pointSRID := wkbHeader(binary.LittleEndian, wkbPoint|ewkbSRID)
+	pointSRID = binary.LittleEndian.AppendUint32(pointSRID, 4326)
+	pointSRID = append(pointSRID, wkbPointBytes(3, 4)[5:]...)
+
+	collection := wkbHeader(binary.LittleEndian, wkbGeometryCollection)
+	collection = binary.LittleEndian.AppendUint32(collection, 2)
+	collection = append(collection, wkbPointBytes(1, 2)...)
+	collection = append(collection, pointZ...)
+
+	emptyPoint := wkbPointBytes(math.NaN(), math.NaN())
+
+	tcs := []struct {
+		desc        string
+		in          []byte
+		wantWKT     string
+		wantGeoJSON map[string]any
+	}{
+		{
+			desc:        "polygon",
+			in:          polygon,
+			wantWKT:     "POLYGON ((0 0, 1 0, 1 1, 0 0))",
+			wantGeoJSON: map[string]any{"type": "Polygon", "coordinates": []any{[][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		},
+		{
+			desc:        "point z",
+			in:          pointZ,
+			wantWKT:     "POINT Z (1 2 3)",
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{1, 2, 3}},
+		},
+		{
+			desc:        "point with srid",
+			in:          pointSRID,
+			wantWKT:     "POINT (3 4)",
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{3, 4}},
+		},
+		{
+			desc:    "collection",
+			in:      collection,
+			wantWKT: "GEOMETRYCOLLECTION (POINT (1 2), POINT Z (1 2 3))",
+			wantGeoJSON: map[string]any{"type": "GeometryCollection", "geometries": []any{
+				map[string]any{"type": "Point", "coordinates": []float64{1, 2}},
+				map[string]any{"type": "Point", "coordinates": []float64{1, 2, 3}},
+			}},
+		},
+		{
+			desc:        "empty point",
+			in:          emptyPoint,
+			wantWKT:     "POINT EMPTY",
+			wantGeoJSON: map[string]any{"type": "Point", "coordinates": []float64{}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			g, err := decodeWKB(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := g.wkt(); got != tc.wantWKT {
+				t.Fatalf("incorrect WKT: got %q, want %q", got, tc.wantWKT)
+			}
+			if diff := cmp.Diff(tc.wantGeoJSON, g.geoJSON()); diff != "" {
+				t.Fatalf("incorrect GeoJSON (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeWKBError(t *testing.T) {
+	for _, in := range [][]byte{nil, {2}, wkbPointBytes(1, 2)[:12], append(wkbPointBytes(1, 2), 0), wkbHeader(binary.LittleEndian, 99)} {
+		if _, err := decodeWKB(in); err == nil {
+			t.Fatalf("expected error decoding %x", in)
+		}
+	}
+}
+
+func wkbHeader(order binary.AppendByteOrder, typ uint32) []byte {
+	b := []byte{0}
+	if order == binary.LittleEndian {
+		b[0] = 1
+	}
+	return order.AppendUint32(b, typ)
+}
+
+func wkbPointBytes(x, y float64) []byte {
+	b := wkbHeader(binary.LittleEndian, wkbPoint)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(x))
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(y))
+}
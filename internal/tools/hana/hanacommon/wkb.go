@@ -0,0 +1,327 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanacommon
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WKB geometry types.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// Flags of the geometry type of extended WKB.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+var wktNames = map[uint32]string{
+	wkbPoint:              "POINT",
+	wkbLineString:         "LINESTRING",
+	wkbPolygon:            "POLYGON",
+	wkbMultiPoint:         "MULTIPOINT",
+	wkbMultiLineString:    "MULTILINESTRING",
+	wkbMultiPolygon:       "MULTIPOLYGON",
+	wkbGeometryCollection: "GEOMETRYCOLLECTION",
+}
+
+var geoJSONTypes = map[uint32]string{
+	wkbPoint:              "Point",
+	wkbLineString:         "LineString",
+	wkbPolygon:            "Polygon",
+	wkbMultiPoint:         "MultiPoint",
+	wkbMultiLineString:    "MultiLineString",
+	wkbMultiPolygon:       "MultiPolygon",
+	wkbGeometryCollection: "GeometryCollection",
+}
+
+// geometry is a geometry decoded from WKB.
+type geometry struct {
+	typ        uint32
+	hasZ, hasM bool
+	// points are the points of a point, which has none if it is empty, or
+	// of a line string.
+	points [][]float64
+	// rings are the rings of a polygon.
+	rings [][][]float64
+	// parts are the geometries of a multi geometry or a collection.
+	parts []geometry
+}
+
+// decodeWKB decodes a geometry in WKB, ISO WKB or extended WKB.
+func decodeWKB(b []byte) (geometry, error) {
+	r := &wkbReader{b: b}
+	g := r.geometry()
+	if r.err != nil {
+		return geometry{}, fmt.Errorf("invalid WKB: %w", r.err)
+	}
+	if len(r.b) > 0 {
+		return geometry{}, fmt.Errorf("invalid WKB: %d trailing bytes", len(r.b))
+	}
+	return g, nil
+}
+
+type wkbReader struct {
+	b     []byte
+	order binary.ByteOrder
+	err   error
+}
+
+func (r *wkbReader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 4 {
+		r.err = fmt.Errorf("unexpected end of data")
+		return 0
+	}
+	v := r.order.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *wkbReader) float64() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 8 {
+		r.err = fmt.Errorf("unexpected end of data")
+		return 0
+	}
+	v := math.Float64frombits(r.order.Uint64(r.b))
+	r.b = r.b[8:]
+	return v
+}
+
+// count reads the number of elements of a geometry, each taking at least
+// size bytes.
+func (r *wkbReader) count(size int) int {
+	n := r.uint32()
+	if r.err == nil && uint64(n)*uint64(size) > uint64(len(r.b)) {
+		r.err = fmt.Errorf("unexpected end of data")
+		return 0
+	}
+	return int(n)
+}
+
+func (r *wkbReader) geometry() geometry {
+	if r.err != nil {
+		return geometry{}
+	}
+	if len(r.b) < 1 {
+		r.err = fmt.Errorf("unexpected end of data")
+		return geometry{}
+	}
+	switch r.b[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		r.err = fmt.Errorf("invalid byte order %d", r.b[0])
+		return geometry{}
+	}
+	r.b = r.b[1:]
+
+	typ := r.uint32()
+	g := geometry{hasZ: typ&ewkbZ != 0, hasM: typ&ewkbM != 0}
+	if typ&ewkbSRID != 0 {
+		r.uint32()
+	}
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	switch typ / 1000 {
+	case 1:
+		g.hasZ = true
+	case 2:
+		g.hasM = true
+	case 3:
+		g.hasZ, g.hasM = true, true
+	}
+	g.typ = typ % 1000
+	dims := 2
+	if g.hasZ {
+		dims++
+	}
+	if g.hasM {
+		dims++
+	}
+
+	switch g.typ {
+	case wkbPoint:
+		p := r.point(dims)
+		// empty points have NaN coordinates
+		if !math.IsNaN(p[0]) {
+			g.points = [][]float64{p}
+		}
+	case wkbLineString:
+		g.points = r.points(dims)
+	case wkbPolygon:
+		n := r.count(4)
+		for range n {
+			g.rings = append(g.rings, r.points(dims))
+		}
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		n := r.count(5)
+		for range n {
+			order := r.order
+			g.parts = append(g.parts, r.geometry())
+			r.order = order
+		}
+	default:
+		r.err = fmt.Errorf("unsupported geometry type %d", typ)
+	}
+	return g
+}
+
+func (r *wkbReader) point(dims int) []float64 {
+	p := make([]float64, dims)
+	for i := range p {
+		p[i] = r.float64()
+	}
+	return p
+}
+
+func (r *wkbReader) points(dims int) [][]float64 {
+	n := r.count(8 * dims)
+	ps := make([][]float64, 0, n)
+	for range n {
+		ps = append(ps, r.point(dims))
+	}
+	return ps
+}
+
+// wkt returns the WKT of g, such as `POINT Z (1 2 3)`.
+func (g geometry) wkt() string {
+	var sb strings.Builder
+	sb.WriteString(wktNames[g.typ])
+	switch {
+	case g.hasZ && g.hasM:
+		sb.WriteString(" ZM")
+	case g.hasZ:
+		sb.WriteString(" Z")
+	case g.hasM:
+		sb.WriteString(" M")
+	}
+	sb.WriteByte(' ')
+	g.writeWKTBody(&sb)
+	return sb.String()
+}
+
+// writeWKTBody writes the coordinates of g, without its type.
+func (g geometry) writeWKTBody(sb *strings.Builder) {
+	switch g.typ {
+	case wkbPoint, wkbLineString:
+		writeWKTPoints(sb, g.points)
+	case wkbPolygon:
+		writeWKTList(sb, len(g.rings), func(i int) { writeWKTPoints(sb, g.rings[i]) })
+	case wkbGeometryCollection:
+		writeWKTList(sb, len(g.parts), func(i int) { sb.WriteString(g.parts[i].wkt()) })
+	default:
+		writeWKTList(sb, len(g.parts), func(i int) { g.parts[i].writeWKTBody(sb) })
+	}
+}
+
+func writeWKTPoints(sb *strings.Builder, points [][]float64) {
+	writeWKTList(sb, len(points), func(i int) {
+		for j, c := range points[i] {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(strconv.FormatFloat(c, 'f', -1, 64))
+		}
+	})
+}
+
+func writeWKTList(sb *strings.Builder, n int, write func(i int)) {
+	if n == 0 {
+		sb.WriteString("EMPTY")
+		return
+	}
+	sb.WriteByte('(')
+	for i := range n {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		write(i)
+	}
+	sb.WriteByte(')')
+}
+
+// geoJSON returns the GeoJSON object of g. GeoJSON has no measures, so
+// M coordinates are dropped.
+func (g geometry) geoJSON() map[string]any {
+	typ := geoJSONTypes[g.typ]
+	if g.typ == wkbGeometryCollection {
+		geometries := make([]any, len(g.parts))
+		for i, p := range g.parts {
+			geometries[i] = p.geoJSON()
+		}
+		return map[string]any{"type": typ, "geometries": geometries}
+	}
+	return map[string]any{"type": typ, "coordinates": g.coordinates()}
+}
+
+func (g geometry) coordinates() any {
+	switch g.typ {
+	case wkbPoint:
+		if len(g.points) == 0 {
+			return []float64{}
+		}
+		return g.position(g.points[0])
+	case wkbLineString:
+		return g.positions(g.points)
+	case wkbPolygon:
+		rings := make([]any, len(g.rings))
+		for i, ring := range g.rings {
+			rings[i] = g.positions(ring)
+		}
+		return rings
+	default:
+		parts := make([]any, len(g.parts))
+		for i, p := range g.parts {
+			parts[i] = p.coordinates()
+		}
+		return parts
+	}
+}
+
+func (g geometry) positions(points [][]float64) [][]float64 {
+	ps := make([][]float64, len(points))
+	for i, p := range points {
+		ps[i] = g.position(p)
+	}
+	return ps
+}
+
+// position returns the X, Y and Z coordinates of p.
+func (g geometry) position(p []float64) []float64 {
+	if g.hasZ {
+		return p[:3]
+	}
+	return p[:2]
+}
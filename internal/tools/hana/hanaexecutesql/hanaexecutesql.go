@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/hana"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hana/hanacommon"
 )
 
 const kind string = "hana-execute-sql"
@@ -42,7 +43,7 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 }
 
 type compatibleSource interface {
-	HanaDB() *sql.DB
+	HanaDBContext(context.Context) (*sql.DB, error)
 }
 
 var _ compatibleSource = &hana.Source{}
@@ -50,17 +51,24 @@ var _ compatibleSource = &hana.Source{}
 var compatibleSources = [...]string{hana.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name           string   `yaml:"name" validate:"required"`
+	Kind           string   `yaml:"kind" validate:"required"`
+	Source         string   `yaml:"source" validate:"required"`
+	Description    string   `yaml:"description" validate:"required"`
+	AuthRequired   []string `yaml:"authRequired"`
+	MaxRows        int      `yaml:"maxRows"`
+	MaxResultBytes int      `yaml:"maxResultBytes"`
+	DryRun         bool     `yaml:"dryRun"`
 }
 
 var _ tools.ToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string { return kind }
 
+// SupportsColumnMasks reports that the result columns cannot be masked by
+// name, since the statements of the caller may alias them.
+func (cfg Config) SupportsColumnMasks() bool { return false }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -73,7 +81,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParam := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParam}
+	limits := tools.ResolveResultLimits(tools.ResultLimits{MaxRows: cfg.MaxRows, MaxResultBytes: cfg.MaxResultBytes}, rawS)
+	parameters, err := limits.WithPageTokenParameter(tools.Parameters{sqlParam})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DryRun {
+		parameters, err = tools.WithDryRunParameter(parameters)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -82,7 +100,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Kind:         kind,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
-		DB:           s.HanaDB(),
+		Limits:       limits,
+		DryRun:       cfg.DryRun,
+		Source:       s,
+		scanOptions:  hanacommon.ScanOptionsOf(rawS),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
 	}
@@ -92,20 +113,23 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
-
-	DB          *sql.DB
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
+	Limits       tools.ResultLimits `yaml:"limits"`
+	DryRun       bool               `yaml:"dryRun"`
+
+	Source      compatibleSource
+	scanOptions hanacommon.ScanOptions
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	db := t.DB
-	if db == nil {
-		return nil, fmt.Errorf("database connection is nil")
+	db, err := t.Source.HanaDBContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract the SQL statement from the parameters
@@ -114,47 +138,31 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if !ok {
 		return nil, fmt.Errorf("required parameter 'sql' not provided")
 	}
-
-	rows, err := db.QueryContext(ctx, sqlValue)
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return hanacommon.DryRun(ctx, db, sqlValue, nil)
+	}
+	pageToken, _ := paramsMap[tools.PageTokenParameterName].(string)
+	collector, err := tools.NewRowCollector(ctx, t.Limits, pageToken, tools.QueryFingerprint(sqlValue))
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	cols, err := rows.Columns()
+	conn, release, err := hanacommon.Conn(ctx, db)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get columns: %w", err)
+		return nil, err
 	}
+	defer release()
 
-	var results []map[string]any
-	for rows.Next() {
-		vals := make([]any, len(cols))
-		valPtrs := make([]any, len(cols))
-		for i := range vals {
-			valPtrs[i] = &vals[i]
-		}
-
-		if err := rows.Scan(valPtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-
-		rowMap := make(map[string]any)
-		for i, col := range cols {
-			switch v := vals[i].(type) {
-			case []byte:
-				rowMap[col] = string(v)
-			default:
-				rowMap[col] = v
-			}
-		}
-		results = append(results, rowMap)
+	rows, err := conn.QueryContext(ctx, sqlValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
+	defer rows.Close()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+	if err := hanacommon.ScanRows(ctx, rows, t.scanOptions, func(row map[string]any) bool { return collector.Add(row) }); err != nil {
+		return nil, err
 	}
-
-	return results, nil
+	return collector.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
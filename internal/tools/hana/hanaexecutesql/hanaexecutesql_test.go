@@ -55,6 +55,50 @@ func TestParseFromYamlHanaExecuteSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with result limits",
+			in: `
+            tools:
+                exec_tool:
+                    kind: hana-execute-sql
+                    source: my-hana-instance
+                    description: execute any sql
+                    maxRows: 100
+                    maxResultBytes: 1048576
+            `,
+			want: server.ToolConfigs{
+				"exec_tool": hanaexecutesql.Config{
+					Name:           "exec_tool",
+					Kind:           "hana-execute-sql",
+					Source:         "my-hana-instance",
+					Description:    "execute any sql",
+					AuthRequired:   []string{},
+					MaxRows:        100,
+					MaxResultBytes: 1048576,
+				},
+			},
+		},
+		{
+			desc: "with dry run",
+			in: `
+            tools:
+                exec_tool:
+                    kind: hana-execute-sql
+                    source: my-hana-instance
+                    description: execute any sql
+                    dryRun: true
+            `,
+			want: server.ToolConfigs{
+				"exec_tool": hanaexecutesql.Config{
+					Name:         "exec_tool",
+					Kind:         "hana-execute-sql",
+					Source:       "my-hana-instance",
+					Description:  "execute any sql",
+					AuthRequired: []string{},
+					DryRun:       true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -23,6 +23,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/hana"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hana/hanacommon"
 )
 
 const kind string = "hana-sql"
@@ -42,7 +43,7 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 }
 
 type compatibleSource interface {
-	HanaDB() *sql.DB
+	HanaDBContext(context.Context) (*sql.DB, error)
 }
 
 // Validate compatible sources compile-time.
@@ -59,12 +60,19 @@ type Config struct {
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	MaxRows            int              `yaml:"maxRows"`
+	MaxResultBytes     int              `yaml:"maxResultBytes"`
+	DryRun             bool             `yaml:"dryRun"`
 }
 
 var _ tools.ToolConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string { return kind }
 
+// SupportsRowFilters reports that the tool applies the row filters of data
+// policies to its statement.
+func (cfg Config) SupportsRowFilters() bool { return true }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	rawS, ok := srcs[cfg.Source]
 	if !ok {
@@ -80,6 +88,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	if err != nil {
 		return nil, err
 	}
+	limits := tools.ResolveResultLimits(tools.ResultLimits{MaxRows: cfg.MaxRows, MaxResultBytes: cfg.MaxResultBytes}, rawS)
+	if limits.Enabled() {
+		allParameters, err = limits.WithPageTokenParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
+	if cfg.DryRun {
+		allParameters, err = tools.WithDryRunParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
@@ -91,7 +114,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
-		DB:                 s.HanaDB(),
+		Limits:             limits,
+		DryRun:             cfg.DryRun,
+		Source:             s,
+		scanOptions:        hanacommon.ScanOptionsOf(rawS),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
 	}
@@ -102,23 +128,26 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
-
-	DB          *sql.DB
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
+	Limits             tools.ResultLimits `yaml:"limits"`
+	DryRun             bool               `yaml:"dryRun"`
+
+	Source      compatibleSource
 	Statement   string
+	scanOptions hanacommon.ScanOptions
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
-	db := t.DB
-	if db == nil {
-		return nil, fmt.Errorf("database connection is nil")
+	db, err := t.Source.HanaDBContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	paramsMap := params.AsMap()
@@ -131,48 +160,36 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params: %w", err)
 	}
-	sliceParams := newParams.AsSlice()
-
-	rows, err := db.QueryContext(ctx, stmt, sliceParams...)
+	stmt, sliceParams, err := hanacommon.FilterStatement(ctx, stmt, newParams.AsSlice())
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, err
+	}
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return hanacommon.DryRun(ctx, db, stmt, sliceParams)
 	}
-	defer rows.Close()
 
-	cols, err := rows.Columns()
+	pageToken, _ := paramsMap[tools.PageTokenParameterName].(string)
+	collector, err := tools.NewRowCollector(ctx, t.Limits, pageToken, tools.QueryFingerprint(stmt, sliceParams...))
 	if err != nil {
-		return nil, fmt.Errorf("unable to get columns: %w", err)
+		return nil, err
 	}
 
-	var results []map[string]any
-	for rows.Next() {
-		vals := make([]any, len(cols))
-		valPtrs := make([]any, len(cols))
-		for i := range vals {
-			valPtrs[i] = &vals[i]
-		}
-
-		if err := rows.Scan(valPtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-
-		rowMap := make(map[string]any)
-		for i, col := range cols {
-			switch v := vals[i].(type) {
-			case []byte:
-				rowMap[col] = string(v)
-			default:
-				rowMap[col] = v
-			}
-		}
-		results = append(results, rowMap)
+	conn, release, err := hanacommon.Conn(ctx, db)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+	rows, err := conn.QueryContext(ctx, stmt, sliceParams...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
+	defer rows.Close()
 
-	return results, nil
+	if err := hanacommon.ScanRows(ctx, rows, t.scanOptions, func(row map[string]any) bool { return collector.Add(row) }); err != nil {
+		return nil, err
+	}
+	return collector.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -66,6 +66,29 @@ func TestParseFromYamlHanaSQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with dry run",
+			in: `
+            tools:
+                example_tool:
+                    kind: hana-sql
+                    source: my-hana-instance
+                    description: some description
+                    statement: DELETE FROM ORDERS WHERE ID = ?;
+                    dryRun: true
+            `,
+			want: server.ToolConfigs{
+				"example_tool": hanasql.Config{
+					Name:         "example_tool",
+					Kind:         "hana-sql",
+					Source:       "my-hana-instance",
+					Description:  "some description",
+					Statement:    "DELETE FROM ORDERS WHERE ID = ?;",
+					AuthRequired: []string{},
+					DryRun:       true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
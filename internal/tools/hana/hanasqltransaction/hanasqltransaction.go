@@ -0,0 +1,223 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanasqltransaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/hana"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hana/hanacommon"
+)
+
+const kind string = "hana-sql-transaction"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	HanaDBContext(context.Context) (*sql.DB, error)
+}
+
+// Validate compatible sources compile-time.
+var _ compatibleSource = &hana.Source{}
+
+var compatibleSources = [...]string{hana.SourceKind}
+
+type Config struct {
+	Name           string                       `yaml:"name" validate:"required"`
+	Kind           string                       `yaml:"kind" validate:"required"`
+	Source         string                       `yaml:"source" validate:"required"`
+	Description    string                       `yaml:"description" validate:"required"`
+	Statements     []tools.TransactionStatement `yaml:"statements" validate:"required"`
+	IsolationLevel tools.IsolationLevel         `yaml:"isolationLevel"`
+	AuthRequired   []string                     `yaml:"authRequired"`
+	Parameters     tools.Parameters             `yaml:"parameters"`
+}
+
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string { return kind }
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.IsolationLevel == tools.IsolationReadUncommitted {
+		return nil, fmt.Errorf("isolation level %q is not supported by SAP HANA", cfg.IsolationLevel)
+	}
+	if err := tools.CheckTransactionStatements(cfg.Statements, cfg.Parameters); err != nil {
+		return nil, err
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.Parameters)
+
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Statements:   cfg.Statements,
+		AuthRequired: cfg.AuthRequired,
+		Source:       s,
+		txOptions:    &sql.TxOptions{Isolation: cfg.IsolationLevel.SQLIsolationLevel()},
+		scanOptions:  hanacommon.ScanOptionsOf(rawS),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// Tool implementation
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string                       `yaml:"name"`
+	Kind         string                       `yaml:"kind"`
+	AuthRequired []string                     `yaml:"authRequired"`
+	Parameters   tools.Parameters             `yaml:"parameters"`
+	Statements   []tools.TransactionStatement `yaml:"statements"`
+
+	Source      compatibleSource
+	txOptions   *sql.TxOptions
+	scanOptions hanacommon.ScanOptions
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Invoke executes the statements in a transaction, which is rolled back if
+// any statement fails.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	db, err := t.Source.HanaDBContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, release, err := hanacommon.Conn(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	paramsMap := params.AsMap()
+	tx, err := conn.BeginTx(ctx, t.txOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Rollback is a no-op once the transaction is committed.
+	defer func() { _ = tx.Rollback() }()
+
+	result := tools.TransactionResult{Statements: make([]tools.StatementResult, 0, len(t.Statements))}
+	for i, s := range t.Statements {
+		args, err := s.Args(t.Parameters, paramsMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to extract params of statement %d: %w", i, err)
+		}
+		if !isQuery(s.Statement) {
+			res, err := tx.ExecContext(ctx, s.Statement, args...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to execute statement %d: %w", i, err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("unable to get rows affected by statement %d: %w", i, err)
+			}
+			result.Statements = append(result.Statements, tools.StatementResult{RowsAffected: n})
+			continue
+		}
+		rows, err := queryRows(ctx, tx, s.Statement, args, t.scanOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute statement %d: %w", i, err)
+		}
+		result.Rows = rows
+		result.Statements = append(result.Statements, tools.StatementResult{RowsAffected: int64(len(rows))})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// isQuery reports whether the statement returns rows, judging by its first
+// keyword.
+func isQuery(statement string) bool {
+	fields := strings.Fields(strings.TrimLeft(statement, " \t\r\n("))
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH":
+		return true
+	}
+	return false
+}
+
+// queryRows returns the rows of a query in tx.
+func queryRows(ctx context.Context, tx *sql.Tx, statement string, args []any, opts hanacommon.ScanOptions) ([]any, error) {
+	rows, err := tx.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []any{}
+	err = hanacommon.ScanRows(ctx, rows, opts, func(row map[string]any) bool {
+		out = append(out, row)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest { return t.manifest }
+
+func (t Tool) McpManifest() tools.McpManifest { return t.mcpManifest }
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
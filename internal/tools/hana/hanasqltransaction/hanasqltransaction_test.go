@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanasqltransaction_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/hana"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hana/hanasqltransaction"
+)
+
+func TestParseFromYamlHanaSQLTransaction(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+            tools:
+                create_order:
+                    kind: hana-sql-transaction
+                    source: my-hana-instance
+                    description: creates an order with an item
+                    isolationLevel: serializable
+                    statements:
+                        - statement: INSERT INTO ORDERS (ID, CUSTOMER) VALUES (?, ?)
+                          parameters: [id, customer]
+                        - statement: INSERT INTO ITEMS (ORDER_ID, SKU) VALUES (?, ?)
+                          parameters: [id, sku]
+                        - statement: SELECT * FROM ITEMS WHERE ORDER_ID = ?
+                          parameters: [id]
+                    authRequired:
+                        - corp-auth-service
+                    parameters:
+                        - name: id
+                          type: integer
+                          description: order id
+                        - name: customer
+                          type: string
+                          description: customer name
+                        - name: sku
+                          type: string
+                          description: item sku
+            `
+	want := server.ToolConfigs{
+		"create_order": hanasqltransaction.Config{
+			Name:           "create_order",
+			Kind:           "hana-sql-transaction",
+			Source:         "my-hana-instance",
+			Description:    "creates an order with an item",
+			IsolationLevel: tools.IsolationSerializable,
+			Statements: []tools.TransactionStatement{
+				{Statement: "INSERT INTO ORDERS (ID, CUSTOMER) VALUES (?, ?)", Parameters: []string{"id", "customer"}},
+				{Statement: "INSERT INTO ITEMS (ORDER_ID, SKU) VALUES (?, ?)", Parameters: []string{"id", "sku"}},
+				{Statement: "SELECT * FROM ITEMS WHERE ORDER_ID = ?", Parameters: []string{"id"}},
+			},
+			AuthRequired: []string{"corp-auth-service"},
+			Parameters: []tools.Parameter{
+				tools.NewIntParameter("id", "order id"),
+				tools.NewStringParameter("customer", "customer name"),
+				tools.NewStringParameter("sku", "item sku"),
+			},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestInitializeHanaSQLTransaction(t *testing.T) {
+	srcs := map[string]sources.Source{"my-hana-instance": &hana.Source{}}
+	tcs := []struct {
+		desc string
+		cfg  hanasqltransaction.Config
+		err  string
+	}{
+		{
+			desc: "valid",
+			cfg: hanasqltransaction.Config{
+				Source:     "my-hana-instance",
+				Statements: []tools.TransactionStatement{{Statement: "DELETE FROM T WHERE ID = ?"}},
+				Parameters: tools.Parameters{tools.NewIntParameter("id", "")},
+			},
+		},
+		{
+			desc: "read uncommitted",
+			cfg: hanasqltransaction.Config{
+				Source:         "my-hana-instance",
+				IsolationLevel: tools.IsolationReadUncommitted,
+				Statements:     []tools.TransactionStatement{{Statement: "DELETE FROM T"}},
+			},
+			err: "not supported by SAP HANA",
+		},
+		{
+			desc: "unknown parameter",
+			cfg: hanasqltransaction.Config{
+				Source:     "my-hana-instance",
+				Statements: []tools.TransactionStatement{{Statement: "DELETE FROM T WHERE ID = ?", Parameters: []string{"id"}}},
+			},
+			err: `parameter "id" of statement 0 is not a parameter of the tool`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(srcs)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlHanaSQLTransaction(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+            tools:
+                create_order:
+                    kind: hana-sql-transaction
+                    source: my-hana-instance
+                    description: some description
+                    isolationLevel: snapshot
+                    statements:
+                        - statement: DELETE FROM T
+            `
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil || !strings.Contains(err.Error(), `"snapshot" is not a valid isolation level`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanavectorsearch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/hana"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hana/hanacommon"
+)
+
+const kind string = "hana-vector-search"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	HanaDBContext(context.Context) (*sql.DB, error)
+}
+
+// Validate compatible sources compile-time.
+var _ compatibleSource = &hana.Source{}
+
+var compatibleSources = [...]string{hana.SourceKind}
+
+type Config struct {
+	Name            string                 `yaml:"name" validate:"required"`
+	Kind            string                 `yaml:"kind" validate:"required"`
+	Source          string                 `yaml:"source" validate:"required"`
+	Description     string                 `yaml:"description" validate:"required"`
+	Table           string                 `yaml:"table" validate:"required"`
+	EmbeddingColumn string                 `yaml:"embeddingColumn" validate:"required"`
+	Columns         []string               `yaml:"columns" validate:"required"`
+	Distance        tools.VectorDistance   `yaml:"distance"`
+	TopK            int                    `yaml:"topK"`
+	MaxTopK         int                    `yaml:"maxTopK"`
+	Filters         tools.Parameters       `yaml:"filters"`
+	EmbeddingModel  embeddingmodels.Config `yaml:"embeddingModel" validate:"required"`
+	AuthRequired    []string               `yaml:"authRequired"`
+}
+
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string { return kind }
+
+// SupportsRowFilters reports that the tool applies the row filters of data
+// policies to its search.
+func (cfg Config) SupportsRowFilters() bool { return true }
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	search, err := tools.VectorSearch{
+		Table:           cfg.Table,
+		EmbeddingColumn: cfg.EmbeddingColumn,
+		Columns:         cfg.Columns,
+		Distance:        cfg.Distance,
+		TopK:            cfg.TopK,
+		MaxTopK:         cfg.MaxTopK,
+		Filters:         cfg.Filters,
+	}.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	model, err := cfg.EmbeddingModel.Initialize()
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize embedding model: %w", err)
+	}
+
+	parameters := search.Parameters()
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
+
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Source:       s,
+		search:       search,
+		dialect:      dialect(search.Distance),
+		model:        model,
+		scanOptions:  hanacommon.ScanOptionsOf(rawS),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// dialect returns the SQL of a search by distance. Cosine similarities rank
+// the closest rows first by descending score, and L2 distances by ascending
+// score.
+func dialect(distance tools.VectorDistance) tools.VectorDialect {
+	d := tools.VectorDialect{
+		Placeholder: func(int) string { return "?" },
+		Vector:      func(placeholder string) string { return "TO_REAL_VECTOR(" + placeholder + ")" },
+	}
+	score := tools.QuoteIdentifier(tools.VectorScoreColumn)
+	switch distance {
+	case tools.VectorDistanceL2:
+		d.Score = func(column, vector string) string { return fmt.Sprintf("L2DISTANCE(%s, %s)", column, vector) }
+		d.Order = func(string, string) string { return score + " ASC" }
+	default:
+		d.Score = func(column, vector string) string { return fmt.Sprintf("COSINE_SIMILARITY(%s, %s)", column, vector) }
+		d.Order = func(string, string) string { return score + " DESC" }
+	}
+	return d
+}
+
+// Tool implementation
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Source      compatibleSource
+	search      tools.VectorSearch
+	dialect     tools.VectorDialect
+	model       embeddingmodels.EmbeddingModel
+	scanOptions hanacommon.ScanOptions
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Invoke embeds the query and returns the closest rows, ranked by score.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	db, err := t.Source.HanaDBContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := t.search.Query(params)
+	if err != nil {
+		return nil, err
+	}
+	vector, err := t.model.Embed(ctx, q.Text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to embed query: %w", err)
+	}
+	statement, args := t.search.Statement(t.dialect, q, vector, tools.RowFiltersFromContext(ctx))
+
+	conn, release, err := hanacommon.Conn(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := conn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	out := []any{}
+	err = hanacommon.ScanRows(ctx, rows, t.scanOptions, func(row map[string]any) bool {
+		out = append(out, row)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest { return t.manifest }
+
+func (t Tool) McpManifest() tools.McpManifest { return t.mcpManifest }
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hanavectorsearch_test
+
+import (
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels"
+	"github.com/googleapis/genai-toolbox/internal/embeddingmodels/fake"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/hana"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hana/hanavectorsearch"
+)
+
+func TestParseFromYamlHanaVectorSearch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+            tools:
+                search_docs:
+                    kind: hana-vector-search
+                    source: my-hana-instance
+                    description: searches the documentation
+                    table: APP.DOCS
+                    embeddingColumn: EMBEDDING
+                    columns: [ID, TITLE, BODY]
+                    distance: l2
+                    topK: 3
+                    filters:
+                        - name: LANG
+                          type: string
+                          description: language of the documents
+                          required: false
+                    embeddingModel:
+                        kind: fake
+                        dimension: 8
+                    authRequired:
+                        - corp-auth-service
+            `
+	want := server.ToolConfigs{
+		"search_docs": hanavectorsearch.Config{
+			Name:            "search_docs",
+			Kind:            "hana-vector-search",
+			Source:          "my-hana-instance",
+			Description:     "searches the documentation",
+			Table:           "APP.DOCS",
+			EmbeddingColumn: "EMBEDDING",
+			Columns:         []string{"ID", "TITLE", "BODY"},
+			Distance:        tools.VectorDistanceL2,
+			TopK:            3,
+			Filters: tools.Parameters{
+				tools.NewStringParameterWithRequired("LANG", "language of the documents", false),
+			},
+			EmbeddingModel: embeddingmodels.Config{EmbeddingModelConfig: fake.Config{Kind: "fake", Dimension: 8}},
+			AuthRequired:   []string{"corp-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestInitializeHanaVectorSearch(t *testing.T) {
+	srcs := map[string]sources.Source{"my-hana-instance": &hana.Source{}}
+	valid := hanavectorsearch.Config{
+		Name:            "search_docs",
+		Source:          "my-hana-instance",
+		Table:           "DOCS",
+		EmbeddingColumn: "EMBEDDING",
+		Columns:         []string{"ID", "BODY"},
+		EmbeddingModel:  embeddingmodels.Config{EmbeddingModelConfig: fake.Config{Kind: "fake", Dimension: 8}},
+	}
+	tcs := []struct {
+		desc   string
+		modify func(*hanavectorsearch.Config)
+		err    string
+	}{
+		{
+			desc:   "valid",
+			modify: func(*hanavectorsearch.Config) {},
+		},
+		{
+			desc:   "topK above maxTopK",
+			modify: func(c *hanavectorsearch.Config) { c.TopK, c.MaxTopK = 20, 10 },
+			err:    "invalid topK 20",
+		},
+		{
+			desc:   "invalid embedding model",
+			modify: func(c *hanavectorsearch.Config) { c.EmbeddingModel.EmbeddingModelConfig = fake.Config{Kind: "fake"} },
+			err:    "unable to initialize embedding model",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := valid
+			tc.modify(&cfg)
+			tool, err := cfg.Initialize(srcs)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				var names []string
+				for _, p := range tool.Manifest().Parameters {
+					names = append(names, p.Name)
+				}
+				if diff := cmp.Diff([]string{"query", "topK"}, names); diff != "" {
+					t.Errorf("incorrect parameters: diff %v", diff)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlHanaVectorSearch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+            tools:
+                search_docs:
+                    kind: hana-vector-search
+                    source: my-hana-instance
+                    description: some description
+                    table: DOCS
+                    embeddingColumn: EMBEDDING
+                    columns: [ID]
+                    distance: dot
+                    embeddingModel:
+                        kind: fake
+                        dimension: 8
+            `
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got)
+	if err == nil || !strings.Contains(err.Error(), `"dot" is not a valid vector distance`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -17,22 +17,26 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 )
 
 // HTTPMethod is a string of a valid HTTP method (e.g "GET")
 type HTTPMethod string
 
-// isValidHTTPMethod checks if the input string matches one of the method constants defined in the net/http package
-func isValidHTTPMethod(method string) bool {
-
-	switch method {
-	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
+// Enum returns the values allowed for an HTTPMethod, which are the method
+// constants defined in the net/http package.
+func (HTTPMethod) Enum() []string {
+	return []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
 		http.MethodPatch, http.MethodHead, http.MethodOptions, http.MethodTrace,
-		http.MethodConnect:
-		return true
+		http.MethodConnect,
 	}
-	return false
+}
+
+// isValidHTTPMethod checks if the input string matches one of the method constants defined in the net/http package
+func isValidHTTPMethod(method string) bool {
+	return slices.Contains(HTTPMethod("").Enum(), method)
 }
 
 func (i *HTTPMethod) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
@@ -15,9 +15,13 @@
 package mysqlcommon
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"reflect"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 // ConvertToType handles casting mysql returns to the right type
@@ -41,3 +45,28 @@ func ConvertToType(t *sql.ColumnType, v any) (any, error) {
 		return v, nil
 	}
 }
+
+// DryRun returns the EXPLAIN output of statement, and the number of rows it
+// affects if it is a DML statement, in a transaction that is rolled back.
+// Changes to tables that do not support transactions, such as MyISAM tables,
+// cannot be rolled back.
+func DryRun(ctx context.Context, db *sql.DB, statement string, args []any) (tools.DryRunResult, error) {
+	return tools.SQLDryRun(ctx, db, statement, args, func(ctx context.Context, tx *sql.Tx) (any, error) {
+		rows, err := tx.QueryContext(ctx, "EXPLAIN "+statement, args...)
+		if err != nil {
+			return nil, err
+		}
+		return tools.ScanSQLRows(rows)
+	})
+}
+
+// FilterStatement applies the row filters of ctx to statement. See
+// tools.FilterStatement.
+func FilterStatement(ctx context.Context, statement string, args []any) (string, []any, error) {
+	return tools.FilterStatement(statement, args, tools.RowFiltersFromContext(ctx), func(int) string { return "?" }, quoteIdentifier)
+}
+
+// quoteIdentifier quotes name with backticks.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
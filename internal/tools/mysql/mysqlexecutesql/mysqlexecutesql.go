@@ -55,11 +55,14 @@ var _ compatibleSource = &mysql.Source{}
 var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name           string   `yaml:"name" validate:"required"`
+	Kind           string   `yaml:"kind" validate:"required"`
+	Source         string   `yaml:"source" validate:"required"`
+	Description    string   `yaml:"description" validate:"required"`
+	AuthRequired   []string `yaml:"authRequired"`
+	MaxRows        int      `yaml:"maxRows"`
+	MaxResultBytes int      `yaml:"maxResultBytes"`
+	DryRun         bool     `yaml:"dryRun"`
 }
 
 // validate interface
@@ -69,6 +72,10 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsColumnMasks reports that the result columns cannot be masked by
+// name, since the statements of the caller may alias them.
+func (cfg Config) SupportsColumnMasks() bool { return false }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -83,7 +90,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	limits := tools.ResolveResultLimits(tools.ResultLimits{MaxRows: cfg.MaxRows, MaxResultBytes: cfg.MaxResultBytes}, rawS)
+	parameters, err := limits.WithPageTokenParameter(tools.Parameters{sqlParameter})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DryRun {
+		parameters, err = tools.WithDryRunParameter(parameters)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -93,6 +110,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Kind:         kind,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		Limits:       limits,
+		DryRun:       cfg.DryRun,
 		Pool:         s.MySQLPool(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -104,10 +123,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
+	Limits       tools.ResultLimits `yaml:"limits"`
+	DryRun       bool               `yaml:"dryRun"`
 
 	Pool        *sql.DB
 	manifest    tools.Manifest
@@ -127,6 +148,15 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("error getting logger: %s", err)
 	}
 	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return mysqlcommon.DryRun(ctx, t.Pool, sql, nil)
+	}
+
+	pageToken, _ := paramsMap[tools.PageTokenParameterName].(string)
+	collector, err := tools.NewRowCollector(ctx, t.Limits, pageToken, tools.QueryFingerprint(sql))
+	if err != nil {
+		return nil, err
+	}
 
 	results, err := t.Pool.QueryContext(ctx, sql)
 	if err != nil {
@@ -151,7 +181,6 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
@@ -170,14 +199,16 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
-		out = append(out, vMap)
+		if !collector.Add(vMap) {
+			break
+		}
 	}
 
 	if err := results.Err(); err != nil {
 		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
 	}
 
-	return out, nil
+	return collector.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
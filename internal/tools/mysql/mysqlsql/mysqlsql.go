@@ -62,6 +62,9 @@ type Config struct {
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	MaxRows            int              `yaml:"maxRows"`
+	MaxResultBytes     int              `yaml:"maxResultBytes"`
+	DryRun             bool             `yaml:"dryRun"`
 }
 
 // validate interface
@@ -71,6 +74,12 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsRowFilters reports that the tool applies the row filters of data
+// policies to its statement.
+func (cfg Config) SupportsRowFilters() bool {
+	return true
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -88,6 +97,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	if err != nil {
 		return nil, err
 	}
+	limits := tools.ResolveResultLimits(tools.ResultLimits{MaxRows: cfg.MaxRows, MaxResultBytes: cfg.MaxResultBytes}, rawS)
+	if limits.Enabled() {
+		allParameters, err = limits.WithPageTokenParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
+	if cfg.DryRun {
+		allParameters, err = tools.WithDryRunParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
@@ -100,6 +124,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
+		Limits:             limits,
+		DryRun:             cfg.DryRun,
 		Pool:               s.MySQLPool(),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
@@ -111,12 +137,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
+	Limits             tools.ResultLimits `yaml:"limits"`
+	DryRun             bool               `yaml:"dryRun"`
 
 	Pool        *sql.DB
 	Statement   string
@@ -136,7 +164,20 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
 
-	sliceParams := newParams.AsSlice()
+	newStatement, sliceParams, err := mysqlcommon.FilterStatement(ctx, newStatement, newParams.AsSlice())
+	if err != nil {
+		return nil, err
+	}
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return mysqlcommon.DryRun(ctx, t.Pool, newStatement, sliceParams)
+	}
+
+	pageToken, _ := paramsMap[tools.PageTokenParameterName].(string)
+	collector, err := tools.NewRowCollector(ctx, t.Limits, pageToken, tools.QueryFingerprint(newStatement, sliceParams...))
+	if err != nil {
+		return nil, err
+	}
+
 	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
@@ -160,7 +201,6 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
@@ -179,14 +219,16 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 				return nil, fmt.Errorf("errors encountered when converting values: %w", err)
 			}
 		}
-		out = append(out, vMap)
+		if !collector.Add(vMap) {
+			break
+		}
 	}
 
 	if err := results.Err(); err != nil {
 		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
 	}
 
-	return out, nil
+	return collector.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -69,6 +69,10 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsColumnMasks reports that the result columns cannot be masked by
+// name, since the queries of the caller may alias them.
+func (cfg Config) SupportsColumnMasks() bool { return false }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -267,6 +267,19 @@ func (c *Parameters) UnmarshalYAML(ctx context.Context, unmarshal func(interface
 	return nil
 }
 
+// ParameterTypes returns an empty Parameter of each supported type, keyed by
+// the value of its `type` field.
+func ParameterTypes() map[string]Parameter {
+	return map[string]Parameter{
+		typeString: &StringParameter{},
+		typeInt:    &IntParameter{},
+		typeFloat:  &FloatParameter{},
+		typeBool:   &BooleanParameter{},
+		typeArray:  &ArrayParameter{},
+		typeMap:    &MapParameter{},
+	}
+}
+
 // parseParamFromDelayedUnmarshaler is a helper function that is required to parse
 // parameters because there are multiple different types
 func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmarshaler) (Parameter, error) {
@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package postgrescommon contains helpers shared by the Postgres tools.
+package postgrescommon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DryRun returns the plan of statement, and the number of rows it affects if
+// it is a DML statement, in a transaction that is rolled back.
+func DryRun(ctx context.Context, pool *pgxpool.Pool, statement string, args []any) (tools.DryRunResult, error) {
+	res := tools.DryRunResult{Statement: statement, Parameters: args}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return res, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+statement, args...).Scan(&res.Plan); err != nil {
+		return res, fmt.Errorf("unable to explain statement: %w", err)
+	}
+	if tools.IsDML(statement) {
+		tag, err := tx.Exec(ctx, statement, args...)
+		if err != nil {
+			return res, fmt.Errorf("unable to execute statement: %w", err)
+		}
+		n := tag.RowsAffected()
+		res.RowsAffected = &n
+	}
+	return res, nil
+}
+
+// FilterStatement applies the row filters of ctx to statement. See
+// tools.FilterStatement.
+func FilterStatement(ctx context.Context, statement string, args []any) (string, []any, error) {
+	return tools.FilterStatement(statement, args, tools.RowFiltersFromContext(ctx), func(n int) string { return fmt.Sprintf("$%d", n) }, tools.QuoteIdentifier)
+}
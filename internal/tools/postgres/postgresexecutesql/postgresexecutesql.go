@@ -24,6 +24,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -56,11 +57,14 @@ var _ compatibleSource = &postgres.Source{}
 var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name           string   `yaml:"name" validate:"required"`
+	Kind           string   `yaml:"kind" validate:"required"`
+	Source         string   `yaml:"source" validate:"required"`
+	Description    string   `yaml:"description" validate:"required"`
+	AuthRequired   []string `yaml:"authRequired"`
+	MaxRows        int      `yaml:"maxRows"`
+	MaxResultBytes int      `yaml:"maxResultBytes"`
+	DryRun         bool     `yaml:"dryRun"`
 }
 
 // validate interface
@@ -70,6 +74,10 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsColumnMasks reports that the result columns cannot be masked by
+// name, since the statements of the caller may alias them.
+func (cfg Config) SupportsColumnMasks() bool { return false }
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -84,7 +92,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	}
 
 	sqlParameter := tools.NewStringParameter("sql", "The sql to execute.")
-	parameters := tools.Parameters{sqlParameter}
+	limits := tools.ResolveResultLimits(tools.ResultLimits{MaxRows: cfg.MaxRows, MaxResultBytes: cfg.MaxResultBytes}, rawS)
+	parameters, err := limits.WithPageTokenParameter(tools.Parameters{sqlParameter})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DryRun {
+		parameters, err = tools.WithDryRunParameter(parameters)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, parameters)
 
@@ -94,6 +112,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Kind:         kind,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		Limits:       limits,
+		DryRun:       cfg.DryRun,
 		Pool:         s.PostgresPool(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
@@ -105,10 +125,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name         string           `yaml:"name"`
-	Kind         string           `yaml:"kind"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string             `yaml:"name"`
+	Kind         string             `yaml:"kind"`
+	AuthRequired []string           `yaml:"authRequired"`
+	Parameters   tools.Parameters   `yaml:"parameters"`
+	Limits       tools.ResultLimits `yaml:"limits"`
+	DryRun       bool               `yaml:"dryRun"`
 
 	Pool        *pgxpool.Pool
 	manifest    tools.Manifest
@@ -127,15 +149,24 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		return nil, fmt.Errorf("error getting logger: %s", err)
 	}
 	logger.DebugContext(ctx, "executing `%s` tool query: %s", kind, sql)
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return postgrescommon.DryRun(ctx, t.Pool, sql, nil)
+	}
+
+	pageToken, _ := paramsMap[tools.PageTokenParameterName].(string)
+	collector, err := tools.NewRowCollector(ctx, t.Limits, pageToken, tools.QueryFingerprint(sql))
+	if err != nil {
+		return nil, err
+	}
 
 	results, err := t.Pool.Query(ctx, sql)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer results.Close()
 
 	fields := results.FieldDescriptions()
 
-	var out []any
 	for results.Next() {
 		v, err := results.Values()
 		if err != nil {
@@ -145,10 +176,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		for i, f := range fields {
 			vMap[f.Name] = v[i]
 		}
-		out = append(out, vMap)
+		if !collector.Add(vMap) {
+			break
+		}
 	}
 
-	return out, nil
+	return collector.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -24,6 +24,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgrescommon"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -63,6 +64,9 @@ type Config struct {
 	AuthRequired       []string         `yaml:"authRequired"`
 	Parameters         tools.Parameters `yaml:"parameters"`
 	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	MaxRows            int              `yaml:"maxRows"`
+	MaxResultBytes     int              `yaml:"maxResultBytes"`
+	DryRun             bool             `yaml:"dryRun"`
 }
 
 // validate interface
@@ -72,6 +76,12 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsRowFilters reports that the tool applies the row filters of data
+// policies to its statement.
+func (cfg Config) SupportsRowFilters() bool {
+	return true
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -89,6 +99,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	if err != nil {
 		return nil, err
 	}
+	limits := tools.ResolveResultLimits(tools.ResultLimits{MaxRows: cfg.MaxRows, MaxResultBytes: cfg.MaxResultBytes}, rawS)
+	if limits.Enabled() {
+		allParameters, err = limits.WithPageTokenParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
+	if cfg.DryRun {
+		allParameters, err = tools.WithDryRunParameter(allParameters)
+		if err != nil {
+			return nil, err
+		}
+		paramManifest = allParameters.Manifest()
+	}
 
 	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, allParameters)
 
@@ -101,6 +126,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		AllParams:          allParameters,
 		Statement:          cfg.Statement,
 		AuthRequired:       cfg.AuthRequired,
+		Limits:             limits,
+		DryRun:             cfg.DryRun,
 		Pool:               s.PostgresPool(),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
@@ -112,12 +139,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 var _ tools.Tool = Tool{}
 
 type Tool struct {
-	Name               string           `yaml:"name"`
-	Kind               string           `yaml:"kind"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
-	AllParams          tools.Parameters `yaml:"allParams"`
+	Name               string             `yaml:"name"`
+	Kind               string             `yaml:"kind"`
+	AuthRequired       []string           `yaml:"authRequired"`
+	Parameters         tools.Parameters   `yaml:"parameters"`
+	TemplateParameters tools.Parameters   `yaml:"templateParameters"`
+	AllParams          tools.Parameters   `yaml:"allParams"`
+	Limits             tools.ResultLimits `yaml:"limits"`
+	DryRun             bool               `yaml:"dryRun"`
 
 	Pool        *pgxpool.Pool
 	Statement   string
@@ -136,15 +165,28 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
-	sliceParams := newParams.AsSlice()
+	newStatement, sliceParams, err := postgrescommon.FilterStatement(ctx, newStatement, newParams.AsSlice())
+	if err != nil {
+		return nil, err
+	}
+	if t.DryRun && tools.IsDryRun(paramsMap) {
+		return postgrescommon.DryRun(ctx, t.Pool, newStatement, sliceParams)
+	}
+
+	pageToken, _ := paramsMap[tools.PageTokenParameterName].(string)
+	collector, err := tools.NewRowCollector(ctx, t.Limits, pageToken, tools.QueryFingerprint(newStatement, sliceParams...))
+	if err != nil {
+		return nil, err
+	}
+
 	results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer results.Close()
 
 	fields := results.FieldDescriptions()
 
-	var out []any
 	for results.Next() {
 		v, err := results.Values()
 		if err != nil {
@@ -154,10 +196,12 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken
 		for i, f := range fields {
 			vMap[f.Name] = v[i]
 		}
-		out = append(out, vMap)
+		if !collector.Add(vMap) {
+			break
+		}
 	}
 
-	return out, nil
+	return collector.Result(), nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
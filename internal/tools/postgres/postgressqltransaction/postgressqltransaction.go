@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgressqltransaction
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "postgres-sql-transaction"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &alloydbpg.Source{}
+var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &postgres.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+
+// isoLevels maps isolation levels to their pgx equivalent.
+var isoLevels = map[tools.IsolationLevel]pgx.TxIsoLevel{
+	tools.IsolationReadUncommitted: pgx.ReadUncommitted,
+	tools.IsolationReadCommitted:   pgx.ReadCommitted,
+	tools.IsolationRepeatableRead:  pgx.RepeatableRead,
+	tools.IsolationSerializable:    pgx.Serializable,
+}
+
+type Config struct {
+	Name           string                       `yaml:"name" validate:"required"`
+	Kind           string                       `yaml:"kind" validate:"required"`
+	Source         string                       `yaml:"source" validate:"required"`
+	Description    string                       `yaml:"description" validate:"required"`
+	Statements     []tools.TransactionStatement `yaml:"statements" validate:"required"`
+	IsolationLevel tools.IsolationLevel         `yaml:"isolationLevel"`
+	AuthRequired   []string                     `yaml:"authRequired"`
+	Parameters     tools.Parameters             `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if err := tools.CheckTransactionStatements(cfg.Statements, cfg.Parameters); err != nil {
+		return nil, err
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.Parameters)
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Statements:   cfg.Statements,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.PostgresPool(),
+		txOptions:    pgx.TxOptions{IsoLevel: isoLevels[cfg.IsolationLevel]},
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string                       `yaml:"name"`
+	Kind         string                       `yaml:"kind"`
+	AuthRequired []string                     `yaml:"authRequired"`
+	Parameters   tools.Parameters             `yaml:"parameters"`
+	Statements   []tools.TransactionStatement `yaml:"statements"`
+
+	Pool        *pgxpool.Pool
+	txOptions   pgx.TxOptions
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Invoke executes the statements in a transaction, which is rolled back if
+// any statement fails.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap := params.AsMap()
+	tx, err := t.Pool.BeginTx(ctx, t.txOptions)
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	// Rollback is a no-op once the transaction is committed.
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	result := tools.TransactionResult{Statements: make([]tools.StatementResult, 0, len(t.Statements))}
+	for i, s := range t.Statements {
+		args, err := s.Args(t.Parameters, paramsMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to extract params of statement %d: %w", i, err)
+		}
+		rows, err := tx.Query(ctx, s.Statement, args...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute statement %d: %w", i, err)
+		}
+		fields := rows.FieldDescriptions()
+		out := []any{}
+		for rows.Next() {
+			v, err := rows.Values()
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("unable to parse row of statement %d: %w", i, err)
+			}
+			vMap := make(map[string]any)
+			for j, f := range fields {
+				vMap[f.Name] = v[j]
+			}
+			out = append(out, vMap)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("unable to execute statement %d: %w", i, err)
+		}
+		if len(fields) > 0 {
+			result.Rows = out
+		}
+		result.Statements = append(result.Statements, tools.StatementResult{RowsAffected: rows.CommandTag().RowsAffected()})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	return false
+}
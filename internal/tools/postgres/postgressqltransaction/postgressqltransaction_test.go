@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgressqltransaction_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressqltransaction"
+)
+
+func TestParseFromYamlPostgresSQLTransaction(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				create_order:
+					kind: postgres-sql-transaction
+					source: my-pg-instance
+					description: creates an order with an item
+					isolationLevel: repeatableRead
+					statements:
+						- statement: INSERT INTO orders (id, customer) VALUES ($1, $2)
+						  parameters: [id, customer]
+						- statement: INSERT INTO items (order_id, sku) VALUES ($1, $2) RETURNING *
+						  parameters: [id, sku]
+					authRequired:
+						- my-google-auth-service
+					parameters:
+						- name: id
+						  type: integer
+						  description: order id
+						- name: customer
+						  type: string
+						  description: customer name
+						- name: sku
+						  type: string
+						  description: item sku
+			`,
+			want: server.ToolConfigs{
+				"create_order": postgressqltransaction.Config{
+					Name:           "create_order",
+					Kind:           "postgres-sql-transaction",
+					Source:         "my-pg-instance",
+					Description:    "creates an order with an item",
+					IsolationLevel: tools.IsolationRepeatableRead,
+					Statements: []tools.TransactionStatement{
+						{Statement: "INSERT INTO orders (id, customer) VALUES ($1, $2)", Parameters: []string{"id", "customer"}},
+						{Statement: "INSERT INTO items (order_id, sku) VALUES ($1, $2) RETURNING *", Parameters: []string{"id", "sku"}},
+					},
+					AuthRequired: []string{"my-google-auth-service"},
+					Parameters: []tools.Parameter{
+						tools.NewIntParameter("id", "order id"),
+						tools.NewStringParameter("customer", "customer name"),
+						tools.NewStringParameter("sku", "item sku"),
+					},
+				},
+			},
+		},
+		{
+			desc: "default isolation level and parameters",
+			in: `
+			tools:
+				delete_order:
+					kind: postgres-sql-transaction
+					source: my-pg-instance
+					description: deletes an order
+					statements:
+						- statement: DELETE FROM items WHERE order_id = $1
+						- statement: DELETE FROM orders WHERE id = $1
+					parameters:
+						- name: id
+						  type: integer
+						  description: order id
+			`,
+			want: server.ToolConfigs{
+				"delete_order": postgressqltransaction.Config{
+					Name:        "delete_order",
+					Kind:        "postgres-sql-transaction",
+					Source:      "my-pg-instance",
+					Description: "deletes an order",
+					Statements: []tools.TransactionStatement{
+						{Statement: "DELETE FROM items WHERE order_id = $1"},
+						{Statement: "DELETE FROM orders WHERE id = $1"},
+					},
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewIntParameter("id", "order id"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got); err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
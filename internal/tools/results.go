@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// PageTokenParameterName is the name of the optional argument used to request
+// the next page of a result that was cut off by ResultLimits.
+const PageTokenParameterName = "pageToken"
+
+// ResultLimits caps the number of rows and the encoded size of a tool result.
+// A zero value means no limit.
+type ResultLimits struct {
+	MaxRows        int `yaml:"maxRows"`
+	MaxResultBytes int `yaml:"maxResultBytes"`
+}
+
+// Enabled returns true if any limit is set.
+func (l ResultLimits) Enabled() bool {
+	return l.MaxRows > 0 || l.MaxResultBytes > 0
+}
+
+// limitedSource is implemented by sources that define default result limits
+// for the tools that use them.
+type limitedSource interface {
+	ResultLimits() (maxRows int, maxResultBytes int)
+}
+
+// ResolveResultLimits returns the tool limits, falling back to the limits of
+// the source for any limit the tool does not set.
+func ResolveResultLimits(toolLimits ResultLimits, s sources.Source) ResultLimits {
+	ls, ok := s.(limitedSource)
+	if !ok {
+		return toolLimits
+	}
+	maxRows, maxResultBytes := ls.ResultLimits()
+	if toolLimits.MaxRows == 0 {
+		toolLimits.MaxRows = maxRows
+	}
+	if toolLimits.MaxResultBytes == 0 {
+		toolLimits.MaxResultBytes = maxResultBytes
+	}
+	return toolLimits
+}
+
+// WithPageTokenParameter appends the optional pageToken parameter to ps if
+// results may be paginated.
+func (l ResultLimits) WithPageTokenParameter(ps Parameters) (Parameters, error) {
+	if !l.Enabled() {
+		return ps, nil
+	}
+	for _, p := range ps {
+		if p.GetName() == PageTokenParameterName {
+			return nil, fmt.Errorf("parameter name %q is reserved when maxRows or maxResultBytes is set", PageTokenParameterName)
+		}
+	}
+	pageToken := NewStringParameterWithDefault(PageTokenParameterName, "", "Token returned as nextPageToken by a previous call, used to retrieve the next page of results.")
+	return append(slices.Clone(ps), pageToken), nil
+}
+
+// PagedResult is returned instead of the plain rows when a result was cut off
+// by ResultLimits or when a page other than the first was requested.
+type PagedResult struct {
+	Rows []any `json:"rows"`
+	// NextPageToken is passed as the pageToken argument to retrieve the next
+	// page. It is empty on the last page.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// pageToken is the decoded form of the opaque page token.
+type pageToken struct {
+	// Offset is the number of rows returned by previous pages.
+	Offset int `json:"o"`
+	// Fingerprint identifies the query the token was issued for.
+	Fingerprint string `json:"f"`
+}
+
+// QueryFingerprint returns a short identifier of a statement and its
+// arguments, used to reject page tokens issued for a different query.
+func QueryFingerprint(statement string, args ...any) string {
+	b, _ := json.Marshal(args)
+	sum := sha256.Sum256(append([]byte(statement+"\x00"), b...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RowCollector accumulates result rows while enforcing ResultLimits. Pages are
+// addressed by row offset, so the query must return rows in a stable order
+// (e.g. using ORDER BY) for pages to be consistent.
+//
+// If the context has a RowSink, rows are streamed to it without limits
+// instead.
+type RowCollector struct {
+	ctx         context.Context
+	sink        RowSink
+	streamed    int
+	limits      ResultLimits
+	fingerprint string
+	offset      int
+	skipped     int
+	paged       bool
+	rows        []any
+	size        int
+	truncated   bool
+}
+
+// NewRowCollector returns a RowCollector that starts at the page identified
+// by token, or at the first row if token is empty.
+func NewRowCollector(ctx context.Context, limits ResultLimits, token string, fingerprint string) (*RowCollector, error) {
+	c := &RowCollector{ctx: ctx, sink: RowSinkFromContext(ctx), limits: limits, fingerprint: fingerprint}
+	if token == "" {
+		return c, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token")
+	}
+	var pt pageToken
+	if err := json.Unmarshal(b, &pt); err != nil || pt.Offset < 0 {
+		return nil, fmt.Errorf("invalid page token")
+	}
+	if pt.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("page token was issued for a different query")
+	}
+	c.offset = pt.Offset
+	c.paged = true
+	return c, nil
+}
+
+// Add adds a row to the current page. It returns false once the page is full,
+// after which the caller should stop reading rows.
+func (c *RowCollector) Add(row any) bool {
+	if c.skipped < c.offset {
+		c.skipped++
+		return true
+	}
+	if c.sink != nil {
+		if m, ok := row.(map[string]any); ok {
+			row = MapRow(c.ctx, m)
+		}
+		if err := c.sink.WriteRow(row); err != nil {
+			// the sink keeps its error, the rows are not needed anymore
+			return false
+		}
+		c.streamed++
+		return true
+	}
+	if c.limits.MaxRows > 0 && len(c.rows) >= c.limits.MaxRows {
+		c.truncated = true
+		return false
+	}
+	if c.limits.MaxResultBytes > 0 {
+		b, err := json.Marshal(row)
+		size := len(b) + 1
+		if err == nil && len(c.rows) > 0 && c.size+size > c.limits.MaxResultBytes {
+			c.truncated = true
+			return false
+		}
+		c.size += size
+	}
+	c.rows = append(c.rows, row)
+	return true
+}
+
+// Result returns the collected rows. Unless the result was cut off or a page
+// token was used, the rows are returned as is. Streamed rows are returned as
+// a StreamedResult.
+func (c *RowCollector) Result() any {
+	if c.sink != nil {
+		return StreamedResult{Rows: c.streamed}
+	}
+	if !c.truncated && !c.paged {
+		return c.rows
+	}
+	res := PagedResult{Rows: c.rows}
+	if res.Rows == nil {
+		res.Rows = []any{}
+	}
+	if c.truncated {
+		b, _ := json.Marshal(pageToken{Offset: c.offset + len(c.rows), Fingerprint: c.fingerprint})
+		res.NextPageToken = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return res
+}
+
+// ResultRows returns the number of rows of a tool result. Slice results have
+// a row per element, and any other non-nil result is a single row. The size
+// of a result is measured where it is encoded in the response, so that it is
+// not encoded twice.
+func ResultRows(res any) int {
+	if p, ok := res.(PagedResult); ok {
+		res = p.Rows
+	}
+	if res == nil {
+		return 0
+	}
+	if v := reflect.ValueOf(res); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return v.Len()
+	}
+	return 1
+}
+
+// maxDecimalDigits is the number of fractional digits of rationals that are
+// not decimals, which is the precision of a decimal128.
+const maxDecimalDigits = 34
+
+// DecimalString returns the exact decimal representation of r, such as a
+// DECIMAL value of SAP HANA.
+func DecimalString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	// the denominator of a decimal only has the prime factors 2 and 5, and
+	// the number of fractional digits is the largest of their exponents
+	d := new(big.Int).Set(r.Denom())
+	twos := 0
+	for d.Bit(0) == 0 {
+		d.Rsh(d, 1)
+		twos++
+	}
+	five, q, m := big.NewInt(5), new(big.Int), new(big.Int)
+	fives := 0
+	for {
+		q.QuoRem(d, five, m)
+		if m.Sign() != 0 {
+			break
+		}
+		d.Set(q)
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return r.FloatString(maxDecimalDigits)
+	}
+	return r.FloatString(max(twos, fives))
+}
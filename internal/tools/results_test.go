@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// collect returns the page of rows starting at token.
+func collect(t *testing.T, limits tools.ResultLimits, token string, rows []any) any {
+	t.Helper()
+	c, err := tools.NewRowCollector(context.Background(), limits, token, tools.QueryFingerprint("SELECT 1", 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, r := range rows {
+		if !c.Add(r) {
+			break
+		}
+	}
+	return c.Result()
+}
+
+func TestRowCollector(t *testing.T) {
+	rows := []any{
+		map[string]any{"id": 1},
+		map[string]any{"id": 2},
+		map[string]any{"id": 3},
+		map[string]any{"id": 4},
+		map[string]any{"id": 5},
+	}
+	tcs := []struct {
+		name   string
+		limits tools.ResultLimits
+		want   [][]any
+	}{
+		{
+			name: "no limits",
+			want: [][]any{rows},
+		},
+		{
+			name:   "max rows",
+			limits: tools.ResultLimits{MaxRows: 2},
+			want:   [][]any{rows[0:2], rows[2:4], rows[4:5]},
+		},
+		{
+			name:   "max rows equal to total",
+			limits: tools.ResultLimits{MaxRows: 5},
+			want:   [][]any{rows},
+		},
+		{
+			name: "max result bytes",
+			// each row is encoded as `{"id":N}` plus a separator
+			limits: tools.ResultLimits{MaxResultBytes: 30},
+			want:   [][]any{rows[0:3], rows[3:5]},
+		},
+		{
+			name:   "row larger than max result bytes",
+			limits: tools.ResultLimits{MaxResultBytes: 1},
+			want:   [][]any{rows[0:1], rows[1:2], rows[2:3], rows[3:4], rows[4:5]},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var got [][]any
+			token := ""
+			for i := 0; i < 10; i++ {
+				switch res := collect(t, tc.limits, token, rows).(type) {
+				case []any:
+					if i != 0 {
+						t.Fatalf("expected paged result on page %d", i)
+					}
+					got = append(got, res)
+				case tools.PagedResult:
+					got = append(got, res.Rows)
+					token = res.NextPageToken
+				default:
+					t.Fatalf("unexpected result type %T", res)
+				}
+				if token == "" {
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect pages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// sliceSink is a RowSink appending rows to a slice, failing after max rows.
+type sliceSink struct {
+	rows []any
+	max  int
+}
+
+func (s *sliceSink) WriteRow(row any) error {
+	if len(s.rows) >= s.max {
+		return errors.New("sink is full")
+	}
+	s.rows = append(s.rows, row)
+	return nil
+}
+
+func TestRowCollectorSink(t *testing.T) {
+	sink := &sliceSink{max: 3}
+	ctx := tools.WithRowSink(context.Background(), sink)
+	ctx = tools.WithRowMapper(ctx, func(row map[string]any) map[string]any {
+		return map[string]any{"id": row["id"], "masked": true}
+	})
+	// limits do not apply to streamed rows
+	c, err := tools.NewRowCollector(ctx, tools.ResultLimits{MaxRows: 1}, "", tools.QueryFingerprint("SELECT 1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i := range 2 {
+		if !c.Add(map[string]any{"id": i}) {
+			t.Fatalf("unexpected end of rows after %d rows", i)
+		}
+	}
+	if diff := cmp.Diff(tools.StreamedResult{Rows: 2}, c.Result()); diff != "" {
+		t.Fatalf("incorrect result (-want +got):\n%s", diff)
+	}
+	want := []any{map[string]any{"id": 0, "masked": true}, map[string]any{"id": 1, "masked": true}}
+	if diff := cmp.Diff(want, sink.rows); diff != "" {
+		t.Fatalf("incorrect streamed rows (-want +got):\n%s", diff)
+	}
+
+	// a failing sink stops the rows
+	c.Add(map[string]any{"id": 2})
+	if c.Add(map[string]any{"id": 3}) {
+		t.Fatalf("expected the collector to stop after the sink failed")
+	}
+}
+
+func TestFailRowCollector(t *testing.T) {
+	limits := tools.ResultLimits{MaxRows: 1}
+	c, err := tools.NewRowCollector(context.Background(), limits, "", tools.QueryFingerprint("SELECT 1", 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	c.Add(1)
+	c.Add(2)
+	res, ok := c.Result().(tools.PagedResult)
+	if !ok || res.NextPageToken == "" {
+		t.Fatalf("expected a next page token, got %v", c.Result())
+	}
+
+	tcs := []struct {
+		name        string
+		token       string
+		fingerprint string
+		err         string
+	}{
+		{
+			name:        "malformed token",
+			token:       "not a token",
+			fingerprint: tools.QueryFingerprint("SELECT 1", 1),
+			err:         "invalid page token",
+		},
+		{
+			name:        "different statement",
+			token:       res.NextPageToken,
+			fingerprint: tools.QueryFingerprint("SELECT 2", 1),
+			err:         "different query",
+		},
+		{
+			name:        "different arguments",
+			token:       res.NextPageToken,
+			fingerprint: tools.QueryFingerprint("SELECT 1", 2),
+			err:         "different query",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.NewRowCollector(context.Background(), limits, tc.token, tc.fingerprint)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestWithPageTokenParameter(t *testing.T) {
+	params := tools.Parameters{tools.NewStringParameter("sql", "The sql to execute.")}
+
+	got, err := tools.ResultLimits{}.WithPageTokenParameter(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("expected no parameter to be added without limits, got %d parameters", len(got))
+	}
+
+	limits := tools.ResultLimits{MaxRows: 10}
+	got, err = limits.WithPageTokenParameter(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 2 || got[1].GetName() != tools.PageTokenParameterName || tools.CheckParamRequired(got[1].GetRequired(), got[1].GetDefault()) {
+		t.Errorf("expected optional %q parameter to be added, got %v", tools.PageTokenParameterName, got)
+	}
+	if len(params) != 1 {
+		t.Errorf("expected input parameters to be unchanged")
+	}
+
+	_, err = limits.WithPageTokenParameter(got)
+	if err == nil {
+		t.Errorf("expected error for reserved parameter name")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RowFilter restricts the rows returned by a SQL tool to those whose Column
+// equals Value, or one of its elements if Value is a list.
+type RowFilter struct {
+	Column string
+	Value  any
+}
+
+type rowFiltersKey struct{}
+
+// WithRowFilters returns a context requiring SQL tools to apply filters in
+// addition to the row filters of ctx.
+func WithRowFilters(ctx context.Context, filters []RowFilter) context.Context {
+	all := append(slices.Clone(RowFiltersFromContext(ctx)), filters...)
+	return context.WithValue(ctx, rowFiltersKey{}, all)
+}
+
+// RowFiltersFromContext returns the row filters SQL tools must apply.
+func RowFiltersFromContext(ctx context.Context) []RowFilter {
+	filters, _ := ctx.Value(rowFiltersKey{}).([]RowFilter)
+	return filters
+}
+
+// RowFilterConfig is implemented by the configs of tools applying the row
+// filters of the context to their statement.
+type RowFilterConfig interface {
+	SupportsRowFilters() bool
+}
+
+// ColumnMaskConfig is implemented by the configs of tools whose result
+// columns cannot be masked by name, such as tools running the statements of
+// the caller. The columns of other tools are masked.
+type ColumnMaskConfig interface {
+	SupportsColumnMasks() bool
+}
+
+// FilterStatement wraps the query statement in a query returning only the
+// rows matching filters, and returns it with the arguments of its
+// placeholders. placeholder returns the placeholder of the nth argument,
+// starting at 1, and quote quotes a column name.
+func FilterStatement(statement string, args []any, filters []RowFilter, placeholder func(n int) string, quote func(name string) string) (string, []any, error) {
+	if len(filters) == 0 {
+		return statement, args, nil
+	}
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	fields := strings.Fields(strings.TrimLeft(statement, "("))
+	if len(fields) == 0 || (!strings.EqualFold(fields[0], "SELECT") && !strings.EqualFold(fields[0], "WITH")) {
+		return "", nil, fmt.Errorf("row filters can only be applied to SELECT statements")
+	}
+
+	predicates, args := FilterPredicates(filters, args, placeholder, quote)
+	return fmt.Sprintf("SELECT * FROM (%s) toolbox_filtered WHERE %s", statement, predicates), args, nil
+}
+
+// FilterPredicates returns the predicates of filters joined with AND, and
+// args followed by the arguments of their placeholders.
+func FilterPredicates(filters []RowFilter, args []any, placeholder func(n int) string, quote func(name string) string) (string, []any) {
+	if len(filters) == 0 {
+		return "", args
+	}
+	args = slices.Clone(args)
+	predicates := make([]string, 0, len(filters))
+	for _, f := range filters {
+		values, isList := f.Value.([]any)
+		if !isList {
+			args = append(args, f.Value)
+			predicates = append(predicates, fmt.Sprintf("%s = %s", quote(f.Column), placeholder(len(args))))
+			continue
+		}
+		if len(values) == 0 {
+			predicates = append(predicates, "1 = 0")
+			continue
+		}
+		phs := make([]string, 0, len(values))
+		for _, v := range values {
+			args = append(args, v)
+			phs = append(phs, placeholder(len(args)))
+		}
+		predicates = append(predicates, fmt.Sprintf("%s IN (%s)", quote(f.Column), strings.Join(phs, ", ")))
+	}
+	return strings.Join(predicates, " AND "), args
+}
+
+// QuoteIdentifier quotes name with double quotes, as in standard SQL.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteQualifiedIdentifier quotes each part of a name qualified with dots,
+// such as a table name qualified by its schema.
+func QuoteQualifiedIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = QuoteIdentifier(p)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestFilterStatement(t *testing.T) {
+	dollar := func(n int) string { return fmt.Sprintf("$%d", n) }
+	tcs := []struct {
+		desc      string
+		statement string
+		args      []any
+		filters   []tools.RowFilter
+		want      string
+		wantArgs  []any
+	}{
+		{
+			desc:      "no filters",
+			statement: "SELECT * FROM orders WHERE id = $1;",
+			args:      []any{1},
+			want:      "SELECT * FROM orders WHERE id = $1;",
+			wantArgs:  []any{1},
+		},
+		{
+			desc:      "single value",
+			statement: "SELECT * FROM orders WHERE id = $1;",
+			args:      []any{1},
+			filters:   []tools.RowFilter{{Column: "tenant", Value: "acme"}},
+			want:      `SELECT * FROM (SELECT * FROM orders WHERE id = $1) toolbox_filtered WHERE "tenant" = $2`,
+			wantArgs:  []any{1, "acme"},
+		},
+		{
+			desc:      "list of values",
+			statement: "WITH o AS (SELECT * FROM orders) SELECT * FROM o",
+			filters: []tools.RowFilter{
+				{Column: "region", Value: []any{"eu", "us"}},
+				{Column: "tenant", Value: "acme"},
+			},
+			want:     `SELECT * FROM (WITH o AS (SELECT * FROM orders) SELECT * FROM o) toolbox_filtered WHERE "region" IN ($1, $2) AND "tenant" = $3`,
+			wantArgs: []any{"eu", "us", "acme"},
+		},
+		{
+			desc:      "empty list of values",
+			statement: "SELECT * FROM orders",
+			filters:   []tools.RowFilter{{Column: "region", Value: []any{}}},
+			want:      `SELECT * FROM (SELECT * FROM orders) toolbox_filtered WHERE 1 = 0`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, gotArgs, err := tools.FilterStatement(tc.statement, tc.args, tc.filters, dollar, tools.QuoteIdentifier)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("incorrect statement: got %q, want %q", got, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantArgs, gotArgs); diff != "" {
+				t.Errorf("incorrect args: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailFilterStatement(t *testing.T) {
+	filters := []tools.RowFilter{{Column: "tenant", Value: "acme"}}
+	_, _, err := tools.FilterStatement("DELETE FROM orders", nil, filters, func(int) string { return "?" }, tools.QuoteIdentifier)
+	if err == nil {
+		t.Fatalf("expected error filtering a DELETE statement")
+	}
+}
+
+func TestWithRowFilters(t *testing.T) {
+	ctx := tools.WithRowFilters(context.Background(), []tools.RowFilter{{Column: "a", Value: 1}})
+	ctx = tools.WithRowFilters(ctx, []tools.RowFilter{{Column: "b", Value: 2}})
+	want := []tools.RowFilter{{Column: "a", Value: 1}, {Column: "b", Value: 2}}
+	if diff := cmp.Diff(want, tools.RowFiltersFromContext(ctx)); diff != "" {
+		t.Errorf("incorrect row filters: diff %v", diff)
+	}
+}
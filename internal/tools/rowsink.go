@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// RowSink receives the rows of a result streamed by a tool, such as to export
+// them to a file, instead of the rows being returned by the tool.
+type RowSink interface {
+	WriteRow(row any) error
+}
+
+// StreamedResult is returned by a tool that streamed its rows to the RowSink
+// of the context.
+type StreamedResult struct {
+	Rows int `json:"rows"`
+}
+
+type rowSinkKey struct{}
+
+// WithRowSink returns a context asking tools to stream their rows to sink.
+func WithRowSink(ctx context.Context, sink RowSink) context.Context {
+	return context.WithValue(ctx, rowSinkKey{}, sink)
+}
+
+// RowSinkFromContext returns the sink of ctx, or nil if rows are returned.
+func RowSinkFromContext(ctx context.Context) RowSink {
+	s, _ := ctx.Value(rowSinkKey{}).(RowSink)
+	return s
+}
+
+type rowMappersKey struct{}
+
+// WithRowMapper returns a context mapping the rows streamed to a RowSink
+// with m, after the mappers of ctx. Wrappers transforming the rows returned
+// by a tool, such as to mask columns, also transform streamed rows this way.
+func WithRowMapper(ctx context.Context, m func(map[string]any) map[string]any) context.Context {
+	mappers, _ := ctx.Value(rowMappersKey{}).([]func(map[string]any) map[string]any)
+	return context.WithValue(ctx, rowMappersKey{}, append(slices.Clone(mappers), m))
+}
+
+// MapRow applies the row mappers of ctx to row.
+func MapRow(ctx context.Context, row map[string]any) map[string]any {
+	mappers, _ := ctx.Value(rowMappersKey{}).([]func(map[string]any) map[string]any)
+	for _, m := range mappers {
+		row = m(row)
+	}
+	return row
+}
+
+// ColumnOrder records the order of the columns of the rows returned by a
+// tool. Rows are maps, which do not keep the order of the columns of the
+// statement.
+type ColumnOrder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+type columnOrderKey struct{}
+
+// WithColumnOrder returns a context recording the order of the columns of the
+// rows returned by tools invoked with it.
+func WithColumnOrder(ctx context.Context) (context.Context, *ColumnOrder) {
+	o := &ColumnOrder{}
+	return context.WithValue(ctx, columnOrderKey{}, o), o
+}
+
+// ColumnOrderFromContext returns the ColumnOrder of ctx, or nil if the order
+// of the columns is not recorded.
+func ColumnOrderFromContext(ctx context.Context) *ColumnOrder {
+	o, _ := ctx.Value(columnOrderKey{}).(*ColumnOrder)
+	return o
+}
+
+// SetColumns records the names of the columns of the rows returned with ctx,
+// in the order of the statement.
+func SetColumns(ctx context.Context, names []string) {
+	o := ColumnOrderFromContext(ctx)
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.names = slices.Clone(names)
+}
+
+// Columns returns the recorded names of the columns, or nil if none were
+// recorded.
+func (o *ColumnOrder) Columns() []string {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.names
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -46,6 +47,11 @@ func Register(kind string, factory ToolConfigFactory) bool {
 	return true
 }
 
+// Kinds returns the registered tool kinds in sorted order.
+func Kinds() []string {
+	return slices.Sorted(maps.Keys(toolRegistry))
+}
+
 // DecodeConfig looks up the registered factory for the given kind and uses it
 // to decode the tool configuration.
 func DecodeConfig(ctx context.Context, kind string, name string, decoder *yaml.Decoder) (ToolConfig, error) {
@@ -65,6 +71,51 @@ type ToolConfig interface {
 	Initialize(map[string]sources.Source) (Tool, error)
 }
 
+// ComposedToolConfig is implemented by tool configs that invoke other tools.
+// They are initialized after the tools named by ToolNames, with the
+// initialized tools by name, instead of by Initialize.
+type ComposedToolConfig interface {
+	ToolConfig
+	ToolNames() []string
+	InitializeWithTools(map[string]sources.Source, map[string]Tool) (Tool, error)
+}
+
+// TimeoutConfig is implemented by the configs of tools decoding a `timeout`
+// field of their own, which is otherwise decoded for every kind of tool.
+type TimeoutConfig interface {
+	OwnsTimeout() bool
+}
+
+// OwnsTimeout reports whether tools of the given kind decode their own
+// `timeout` field.
+func OwnsTimeout(ctx context.Context, kind string) (bool, error) {
+	cfg, err := DecodeConfig(ctx, kind, "", yaml.NewDecoder(strings.NewReader("{}")))
+	if err != nil {
+		return false, err
+	}
+	c, ok := cfg.(TimeoutConfig)
+	return ok && c.OwnsTimeout(), nil
+}
+
+// WrappedToolNames returns the tools invoked by the tool of inner, the config
+// wrapped by another config, for the ToolNames of the wrapper.
+func WrappedToolNames(inner ToolConfig) []string {
+	if c, ok := inner.(ComposedToolConfig); ok {
+		return c.ToolNames()
+	}
+	return nil
+}
+
+// InitializeWrapped initializes the tool of inner, the config wrapped by
+// another config, with the initialized tools by name if it invokes other
+// tools.
+func InitializeWrapped(inner ToolConfig, srcs map[string]sources.Source, toolsMap map[string]Tool) (Tool, error) {
+	if c, ok := inner.(ComposedToolConfig); ok {
+		return c.InitializeWithTools(srcs, toolsMap)
+	}
+	return inner.Initialize(srcs)
+}
+
 type AccessToken string
 
 func (token AccessToken) ParseBearerToken() (string, error) {
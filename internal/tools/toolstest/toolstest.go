@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolstest provides a fake tool for the tests of configs wrapping
+// or invoking other tools.
+package toolstest
+
+import (
+	"context"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// Kind is the kind of the fake tool.
+const Kind = "fake"
+
+// Config initializes a Tool returning Result and Err, or the result of
+// InvokeFunc if set.
+type Config struct {
+	Name         string
+	Parameters   tools.Parameters
+	AuthRequired []string
+	Result       any
+	Err          error
+	// InvokeFunc, if set, is invoked instead of returning Result and Err.
+	InvokeFunc func(ctx context.Context, params tools.ParamValues) (any, error) `json:"-"`
+	// Calls, if set, counts the invocations of the tool.
+	Calls *int `json:"-"`
+	// RowFilters is reported by SupportsRowFilters.
+	RowFilters bool
+}
+
+var _ tools.ToolConfig = Config{}
+
+func (c Config) ToolConfigKind() string { return Kind }
+
+// SupportsRowFilters reports whether the tool is declared to apply the row
+// filters of data policies.
+func (c Config) SupportsRowFilters() bool { return c.RowFilters }
+
+func (c Config) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return Tool{cfg: c}, nil
+}
+
+// Tool is the fake tool initialized by Config.
+type Tool struct {
+	cfg Config
+}
+
+var _ tools.Tool = Tool{}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, _ tools.AccessToken) (any, error) {
+	if t.cfg.Calls != nil {
+		*t.cfg.Calls++
+	}
+	if t.cfg.InvokeFunc != nil {
+		return t.cfg.InvokeFunc(ctx, params)
+	}
+	return t.cfg.Result, t.cfg.Err
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.cfg.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return tools.Manifest{Parameters: t.cfg.Parameters.Manifest(), AuthRequired: t.cfg.AuthRequired}
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return tools.GetMcpManifest(t.cfg.Name, "", t.cfg.AuthRequired, t.cfg.Parameters)
+}
+
+func (t Tool) Authorized(verified []string) bool {
+	return tools.IsAuthorized(t.cfg.AuthRequired, verified)
+}
+
+func (t Tool) RequiresClientAuthorization() bool { return false }
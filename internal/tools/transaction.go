@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+)
+
+// IsolationLevel is the isolation level of the transaction of a transaction
+// tool. An empty level uses the default level of the database.
+type IsolationLevel string
+
+const (
+	IsolationReadUncommitted IsolationLevel = "readUncommitted"
+	IsolationReadCommitted   IsolationLevel = "readCommitted"
+	IsolationRepeatableRead  IsolationLevel = "repeatableRead"
+	IsolationSerializable    IsolationLevel = "serializable"
+)
+
+// Enum returns the values allowed for an IsolationLevel.
+func (IsolationLevel) Enum() []string {
+	return []string{
+		string(IsolationReadUncommitted), string(IsolationReadCommitted),
+		string(IsolationRepeatableRead), string(IsolationSerializable),
+	}
+}
+
+func (l *IsolationLevel) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var level string
+	if err := unmarshal(&level); err != nil {
+		return fmt.Errorf("error unmarshalling isolation level: %s", err)
+	}
+	if !slices.Contains(IsolationLevel("").Enum(), level) {
+		return fmt.Errorf("%q is not a valid isolation level, must be one of %q", level, IsolationLevel("").Enum())
+	}
+	*l = IsolationLevel(level)
+	return nil
+}
+
+// SQLIsolationLevel returns the database/sql isolation level of l.
+func (l IsolationLevel) SQLIsolationLevel() sql.IsolationLevel {
+	switch l {
+	case IsolationReadUncommitted:
+		return sql.LevelReadUncommitted
+	case IsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case IsolationSerializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
+
+// TransactionStatement is a statement executed by a transaction tool.
+type TransactionStatement struct {
+	Statement string `yaml:"statement" validate:"required"`
+	// Parameters are the names of the tool parameters bound, in order, to
+	// the placeholders of the statement. Defaults to every tool parameter.
+	Parameters []string `yaml:"parameters"`
+}
+
+// CheckTransactionStatements checks that there is at least one statement, and
+// that every parameter named by a statement is a parameter of the tool.
+func CheckTransactionStatements(statements []TransactionStatement, params Parameters) error {
+	if len(statements) == 0 {
+		return fmt.Errorf("a transaction requires at least one statement")
+	}
+	for i, s := range statements {
+		if s.Statement == "" {
+			return fmt.Errorf("statement %d is empty", i)
+		}
+		for _, n := range s.Parameters {
+			if !slices.ContainsFunc(params, func(p Parameter) bool { return p.GetName() == n }) {
+				return fmt.Errorf("parameter %q of statement %d is not a parameter of the tool", n, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Args returns the arguments of the statement from the values of the
+// parameters of the tool.
+func (s TransactionStatement) Args(params Parameters, paramsMap map[string]any) ([]any, error) {
+	if s.Parameters == nil {
+		values, err := GetParams(params, paramsMap)
+		if err != nil {
+			return nil, err
+		}
+		return values.AsSlice(), nil
+	}
+	args := make([]any, 0, len(s.Parameters))
+	for _, n := range s.Parameters {
+		v, ok := paramsMap[n]
+		if !ok {
+			return nil, fmt.Errorf("missing parameter %s", n)
+		}
+		args = append(args, v)
+	}
+	return args, nil
+}
+
+// StatementResult is the result of a statement of a transaction.
+type StatementResult struct {
+	RowsAffected int64 `json:"rowsAffected"`
+}
+
+// TransactionResult is the result of a committed transaction: the rows
+// affected by each statement, and the rows returned by the last statement
+// returning rows, such as a SELECT or an INSERT ... RETURNING.
+type TransactionResult struct {
+	Statements []StatementResult `json:"statements"`
+	Rows       []any             `json:"rows,omitempty"`
+}
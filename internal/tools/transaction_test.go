@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestTransactionStatementArgs(t *testing.T) {
+	params := tools.Parameters{
+		tools.NewIntParameter("id", ""),
+		tools.NewStringParameter("sku", ""),
+	}
+	values := map[string]any{"id": 1, "sku": "abc"}
+	tcs := []struct {
+		desc string
+		stmt tools.TransactionStatement
+		want []any
+	}{
+		{desc: "every parameter by default", stmt: tools.TransactionStatement{Statement: "s"}, want: []any{1, "abc"}},
+		{desc: "named parameters in order", stmt: tools.TransactionStatement{Statement: "s", Parameters: []string{"sku", "id", "sku"}}, want: []any{"abc", 1, "abc"}},
+		{desc: "no parameters", stmt: tools.TransactionStatement{Statement: "s", Parameters: []string{}}, want: []any{}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tools.CheckTransactionStatements([]tools.TransactionStatement{tc.stmt}, params); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := tc.stmt.Args(params, values)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	err := tools.CheckTransactionStatements(nil, params)
+	if err == nil || !strings.Contains(err.Error(), "at least one statement") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. The supported subset is the
+// root `$`, child names (`.name` or `['name']`), array indexes (`[0]`, or
+// `[-1]` from the end) and wildcards (`.*` or `[*]`).
+type jsonPath []pathSegment
+
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath compiles the JSONPath expression expr.
+func parseJSONPath(expr string) (jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with '$'", expr)
+	}
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty name", expr)
+			}
+			if name == "*" {
+				path = append(path, pathSegment{wildcard: true})
+			} else {
+				path = append(path, pathSegment{key: name})
+			}
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ']'", expr)
+			}
+			sel := rest[1:end]
+			rest = rest[end+1:]
+			if sel == "*" {
+				path = append(path, pathSegment{wildcard: true})
+				continue
+			}
+			if len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0] {
+				path = append(path, pathSegment{key: sel[1 : len(sel)-1]})
+				continue
+			}
+			i, err := strconv.Atoi(sel)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: unsupported selector %q", expr, sel)
+			}
+			path = append(path, pathSegment{index: i, isIndex: true})
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, rest)
+		}
+	}
+	return path, nil
+}
+
+// eval returns the value selected by the path in v, which must only contain
+// the types of decoded JSON. Missing values are nil. A path with a wildcard
+// returns the list of every selected value.
+func (p jsonPath) eval(v any) any {
+	nodes := []any{v}
+	multi := false
+	for _, seg := range p {
+		var next []any
+		for _, n := range nodes {
+			switch {
+			case seg.wildcard:
+				switch n := n.(type) {
+				case []any:
+					next = append(next, n...)
+				case map[string]any:
+					for _, k := range slices.Sorted(maps.Keys(n)) {
+						next = append(next, n[k])
+					}
+				}
+			case seg.isIndex:
+				l, ok := n.([]any)
+				if !ok {
+					continue
+				}
+				i := seg.index
+				if i < 0 {
+					i += len(l)
+				}
+				if i >= 0 && i < len(l) {
+					next = append(next, l[i])
+				}
+			default:
+				m, ok := n.(map[string]any)
+				if !ok {
+					continue
+				}
+				if c, ok := m[seg.key]; ok {
+					next = append(next, c)
+				}
+			}
+		}
+		multi = multi || seg.wildcard
+		nodes = next
+	}
+	if multi {
+		if nodes == nil {
+			return []any{}
+		}
+		return nodes
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
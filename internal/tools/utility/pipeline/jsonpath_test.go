@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONPath(t *testing.T) {
+	data := map[string]any{
+		"steps": map[string]any{
+			"tables": []any{
+				map[string]any{"name": "orders", "rows": 10},
+				map[string]any{"name": "items", "rows": 20},
+			},
+			"my-step": "value",
+		},
+	}
+	tcs := []struct {
+		expr string
+		want any
+		err  string
+	}{
+		{expr: "$", want: data},
+		{expr: "$.steps.tables[0].name", want: "orders"},
+		{expr: "$.steps.tables[-1].rows", want: 20},
+		{expr: "$.steps.tables[*].name", want: []any{"orders", "items"}},
+		{expr: "$.steps.tables.*.rows", want: []any{10, 20}},
+		{expr: "$['steps'][\"my-step\"]", want: "value"},
+		{expr: "$.steps.missing.name", want: nil},
+		{expr: "$.steps.tables[5]", want: nil},
+		{expr: "$.steps.missing[*]", want: []any{}},
+		{expr: "steps", err: "must start with '$'"},
+		{expr: "$.steps..name", err: "empty name"},
+		{expr: "$.steps[name]", err: "unsupported selector"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.expr, func(t *testing.T) {
+			p, err := parseJSONPath(tc.expr)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, p.eval(data)); diff != "" {
+				t.Errorf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
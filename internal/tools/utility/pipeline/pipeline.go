@@ -0,0 +1,410 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+const kind string = "pipeline"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Steps        []StepConfig     `yaml:"steps" validate:"required"`
+	AuthRequired []string         `yaml:"authRequired"`
+}
+
+// StepConfig invokes a tool with parameters mapped from the parameters of the
+// pipeline and the results of the previous steps.
+//
+// Parameter values and the condition are evaluated against an object with the
+// `params` of the pipeline and the results of the previous `steps` by name.
+// Strings starting with `$` are JSONPath expressions selecting a value of the
+// object, strings containing `{{` are Go templates executed with the object,
+// and other values are used as is.
+type StepConfig struct {
+	Name   string         `yaml:"name" validate:"required"`
+	Tool   string         `yaml:"tool" validate:"required"`
+	Params map[string]any `yaml:"params"`
+	// If skips the step unless it evaluates to a value other than null,
+	// false, 0, "", "false" or an empty list or object.
+	If      string `yaml:"if"`
+	Timeout string `yaml:"timeout"`
+}
+
+// validate interfaces
+var _ tools.ComposedToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+// SupportsColumnMasks reports that the result columns cannot be masked, since
+// the results of the steps are nested in the result.
+func (cfg Config) SupportsColumnMasks() bool { return false }
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	return nil, fmt.Errorf("tool kind %q must be initialized with the tools it invokes", kind)
+}
+
+// ToolNames returns the tools invoked by the steps.
+func (cfg Config) ToolNames() []string {
+	var names []string
+	for _, s := range cfg.Steps {
+		if !slices.Contains(names, s.Tool) {
+			names = append(names, s.Tool)
+		}
+	}
+	return names
+}
+
+func (cfg Config) InitializeWithTools(_ map[string]sources.Source, toolsMap map[string]tools.Tool) (tools.Tool, error) {
+	if len(cfg.Steps) == 0 {
+		return nil, fmt.Errorf("a pipeline requires at least one step")
+	}
+	steps := make([]step, 0, len(cfg.Steps))
+	seen := make(map[string]bool)
+	for i, sc := range cfg.Steps {
+		if sc.Name == "" {
+			return nil, fmt.Errorf("step %d has no name", i)
+		}
+		if seen[sc.Name] {
+			return nil, fmt.Errorf("duplicate step name %q", sc.Name)
+		}
+		seen[sc.Name] = true
+
+		t, ok := toolsMap[sc.Tool]
+		if !ok {
+			return nil, fmt.Errorf("tool %q of step %q does not exist", sc.Tool, sc.Name)
+		}
+		s := step{name: sc.Name, toolName: sc.Tool, tool: t}
+		var err error
+		if s.params, err = compileValue(map[string]any(sc.Params)); err != nil {
+			return nil, fmt.Errorf("invalid params of step %q: %w", sc.Name, err)
+		}
+		if sc.If != "" {
+			if s.condition, err = compileValue(sc.If); err != nil {
+				return nil, fmt.Errorf("invalid condition of step %q: %w", sc.Name, err)
+			}
+		}
+		if sc.Timeout != "" {
+			s.timeout, err = time.ParseDuration(sc.Timeout)
+			if err != nil || s.timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout %q of step %q: must be a positive duration", sc.Timeout, sc.Name)
+			}
+		}
+		steps = append(steps, s)
+	}
+
+	mcpManifest := tools.GetMcpManifest(cfg.Name, cfg.Description, cfg.AuthRequired, cfg.Parameters)
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		AuthRequired: cfg.AuthRequired,
+		steps:        steps,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// valueFunc evaluates a compiled value against the params and step results.
+type valueFunc func(data map[string]any) (any, error)
+
+// compileValue compiles a step parameter value or condition.
+func compileValue(v any) (valueFunc, error) {
+	switch v := v.(type) {
+	case string:
+		switch {
+		case strings.HasPrefix(v, "$"):
+			path, err := parseJSONPath(v)
+			if err != nil {
+				return nil, err
+			}
+			return func(data map[string]any) (any, error) {
+				return path.eval(data), nil
+			}, nil
+		case strings.Contains(v, "{{"):
+			tmpl, err := template.New("").Option("missingkey=zero").Funcs(templateFuncs).Parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid template %q: %w", v, err)
+			}
+			return func(data map[string]any) (any, error) {
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, data); err != nil {
+					return nil, err
+				}
+				return buf.String(), nil
+			}, nil
+		}
+	case map[string]any:
+		fields := make(map[string]valueFunc, len(v))
+		for k, e := range v {
+			f, err := compileValue(e)
+			if err != nil {
+				return nil, err
+			}
+			fields[k] = f
+		}
+		return func(data map[string]any) (any, error) {
+			out := make(map[string]any, len(fields))
+			for k, f := range fields {
+				e, err := f(data)
+				if err != nil {
+					return nil, err
+				}
+				out[k] = e
+			}
+			return out, nil
+		}, nil
+	case []any:
+		items := make([]valueFunc, 0, len(v))
+		for _, e := range v {
+			f, err := compileValue(e)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, f)
+		}
+		return func(data map[string]any) (any, error) {
+			out := make([]any, 0, len(items))
+			for _, f := range items {
+				e, err := f(data)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, e)
+			}
+			return out, nil
+		}, nil
+	}
+	return func(map[string]any) (any, error) { return v, nil }, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// normalize converts v to the types of decoded JSON, which JSONPath
+// expressions and the parameters of tools expect.
+func normalize(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := util.DecodeJSON(bytes.NewReader(b), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// truthy reports whether the value of a condition runs its step.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false" && v != "0" && v != "<no value>"
+	case json.Number:
+		f, err := v.Float64()
+		return err != nil || f != 0
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() > 0
+	}
+	return true
+}
+
+type step struct {
+	name      string
+	toolName  string
+	tool      tools.Tool
+	params    valueFunc
+	condition valueFunc
+	timeout   time.Duration
+}
+
+// StepResult is the result of a step of the combined result of a pipeline.
+type StepResult struct {
+	Name    string `json:"name"`
+	Tool    string `json:"tool"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Result  any    `json:"result,omitempty"`
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string
+	Kind         string
+	Parameters   tools.Parameters
+	AuthRequired []string
+
+	steps       []step
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues, accessToken tools.AccessToken) (any, error) {
+	paramsMap, err := normalize(params.AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert parameters: %w", err)
+	}
+	stepResults := make(map[string]any)
+	data := map[string]any{"params": paramsMap, "steps": stepResults}
+	claims := util.ClaimsFromContext(ctx)
+
+	results := make([]StepResult, 0, len(t.steps))
+	for _, s := range t.steps {
+		if s.condition != nil {
+			v, err := s.condition(data)
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate condition of step %q: %w", s.name, err)
+			}
+			if !truthy(v) {
+				results = append(results, StepResult{Name: s.name, Tool: s.toolName, Skipped: true})
+				continue
+			}
+		}
+		res, err := s.invoke(ctx, data, claims, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		stepResults[s.name] = res
+		results = append(results, StepResult{Name: s.name, Tool: s.toolName, Result: res})
+	}
+	return results, nil
+}
+
+// invoke runs the tool of the step with its parameters evaluated against data,
+// and returns its normalized result.
+func (s step) invoke(ctx context.Context, data map[string]any, claims map[string]map[string]any, accessToken tools.AccessToken) (any, error) {
+	v, err := s.params(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate params of step %q: %w", s.name, err)
+	}
+	v, err = normalize(v)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert params of step %q: %w", s.name, err)
+	}
+	args, _ := v.(map[string]any)
+	if args == nil {
+		args = make(map[string]any)
+	}
+	pv, err := s.tool.ParseParams(args, claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid params of step %q: %w", s.name, err)
+	}
+
+	stepCtx := ctx
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	res, err := s.tool.Invoke(stepCtx, pv, accessToken)
+	if err != nil {
+		// the pipeline being cancelled or timing out is not the fault of
+		// the step, so it is reported as is
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if s.timeout > 0 && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("step %q timed out after %s: %w", s.name, s.timeout, err)
+		}
+		return nil, fmt.Errorf("step %q failed: %w", s.name, err)
+	}
+	res, err = normalize(res)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert result of step %q: %w", s.name, err)
+	}
+	return res, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+// Authorized requires the auth services of the pipeline and of every tool it
+// invokes.
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	if !tools.IsAuthorized(t.AuthRequired, verifiedAuthServices) {
+		return false
+	}
+	for _, s := range t.steps {
+		if !s.tool.Authorized(verifiedAuthServices) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t Tool) RequiresClientAuthorization() bool {
+	for _, s := range t.steps {
+		if s.tool.RequiresClientAuthorization() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,382 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/toolstest"
+	"github.com/googleapis/genai-toolbox/internal/util"
+
+	pipeline "github.com/googleapis/genai-toolbox/internal/tools/utility/pipeline"
+)
+
+func TestParseFromYamlPipeline(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		explore:
+			kind: pipeline
+			description: some description
+			parameters:
+				- name: schema
+				  type: string
+				  description: the schema
+			steps:
+				- name: tables
+				  tool: list_tables
+				  params:
+				    schema: $.params.schema
+				  timeout: 10s
+				- name: describe
+				  tool: describe_table
+				  if: "{{ len .steps.tables }}"
+				  params:
+				    table: "{{ .params.schema }}.orders"
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"explore": pipeline.Config{
+			Name:        "explore",
+			Kind:        "pipeline",
+			Description: "some description",
+			Parameters: tools.Parameters{
+				tools.NewStringParameter("schema", "the schema"),
+			},
+			Steps: []pipeline.StepConfig{
+				{Name: "tables", Tool: "list_tables", Params: map[string]any{"schema": "$.params.schema"}, Timeout: "10s"},
+				{Name: "describe", Tool: "describe_table", If: "{{ len .steps.tables }}", Params: map[string]any{"table": "{{ .params.schema }}.orders"}},
+			},
+			AuthRequired: []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func initializeTools(t *testing.T, toolsFile string, fakes map[string]toolstest.Config) (map[string]tools.Tool, error) {
+	t.Helper()
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(toolsFile), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	for name, c := range fakes {
+		got.Tools[name] = c
+	}
+	_, _, toolsMap, _, err := server.InitializeConfigs(ctx, server.ServerConfig{Version: "0.0.0", ToolConfigs: got.Tools})
+	return toolsMap, err
+}
+
+func invoke(t *testing.T, tool tools.Tool, data map[string]any) (any, error) {
+	t.Helper()
+	params, err := tool.ParseParams(data, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return tool.Invoke(context.Background(), params, "")
+}
+
+func TestInvoke(t *testing.T) {
+	var sampled []map[string]any
+	fakes := map[string]toolstest.Config{
+		"list_tables": {
+			Parameters: tools.Parameters{tools.NewStringParameter("schema", "")},
+			InvokeFunc: func(_ context.Context, params tools.ParamValues) (any, error) {
+				return []any{map[string]any{"name": params.AsMap()["schema"].(string) + ".orders"}, map[string]any{"name": "items"}}, nil
+			},
+		},
+		"describe_table": {
+			Parameters: tools.Parameters{tools.NewStringParameter("table", "")},
+			InvokeFunc: func(_ context.Context, params tools.ParamValues) (any, error) {
+				return map[string]any{"table": params.AsMap()["table"], "columns": 3}, nil
+			},
+		},
+		"sample_rows": {
+			Parameters: tools.Parameters{tools.NewStringParameter("table", ""), tools.NewIntParameter("limit", "")},
+			InvokeFunc: func(_ context.Context, params tools.ParamValues) (any, error) {
+				sampled = append(sampled, params.AsMap())
+				return []any{"row"}, nil
+			},
+		},
+	}
+	toolsMap, err := initializeTools(t, `
+	tools:
+		explore:
+			kind: pipeline
+			description: explore a schema
+			parameters:
+				- name: schema
+				  type: string
+				  description: the schema
+				- name: sample
+				  type: boolean
+				  description: whether to sample rows
+			steps:
+				- name: tables
+				  tool: list_tables
+				  params:
+				    schema: $.params.schema
+				- name: describe
+				  tool: describe_table
+				  params:
+				    table: $.steps.tables[0].name
+				- name: sample
+				  tool: sample_rows
+				  if: $.params.sample
+				  params:
+				    table: "{{ .steps.describe.table }}"
+				    limit: 5
+	`, fakes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	res, err := invoke(t, toolsMap["explore"], map[string]any{"schema": "sales", "sample": false})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `[{"name":"tables","tool":"list_tables","result":[{"name":"sales.orders"},{"name":"items"}]},` +
+		`{"name":"describe","tool":"describe_table","result":{"columns":3,"table":"sales.orders"}},` +
+		`{"name":"sample","tool":"sample_rows","skipped":true}]`
+	if string(b) != want {
+		t.Errorf("unexpected result:\ngot  %s\nwant %s", b, want)
+	}
+	if len(sampled) != 0 {
+		t.Errorf("expected skipped step not to be invoked, got %v", sampled)
+	}
+
+	if _, err := invoke(t, toolsMap["explore"], map[string]any{"schema": "sales", "sample": true}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]map[string]any{{"table": "sales.orders", "limit": 5}}, sampled); diff != "" {
+		t.Errorf("unexpected params of conditional step (-want +got):\n%s", diff)
+	}
+}
+
+func TestInvokeTimeout(t *testing.T) {
+	fakes := map[string]toolstest.Config{
+		"slow": {
+			InvokeFunc: func(ctx context.Context, _ tools.ParamValues) (any, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+	}
+	toolsMap, err := initializeTools(t, `
+	tools:
+		wait_for_it:
+			kind: pipeline
+			description: waits
+			steps:
+				- name: slow
+				  tool: slow
+				  timeout: 10ms
+	`, fakes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = invoke(t, toolsMap["wait_for_it"], map[string]any{})
+	if err == nil || !strings.Contains(err.Error(), `step "slow" timed out after 10ms`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInvokeParentDeadline(t *testing.T) {
+	fakes := map[string]toolstest.Config{
+		"slow": {
+			InvokeFunc: func(ctx context.Context, _ tools.ParamValues) (any, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+	}
+	toolsMap, err := initializeTools(t, `
+	tools:
+		no_timeout:
+			kind: pipeline
+			description: waits
+			steps:
+				- name: slow
+				  tool: slow
+		long_timeout:
+			kind: pipeline
+			description: waits
+			steps:
+				- name: slow
+				  tool: slow
+				  timeout: 1m
+	`, fakes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, name := range []string{"no_timeout", "long_timeout"} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			// the deadline of the pipeline is not blamed on the step
+			_, err := toolsMap[name].Invoke(ctx, tools.ParamValues{}, "")
+			if !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timed out") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorized(t *testing.T) {
+	fakes := map[string]toolstest.Config{
+		"open":    {},
+		"private": {AuthRequired: []string{"my-auth"}},
+	}
+	toolsMap, err := initializeTools(t, `
+	tools:
+		combined:
+			kind: pipeline
+			description: combined
+			steps:
+				- name: a
+				  tool: open
+				- name: b
+				  tool: private
+	`, fakes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if toolsMap["combined"].Authorized(nil) {
+		t.Errorf("expected pipeline to require the auth services of its tools")
+	}
+	if !toolsMap["combined"].Authorized([]string{"my-auth"}) {
+		t.Errorf("expected pipeline to be authorized")
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing tool",
+			in: `
+			tools:
+				p:
+					kind: pipeline
+					description: d
+					steps:
+						- name: a
+						  tool: missing
+			`,
+			err: `invoked tool "missing" does not exist`,
+		},
+		{
+			desc: "cycle",
+			in: `
+			tools:
+				p:
+					kind: pipeline
+					description: d
+					steps:
+						- name: a
+						  tool: q
+				q:
+					kind: pipeline
+					description: d
+					steps:
+						- name: a
+						  tool: p
+			`,
+			err: "invokes itself",
+		},
+		{
+			desc: "duplicate step",
+			in: `
+			tools:
+				p:
+					kind: pipeline
+					description: d
+					steps:
+						- name: a
+						  tool: fake
+						- name: a
+						  tool: fake
+			`,
+			err: `duplicate step name "a"`,
+		},
+		{
+			desc: "invalid JSONPath",
+			in: `
+			tools:
+				p:
+					kind: pipeline
+					description: d
+					steps:
+						- name: a
+						  tool: fake
+						  params:
+						    x: $.steps[
+			`,
+			err: "missing ']'",
+		},
+	}
+	fakes := map[string]toolstest.Config{
+		"fake": {},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := initializeTools(t, tc.in, fakes)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
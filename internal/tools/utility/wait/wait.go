@@ -54,6 +54,10 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// OwnsTimeout reports that the tool decodes `timeout`, the duration it waits
+// for at most.
+func (cfg Config) OwnsTimeout() bool { return true }
+
 func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
 	durationParameter := tools.NewStringParameter("duration", "The duration to wait for, specified as a string (e.g., '10s', '2m', '1h').")
 	parameters := tools.Parameters{durationParameter}
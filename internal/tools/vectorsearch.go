@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// VectorDistance is the function ranking the rows of a vector search tool by
+// the distance between their embedding and the embedding of the query.
+type VectorDistance string
+
+const (
+	VectorDistanceCosine VectorDistance = "cosine"
+	VectorDistanceL2     VectorDistance = "l2"
+)
+
+// Enum returns the values allowed for a VectorDistance.
+func (VectorDistance) Enum() []string {
+	return []string{string(VectorDistanceCosine), string(VectorDistanceL2)}
+}
+
+func (d *VectorDistance) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	var distance string
+	if err := unmarshal(&distance); err != nil {
+		return fmt.Errorf("error unmarshalling vector distance: %s", err)
+	}
+	if !slices.Contains(VectorDistance("").Enum(), distance) {
+		return fmt.Errorf("%q is not a valid vector distance, must be one of %q", distance, VectorDistance("").Enum())
+	}
+	*d = VectorDistance(distance)
+	return nil
+}
+
+const (
+	// VectorQueryParameterName is the parameter of vector search tools
+	// taking the text to search for.
+	VectorQueryParameterName = "query"
+	// VectorTopKParameterName is the parameter of vector search tools taking
+	// the number of rows to return.
+	VectorTopKParameterName = "topK"
+	// VectorScoreColumn is the column of the score of the rows returned by
+	// vector search tools.
+	VectorScoreColumn = "score"
+
+	defaultVectorTopK    = 5
+	defaultVectorMaxTopK = 100
+)
+
+// VectorSearch is the search run by a vector search tool: the rows of Table
+// whose EmbeddingColumn is closest to the embedding of the query, matching
+// the Filters parameters on the columns they are named after.
+type VectorSearch struct {
+	Table           string
+	EmbeddingColumn string
+	Columns         []string
+	Distance        VectorDistance
+	TopK            int
+	MaxTopK         int
+	Filters         Parameters
+}
+
+// Resolve checks s and applies its defaults.
+func (s VectorSearch) Resolve() (VectorSearch, error) {
+	if s.Table == "" || s.EmbeddingColumn == "" || len(s.Columns) == 0 {
+		return s, fmt.Errorf("a vector search requires a table, an embedding column and columns")
+	}
+	if s.Distance == "" {
+		s.Distance = VectorDistanceCosine
+	}
+	if s.TopK == 0 {
+		s.TopK = defaultVectorTopK
+	}
+	if s.MaxTopK == 0 {
+		s.MaxTopK = max(s.TopK, defaultVectorMaxTopK)
+	}
+	if s.TopK < 0 || s.MaxTopK < s.TopK {
+		return s, fmt.Errorf("invalid topK %d: must be positive and at most maxTopK %d", s.TopK, s.MaxTopK)
+	}
+	for i, c := range s.Columns {
+		if c == VectorScoreColumn || slices.Contains(s.Columns[:i], c) {
+			return s, fmt.Errorf("invalid column %q: columns must be unique and not %q", c, VectorScoreColumn)
+		}
+	}
+	for i, p := range s.Filters {
+		n := p.GetName()
+		if n == VectorQueryParameterName || n == VectorTopKParameterName || slices.ContainsFunc(s.Filters[:i], func(q Parameter) bool { return q.GetName() == n }) {
+			return s, fmt.Errorf("invalid filter %q: filters must be unique and not %q or %q", n, VectorQueryParameterName, VectorTopKParameterName)
+		}
+	}
+	return s, nil
+}
+
+// Parameters returns the parameters of the tool: the text to search for, the
+// number of rows to return and the filters.
+func (s VectorSearch) Parameters() Parameters {
+	return append(Parameters{
+		NewStringParameter(VectorQueryParameterName, "The text to search for. Rows are ranked by the similarity of their meaning to the text."),
+		NewIntParameterWithDefault(VectorTopKParameterName, s.TopK, fmt.Sprintf("The number of rows to return, at most %d.", s.MaxTopK)),
+	}, s.Filters...)
+}
+
+// VectorQuery is a vector search requested by an invocation.
+type VectorQuery struct {
+	Text    string
+	TopK    int
+	Filters []RowFilter
+}
+
+// Query returns the vector search requested by params. Filters without a
+// value are not applied.
+func (s VectorSearch) Query(params ParamValues) (VectorQuery, error) {
+	paramsMap := params.AsMap()
+	text, _ := paramsMap[VectorQueryParameterName].(string)
+	if strings.TrimSpace(text) == "" {
+		return VectorQuery{}, fmt.Errorf("parameter %q must not be empty", VectorQueryParameterName)
+	}
+	topK := s.TopK
+	if k, ok := paramsMap[VectorTopKParameterName].(int); ok {
+		topK = k
+	}
+	if topK <= 0 || topK > s.MaxTopK {
+		return VectorQuery{}, fmt.Errorf("parameter %q must be between 1 and %d", VectorTopKParameterName, s.MaxTopK)
+	}
+	q := VectorQuery{Text: text, TopK: topK}
+	for _, p := range s.Filters {
+		if v := paramsMap[p.GetName()]; v != nil {
+			q.Filters = append(q.Filters, RowFilter{Column: p.GetName(), Value: v})
+		}
+	}
+	return q, nil
+}
+
+// VectorDialect is the SQL of a kind of vector search tool.
+type VectorDialect struct {
+	// Placeholder returns the placeholder of the nth argument, starting at 1.
+	Placeholder func(n int) string
+	// Vector returns the expression of the query vector, bound to the
+	// placeholder as a string such as "[0.1,0.2]".
+	Vector func(placeholder string) string
+	// Score returns the score of a row, and Order the expression ranking the
+	// rows from best to worst, given the embedding column and the expression
+	// of the query vector.
+	Score func(column, vector string) string
+	Order func(column, vector string) string
+}
+
+// Statement returns the statement of q, searching for the rows closest to
+// vector, with the arguments of its placeholders. Rows are also restricted by
+// filters, such as the row filters of data policies.
+func (s VectorSearch) Statement(d VectorDialect, q VectorQuery, vector []float32, filters []RowFilter) (string, []any) {
+	args := []any{FormatVector(vector)}
+	vec := d.Vector(d.Placeholder(1))
+	col := QuoteIdentifier(s.EmbeddingColumn)
+
+	columns := make([]string, 0, len(s.Columns)+1)
+	for _, c := range s.Columns {
+		columns = append(columns, QuoteIdentifier(c))
+	}
+	columns = append(columns, d.Score(col, vec)+" AS "+QuoteIdentifier(VectorScoreColumn))
+
+	where := col + " IS NOT NULL"
+	if preds, predArgs := FilterPredicates(append(slices.Clone(q.Filters), filters...), args, d.Placeholder, QuoteIdentifier); preds != "" {
+		where += " AND " + preds
+		args = predArgs
+	}
+	statement := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d",
+		strings.Join(columns, ", "), QuoteQualifiedIdentifier(s.Table), where, d.Order(col, vec), q.TopK)
+	return statement, args
+}
+
+// FormatVector formats v as a list of numbers in brackets, such as
+// "[0.1,0.2]", the text form of vectors in SAP HANA and pgvector.
+func FormatVector(v []float32) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestVectorSearchResolve(t *testing.T) {
+	base := tools.VectorSearch{Table: "docs", EmbeddingColumn: "embedding", Columns: []string{"id", "body"}}
+	got, err := base.Resolve()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Distance != tools.VectorDistanceCosine || got.TopK != 5 || got.MaxTopK != 100 {
+		t.Errorf("incorrect defaults: got %+v", got)
+	}
+
+	tcs := []struct {
+		desc   string
+		modify func(*tools.VectorSearch)
+		want   string
+	}{
+		{
+			desc:   "missing table",
+			modify: func(s *tools.VectorSearch) { s.Table = "" },
+			want:   "requires a table",
+		},
+		{
+			desc:   "topK above maxTopK",
+			modify: func(s *tools.VectorSearch) { s.TopK, s.MaxTopK = 10, 5 },
+			want:   "invalid topK 10",
+		},
+		{
+			desc:   "score column",
+			modify: func(s *tools.VectorSearch) { s.Columns = []string{"id", "score"} },
+			want:   `invalid column "score"`,
+		},
+		{
+			desc: "filter named after a parameter",
+			modify: func(s *tools.VectorSearch) {
+				s.Filters = tools.Parameters{tools.NewStringParameter("query", "")}
+			},
+			want: `invalid filter "query"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := base
+			tc.modify(&s)
+			_, err := s.Resolve()
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVectorSearchStatement(t *testing.T) {
+	s, err := tools.VectorSearch{
+		Table:           "app.docs",
+		EmbeddingColumn: "embedding",
+		Columns:         []string{"id", "body"},
+		MaxTopK:         20,
+		Filters: tools.Parameters{
+			tools.NewStringParameterWithRequired("lang", "", false),
+			tools.NewStringParameterWithRequired("category", "", false),
+		},
+	}.Resolve()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tools.ParseParams(s.Parameters(), map[string]any{"query": "refund policy", "topK": 3, "lang": "en"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	q, err := s.Query(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantQuery := tools.VectorQuery{Text: "refund policy", TopK: 3, Filters: []tools.RowFilter{{Column: "lang", Value: "en"}}}
+	if diff := cmp.Diff(wantQuery, q); diff != "" {
+		t.Fatalf("incorrect query: diff %v", diff)
+	}
+
+	dialect := tools.VectorDialect{
+		Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+		Vector:      func(p string) string { return p + "::vector" },
+		Score:       func(col, vec string) string { return "1 - (" + col + " <=> " + vec + ")" },
+		Order:       func(col, vec string) string { return col + " <=> " + vec },
+	}
+	got, gotArgs := s.Statement(dialect, q, []float32{0.5, -0.25}, []tools.RowFilter{{Column: "tenant", Value: []any{"a", "b"}}})
+	want := `SELECT "id", "body", 1 - ("embedding" <=> $1::vector) AS "score" FROM "app"."docs" ` +
+		`WHERE "embedding" IS NOT NULL AND "lang" = $2 AND "tenant" IN ($3, $4) ` +
+		`ORDER BY "embedding" <=> $1::vector LIMIT 3`
+	if got != want {
+		t.Errorf("incorrect statement: got %q, want %q", got, want)
+	}
+	if diff := cmp.Diff([]any{"[0.5,-0.25]", "en", "a", "b"}, gotArgs); diff != "" {
+		t.Errorf("incorrect args: diff %v", diff)
+	}
+}
+
+func TestVectorSearchQueryErrors(t *testing.T) {
+	s, err := tools.VectorSearch{Table: "docs", EmbeddingColumn: "embedding", Columns: []string{"id"}, MaxTopK: 10}.Resolve()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		data map[string]any
+		want string
+	}{
+		{desc: "empty query", data: map[string]any{"query": "  "}, want: `parameter "query" must not be empty`},
+		{desc: "topK too large", data: map[string]any{"query": "a", "topK": 11}, want: `parameter "topK" must be between 1 and 10`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tools.ParseParams(s.Parameters(), tc.data, nil)
+			if err != nil {
+				t.Fatalf("unable to parse params: %s", err)
+			}
+			if _, err := s.Query(params); err == nil || err.Error() != tc.want {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -162,3 +163,32 @@ func InstrumentationFromContext(ctx context.Context) (*telemetry.Instrumentation
 	}
 	return nil, fmt.Errorf("unable to retrieve instrumentation")
 }
+
+const claimsKey contextKey = "claims"
+
+// WithClaims adds the claims of the verified auth services of a request into
+// the context as a value
+func WithClaims(ctx context.Context, claims map[string]map[string]any) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext retrieves the claims of the verified auth services, keyed
+// by auth service name. It returns nil if no claims were verified.
+func ClaimsFromContext(ctx context.Context) map[string]map[string]any {
+	claims, _ := ctx.Value(claimsKey).(map[string]map[string]any)
+	return claims
+}
+
+const headerKey contextKey = "header"
+
+// WithHeader adds the header of a request into the context as a value
+func WithHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, headerKey, header)
+}
+
+// HeaderFromContext retrieves the header of a request. It returns nil if the
+// request has no header, such as requests over STDIO.
+func HeaderFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerKey).(http.Header)
+	return header
+}